@@ -1,37 +1,98 @@
 package commands
 
-// Execute выполняет команду приложения
+import (
+	"context"
+	"errors"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/input"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/log"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/state"
+)
+
+// Execute выполняет команду приложения. Выполнение команды прерывается по SIGINT или SIGTERM. При ошибке в аргументах
+// командной строки в stderr выводится справка по использованию команды
 func Execute() error {
-	return cmdRoot.Execute()
+	ctx, cancel := signalContext(context.Background())
+	defer cancel()
+
+	cmd, err := cmdRoot.ExecuteContextC(ctx)
+
+	var usage *UsageError
+
+	if errors.As(err, &usage) {
+		cmd.SetOut(os.Stderr)
+		_ = cmd.Usage()
+	}
+
+	return log.RedactError(err)
 }
 
 func init() {
+	cmdRoot.PersistentFlags().StringVarP(&ConfigPath, "config", "", "",
+		"path to a project configuration file\nby default, dbmill.yaml in the working directory is used if it exists")
+	cmdRoot.PersistentFlags().StringVarP(&Profile, "profile", "p", "",
+		"name of a profile of the project configuration file (default $DBMILL_PROFILE)")
+	cmdRoot.PersistentFlags().StringArrayVarP(&Sensitive, "sensitive", "", nil,
+		"value to mask in logs and error messages, e.g. an access token\n"+
+			"passwords of database users are masked automatically")
+
+	connectionFlags(cmdScriptsFolder)
+	logFlags(cmdScriptsFolder)
+
 	cmdScriptsFolder.Flags().StringVarP(&Path, "path", "d", "",
-		"path to the directory where scripts will be created")
-	cmdScriptsFolder.Flags().StringVarP(&Database, "db", "D", "",
-		"database to create scripts")
+		"path to the directory where scripts will be created\n"+
+			"path to the output file for the script, tar and zip outputs")
 	cmdScriptsFolder.Flags().StringVarP(&DirStructFilename, "output-struct", "S", "",
 		"path to a file that describes a directory structure where the scripts will be created")
-	cmdScriptsFolder.Flags().StringVarP(&LogFilename, "log", "l", "",
-		"path to a log file")
-	cmdScriptsFolder.Flags().StringVarP(&LogLevel, "log-level", "L", "info",
-		"log level: trace, debug, info (default), warning, error, fatal, panic")
 	cmdScriptsFolder.Flags().StringVarP(&FilterPath, "filter-path", "F", "",
 		"path to a file that contains a list of objects for which scripts will be created\nreplaces --filter "+
 			"if it is empty")
 	cmdScriptsFolder.Flags().StringVarP(&ExcludePath, "exclude-path", "E", "",
 		"path to a file that contains a list of objects for which scripts don't need to be created\n"+
 			"replaces --exclude if it is empty")
-	cmdScriptsFolder.Flags().StringVarP(&Username, "username", "U", "",
-		"database username\nreplaces a username listed in a database connection string")
-	cmdScriptsFolder.Flags().StringVarP(&Password, "password", "P", "",
-		"database user password\nreplaces a password listed in a database connection string")
+	cmdScriptsFolder.Flags().StringVarP(&TargetVersion, "target-version", "", "",
+		"target server version of scripts (for SQL Server: 2016, 2017, 2019, 2022)\n"+
+			"scripts are adapted to the target, objects that cannot be expressed on it are reported")
+	cmdScriptsFolder.Flags().StringVarP(&Output, "output", "o", "folder",
+		"output of scripts: folder (default), script (single deployment script), tar, zip, stdout")
+	cmdScriptsFolder.Flags().StringVarP(&GitMessage, "git-message", "", "",
+		"subject of the commit message (used with --git-commit)")
+	cmdScriptsFolder.Flags().StringVarP(&GitAuthor, "git-author", "", "",
+		"author of the commit in the format \"Name <email>\" (used with --git-commit)\n"+
+			"by default, the author from the git configuration is used")
+	cmdScriptsFolder.Flags().StringVarP(&ScriptStyle, "script-style", "", "create",
+		"style of scripts: create (default), if-not-exists, create-or-alter, drop-create")
 
 	cmdScriptsFolder.Flags().StringArrayVarP(&Filter, "filter", "f", nil,
-		"names of objects for which scripts will be created\nregular expressions are permissible\n"+
-			"scripts will be created for all objects if the option is empty\nreplaces --filter-path")
+		"names of objects for which scripts will be created\nregular expressions and [!][type:]schema.name "+
+			"patterns are permissible\nscripts will be created for all objects if the option is empty\n"+
+			"replaces --filter-path")
 	cmdScriptsFolder.Flags().StringArrayVarP(&Exclude, "exclude", "e", nil,
-		"names of objects for which scripts do not need to be created\nreplaces --exclude-path")
+		"names of objects for which scripts do not need to be created\nregular expressions and "+
+			"[!][type:]schema.name patterns are permissible\nreplaces --exclude-path")
+	cmdScriptsFolder.Flags().BoolVarP(&WithDependencies, "with-dependencies", "", false,
+		"add objects that the filtered objects depend on (tables, types, views, functions and so on)")
+	cmdScriptsFolder.Flags().BoolVarP(&WithDependents, "with-dependents", "", false,
+		"add objects that depend on the filtered objects")
+	cmdScriptsFolder.Flags().StringVarP(&ModifiedSince, "modified-since", "", "",
+		"create scripts only for objects modified since the time, YYYY-MM-DD or YYYY-MM-DDThh:mm:ss\n"+
+			"the time is local to the database server")
+	cmdScriptsFolder.Flags().BoolVarP(&SinceLastRun, "since-last-run", "", false,
+		"create scripts only for objects modified since the last successful run for the database and output\n"+
+			"the time of the run is saved to the state file")
+	cmdScriptsFolder.Flags().StringVarP(&StateFile, "state-file", "", state.Filename,
+		"path to the state file used with --since-last-run")
+
+	cmdScriptsFolder.Flags().IntVarP(&Parallel, "parallel", "", 1,
+		"number of objects whose scripts are created and written concurrently")
+
+	cmdScriptsFolder.Flags().StringVarP(&ReportPath, "report", "", "",
+		"path to a JSON report of the command run")
+	cmdScriptsFolder.Flags().StringVarP(&JUnitReportPath, "junit-report", "", "",
+		"path to a JUnit XML report of the command run")
 
 	cmdScriptsFolder.Flags().BoolVarP(&Decrypt, "decrypt", "", false,
 		"decrypt objects")
@@ -39,6 +100,115 @@ func init() {
 		"save data in scripts")
 	cmdScriptsFolder.Flags().BoolVarP(&SkipPermissions, "skip-permissions", "", false,
 		"skip permissions")
+	cmdScriptsFolder.Flags().BoolVarP(&GitAutoCommit, "git-commit", "", false,
+		"commit changes of the scripts folder to the git repository that contains it")
+	cmdScriptsFolder.Flags().BoolVarP(&Prune, "prune", "", false,
+		"delete scripts of objects that no longer exist in the database\ncannot be used with object filters")
+	cmdScriptsFolder.Flags().BoolVarP(&FailFast, "fail-fast", "", false,
+		"stop at the first object whose script cannot be created or saved\n"+
+			"by default, all objects are processed and the failed ones are listed at the end")
+	cmdScriptsFolder.Flags().BoolVarP(&NoProgress, "no-progress", "", false,
+		"do not display the progress on stderr")
+
+	connectionFlags(cmdDependencies)
+	logFlags(cmdDependencies)
+
+	cmdDependencies.Flags().StringVarP(&OutputFile, "file", "", "",
+		"path to the output file, - or empty to write to stdout")
+	cmdDependencies.Flags().StringVarP(&GraphFormat, "format", "", "dot",
+		"format of the graph: dot (default), mermaid, json")
+	cmdDependencies.Flags().StringArrayVarP(&Roots, "root", "r", nil,
+		"[!][type:]schema.name pattern or regular expression of root objects of the graph\n"+
+			"the whole graph is exported if the option is empty")
+	cmdDependencies.Flags().IntVarP(&Depth, "depth", "", 0,
+		"maximum number of dependencies between a root object and an exported object (0 - no limit)")
+	cmdDependencies.Flags().StringVarP(&Direction, "direction", "", "dependencies",
+		"direction of the graph traversal from the root objects: dependencies (default), dependents, both")
 
-	cmdRoot.AddCommand(cmdScriptsFolder, cmdVersion)
+	connectionFlags(cmdERD)
+	logFlags(cmdERD)
+
+	cmdERD.Flags().StringVarP(&OutputFile, "file", "", "",
+		"path to the output file, - or empty to write to stdout")
+	cmdERD.Flags().StringVarP(&DiagramFormat, "format", "", "plantuml",
+		"format of the diagram: plantuml (default), mermaid, dot")
+	cmdERD.Flags().StringArrayVarP(&Schemas, "schema", "s", nil,
+		"schema of tables of the diagram\ntables of all schemas are included if the option is empty")
+	cmdERD.Flags().StringArrayVarP(&TableFilter, "table", "t", nil,
+		"[!][type:]schema.name pattern or regular expression of tables of the diagram")
+
+	cmdRoot.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		log.AddSecret(Password)
+
+		if value, ok := os.LookupEnv(input.PasswordEnv); ok {
+			log.AddSecret(value)
+		}
+
+		err := applyConfig(cmd, ConfigPath, profileName(os.LookupEnv), os.LookupEnv)
+
+		log.AddSecret(Sensitive...)
+		log.AddSecret(Password)
+
+		return log.RedactError(err)
+	}
+
+	cmdRoot.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return log.RedactError(usageError("%v", err))
+	})
+
+	cmdRoot.AddCommand(cmdScriptsFolder, cmdDependencies, cmdERD, cmdVersion)
+}
+
+// connectionFlags добавляет команде cmd флаги соединения с БД: строку соединения, учетные данные, параметры
+// соединения и таймауты
+func connectionFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&Database, "db", "D", "",
+		"connection string of the database")
+	cmd.Flags().StringVarP(&Username, "username", "U", "",
+		"database username\nreplaces a username listed in a database connection string")
+	cmd.Flags().StringVarP(&Password, "password", "P", "",
+		"database user password\nreplaces a password listed in a database connection string")
+	cmd.Flags().StringVarP(&PasswordFile, "password-file", "", "",
+		"path to a file whose first line is the database user password")
+	cmd.Flags().StringVarP(&PasswordCommand, "password-command", "", "",
+		"shell command whose first line of output is the database user password\n"+
+			"DBMILL_HOST, DBMILL_PORT, DBMILL_DATABASE and DBMILL_USERNAME are passed to the command")
+	cmd.Flags().StringVarP(&PassFile, "passfile", "", "",
+		"path to a credentials file with lines host:port:database:username:password (* matches any value)")
+	cmd.Flags().StringVarP(&Encrypt, "encrypt", "", "",
+		"encryption of the connection: disable, false (only login is encrypted), true (certificate is verified)\n"+
+			"by default, the value of the connection string is used")
+	cmd.Flags().StringVarP(&CAFile, "ca-file", "", "",
+		"path to a PEM file with certificates of authorities that issued the server certificate\n"+
+			"enables encryption if --encrypt is not specified")
+	cmd.Flags().StringVarP(&HostNameInCertificate, "host-name-in-certificate", "", "",
+		"host name in the server certificate if it differs from the host of the connection string")
+	cmd.Flags().StringVarP(&AppName, "app-name", "", "",
+		"application name passed to the server")
+	cmd.Flags().IntVarP(&PacketSize, "packet-size", "", 0,
+		"network packet size in bytes, 512-32767 (0 - default of the driver)")
+	cmd.Flags().DurationVarP(&Timeout, "timeout", "", 0,
+		"maximum duration of the command, e.g. 30m (0 - no limit)")
+	cmd.Flags().DurationVarP(&QueryTimeout, "query-timeout", "", 0,
+		"maximum duration of a single query to the server, including connection, e.g. 30s (0 - no limit)")
+	cmd.Flags().DurationVarP(&ConnectionTimeout, "connection-timeout", "", 0,
+		"maximum duration of connecting and logging in to the server, rounded up to seconds (0 - no limit)")
+	cmd.Flags().DurationVarP(&DialTimeout, "dial-timeout", "", 0,
+		"maximum duration of establishing a network connection, rounded up to seconds (0 - default of the driver)")
+	cmd.Flags().BoolVarP(&TrustServerCertificate, "trust-server-certificate", "", false,
+		"do not verify the server certificate")
+	cmd.Flags().BoolVarP(&PasswordStdin, "password-stdin", "", false,
+		"read the database user password from stdin")
+	cmd.Flags().BoolVarP(&NoPrompt, "no-prompt", "", false,
+		"do not prompt for a username and password on the terminal, fail if they are not specified")
+}
+
+// logFlags добавляет команде cmd флаги лога
+func logFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&LogFilename, "log", "l", "",
+		"path to a log file, - to write the log to stderr")
+	cmd.Flags().StringVarP(&LogLevel, "log-level", "L", "info",
+		"log level: trace, debug, info (default), warning, error, fatal, panic")
+	cmd.Flags().StringVar(&LogFormat, "log-format", "text",
+		"log format: text (default), json")
 }
@@ -0,0 +1,115 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/config"
+)
+
+// ProfileEnv переменная окружения с наименованием профиля конфигурации проекта, используемым, если не указан --profile
+const ProfileEnv = "DBMILL_PROFILE"
+
+// applyConfig устанавливает флагам команды cmd, не указанным в командной строке, значения профиля profile файла
+// конфигурации проекта path. Если путь не указан, то файл конфигурации ищется в рабочем каталоге, а при его
+// отсутствии флаги не изменяются. Функция lookup возвращает значение переменной окружения
+func applyConfig(cmd *cobra.Command, path, profile string, lookup func(name string) (string, bool)) error {
+	if strings.Trim(path, " ") == "" {
+		wd, err := os.Getwd()
+
+		if err != nil {
+			return err
+		}
+
+		path, err = config.Find(wd)
+
+		if errors.Is(err, config.ErrNotFound) {
+			if profile != "" {
+				return usageError("profile %s is specified, but %s is not found", profile, config.Filename)
+			}
+
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	cfg, err := config.LoadFile(path)
+
+	if err != nil {
+		return usageError("%v", err)
+	}
+
+	flags, err := cfg.Flags(profile)
+
+	if err != nil {
+		return usageError("%s: %v", path, err)
+	}
+
+	names := make([]string, 0, len(flags))
+
+	for name := range flags {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		if name == "config" || name == "profile" || !knownFlag(cmd.Root(), name) {
+			return usageError("%s: unknown option %s", path, name)
+		}
+
+		flag := cmd.Flags().Lookup(name)
+
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		for _, value := range flags[name] {
+			value, err = config.Expand(value, lookup)
+
+			if err != nil {
+				return usageError("%s: %s: %v", path, name, err)
+			}
+
+			if err = cmd.Flags().Set(name, value); err != nil {
+				return usageError("%s: %s: %v", path, name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// knownFlag проверяет, что флаг name есть у команды cmd или у одной из ее подкоманд
+func knownFlag(cmd *cobra.Command, name string) bool {
+	if cmd.Flags().Lookup(name) != nil || cmd.PersistentFlags().Lookup(name) != nil {
+		return true
+	}
+
+	for _, sub := range cmd.Commands() {
+		if knownFlag(sub, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// profileName возвращает наименование профиля конфигурации проекта: значение --profile или переменной окружения
+// DBMILL_PROFILE
+func profileName(lookup func(name string) (string, bool)) string {
+	if strings.Trim(Profile, " ") != "" {
+		return Profile
+	}
+
+	profile, _ := lookup(ProfileEnv)
+
+	return profile
+}
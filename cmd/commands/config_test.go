@@ -0,0 +1,97 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestApplyConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbmill-config")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "dbmill.yaml")
+
+	err = ioutil.WriteFile(path, []byte(`
+defaults:
+  path: scripts
+  filter: [dbo.*, rpt.*]
+profiles:
+  dev:
+    db: sqlserver://${DB_USER}@localhost
+    parallel: 4
+  broken:
+    dbname: Orders
+`), 0664)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lookup := func(name string) (string, bool) {
+		if name == "DB_USER" {
+			return "mill", true
+		}
+
+		return "", false
+	}
+
+	var db, scriptsPath string
+	var filter []string
+	var parallel int
+
+	newCommand := func() *cobra.Command {
+		root := &cobra.Command{Use: "root"}
+		cmd := &cobra.Command{Use: "scriptsfolder"}
+
+		cmd.Flags().StringVarP(&db, "db", "D", "", "")
+		cmd.Flags().StringVarP(&scriptsPath, "path", "d", "", "")
+		cmd.Flags().StringArrayVarP(&filter, "filter", "f", nil, "")
+		cmd.Flags().IntVarP(&parallel, "parallel", "", 1, "")
+
+		root.AddCommand(cmd)
+
+		return cmd
+	}
+
+	cmd := newCommand()
+
+	if err = cmd.Flags().Parse([]string{"--path", "other"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = applyConfig(cmd, path, "dev", lookup); err != nil {
+		t.Fatal(err)
+	}
+
+	if db != "sqlserver://mill@localhost" || scriptsPath != "other" || parallel != 4 ||
+		!reflect.DeepEqual(filter, []string{"dbo.*", "rpt.*"}) {
+		t.Errorf("unexpected flags: db %q, path %q, parallel %d, filter %v", db, scriptsPath, parallel, filter)
+	}
+
+	var cases = []struct {
+		profile string
+		lookup  func(name string) (string, bool)
+	}{
+		{profile: "prod", lookup: lookup},
+		{profile: "broken", lookup: lookup},
+		{profile: "dev", lookup: func(string) (string, bool) { return "", false }},
+	}
+
+	for _, test := range cases {
+		err = applyConfig(newCommand(), path, test.profile, test.lookup)
+
+		if ExitCode(err) != ExitUsage {
+			t.Errorf("profile %s: have %v, want a usage error", test.profile, err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine"
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/cmd/input"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/log"
+)
+
+// Credentials возвращает строку соединения с БД connection с именем пользователя и паролем. Значения флагов
+// --username и --password заменяют значения строки соединения, недостающие значения запрашиваются у источников
+// учетных данных, указанных флагами, затем берутся из переменных окружения DBMILL_USERNAME и DBMILL_PASSWORD и,
+// если не указан --no-prompt, запрашиваются у пользователя на терминале. Пароль маскируется в логах и сообщениях об
+// ошибках
+func Credentials(ctx context.Context, connection string) (string, error) {
+	sources, err := credentialSources()
+
+	if err != nil {
+		return "", err
+	}
+
+	username := strings.Trim(Username, " ")
+	password := strings.Trim(Password, " ")
+
+	if username == "" || password == "" {
+		user, pwd, err := engine.Credentials(connection)
+
+		if err != nil {
+			return "", err
+		}
+
+		if username == "" {
+			username = user
+		}
+
+		if password == "" {
+			password = pwd
+		}
+	}
+
+	host, port, database, err := engine.Endpoint(connection)
+
+	if err != nil {
+		return "", err
+	}
+
+	endpoint := input.Endpoint{Host: host, Port: port, Database: database, Username: username}
+
+	username, password, err = input.Resolve(ctx, endpoint, password, sources...)
+
+	log.AddSecret(password)
+
+	if errors.Is(err, input.ErrNoCredentials) {
+		return "", fmt.Errorf("%w; use --username and --password, %s and %s, --password-file, --password-stdin, "+
+			"--password-command or --passfile", err, input.UsernameEnv, input.PasswordEnv)
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	return engine.SetCredentials(connection, username, password)
+}
+
+// credentialSources возвращает источники учетных данных в порядке их опроса
+func credentialSources() ([]input.ISource, error) {
+	sources := make([]input.ISource, 0)
+	passwordSources := 0
+
+	if PasswordStdin {
+		if input.IsTerminal() {
+			return nil, usageError("--password-stdin requires the password to be piped to stdin")
+		}
+
+		sources = append(sources, input.PasswordReader(os.Stdin))
+		passwordSources++
+	}
+
+	if strings.Trim(PasswordFile, " ") != "" {
+		sources = append(sources, input.PasswordFile(PasswordFile))
+		passwordSources++
+	}
+
+	if strings.Trim(PasswordCommand, " ") != "" {
+		sources = append(sources, input.PasswordCommand(PasswordCommand))
+		passwordSources++
+	}
+
+	if passwordSources > 1 {
+		return nil, usageError("--password-stdin, --password-file and --password-command cannot be used together")
+	}
+
+	if strings.Trim(PassFile, " ") != "" {
+		sources = append(sources, input.PassFile(PassFile))
+	}
+
+	sources = append(sources, input.Environment(os.LookupEnv))
+
+	if !NoPrompt {
+		sources = append(sources, input.Prompt())
+	}
+
+	return sources, nil
+}
+
+// ConnectionString возвращает строку соединения с БД connection с учетными данными (см. Credentials) и параметрами
+// соединения, указанными флагами команды
+func ConnectionString(ctx context.Context, connection string) (string, error) {
+	connection, err := Credentials(ctx, connection)
+
+	if err != nil {
+		return "", err
+	}
+
+	connection, err = engine.SetConnectionParameters(connection, ConnectionParameters())
+
+	if err != nil {
+		return "", usageError("%v", err)
+	}
+
+	return connection, nil
+}
+
+// ConnectionParameters возвращает параметры соединения с сервером БД, указанные флагами команды
+func ConnectionParameters() commands.ConnectionParameters {
+	return commands.ConnectionParameters{
+		Encrypt:                Encrypt,
+		TrustServerCertificate: TrustServerCertificate,
+		CAFile:                 CAFile,
+		HostNameInCertificate:  HostNameInCertificate,
+		AppName:                AppName,
+		PacketSize:             PacketSize,
+		ConnectionTimeout:      ConnectionTimeout,
+		DialTimeout:            DialTimeout,
+	}
+}
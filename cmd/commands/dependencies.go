@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine"
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/filter"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/graph"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/log"
+)
+
+// cmdDependencies команда построения графа зависимостей объектов БД
+var cmdDependencies = &cobra.Command{
+	Use:   "dependencies",
+	Short: "exports the dependency graph of database objects",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		// сообщения об ошибках не должны содержать паролей из строки соединения и других секретов
+		defer func() {
+			err = log.RedactError(err)
+		}()
+
+		format, err := ParseGraphFormat(GraphFormat)
+
+		if err != nil {
+			return err
+		}
+
+		direction, err := ParseDirection(Direction)
+
+		if err != nil {
+			return err
+		}
+
+		if Depth < 0 {
+			return usageError("--depth must not be negative")
+		}
+
+		if len(Roots) == 0 && (cmd.Flags().Changed("depth") || cmd.Flags().Changed("direction")) {
+			return usageError("--depth and --direction can be used only with --root")
+		}
+
+		Database, err := ConnectionString(cmd.Context(), Database)
+
+		if err != nil {
+			return err
+		}
+
+		logger, closeLog, err := Logger()
+
+		if err != nil {
+			return err
+		}
+
+		defer closeLog()
+
+		engineOptions := make([]engine.Option, 0)
+		commandOptions := []commands.DependenciesOption{commands.WithDirection(direction), commands.WithDepth(Depth)}
+
+		if logger != nil {
+			engineOptions = append(engineOptions, engine.WithLogger(logger))
+		}
+
+		if len(Roots) > 0 {
+			roots, err := filter.New(Roots)
+
+			if err != nil {
+				return usageError("--root: %v", err)
+			}
+
+			commandOptions = append(commandOptions, commands.WithRootObjects(roots))
+		}
+
+		ctx := cmd.Context()
+
+		if Timeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithTimeout(ctx, Timeout)
+			defer cancel()
+		}
+
+		if QueryTimeout > 0 {
+			engineOptions = append(engineOptions, engine.WithQueryTimeout(QueryTimeout))
+		}
+
+		engn, err := engine.New(ctx, Database, engineOptions...)
+
+		if err != nil {
+			return err
+		}
+
+		command := engn.Dependencies(commandOptions...)
+
+		if err = command.Run(ctx); err != nil {
+			return err
+		}
+
+		return WriteOutput(OutputFile, func(w io.Writer) error {
+			return command.Graph().Write(w, format)
+		})
+	},
+}
+
+// ParseGraphFormat возвращает формат графа зависимостей по его наименованию
+func ParseGraphFormat(format string) (graph.Format, error) {
+	switch format {
+	case "", "dot":
+		return graph.FormatDOT, nil
+	case "mermaid":
+		return graph.FormatMermaid, nil
+	case "json":
+		return graph.FormatJSON, nil
+	default:
+		return graph.FormatDOT, usageError("unknown graph format %s", format)
+	}
+}
+
+// ParseDirection возвращает направление обхода графа зависимостей по его наименованию
+func ParseDirection(direction string) (commands.Direction, error) {
+	switch direction {
+	case "", "dependencies":
+		return commands.DirectionDependencies, nil
+	case "dependents":
+		return commands.DirectionDependents, nil
+	case "both":
+		return commands.DirectionBoth, nil
+	default:
+		return commands.DirectionDependencies, usageError("unknown direction %s", direction)
+	}
+}
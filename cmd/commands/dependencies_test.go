@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/graph"
+)
+
+func TestParseGraphFormat(t *testing.T) {
+	cases := []struct {
+		format    string
+		want      graph.Format
+		withError bool
+	}{
+		{format: "", want: graph.FormatDOT},
+		{format: "dot", want: graph.FormatDOT},
+		{format: "mermaid", want: graph.FormatMermaid},
+		{format: "json", want: graph.FormatJSON},
+		{format: "svg", want: graph.FormatDOT, withError: true},
+	}
+
+	for _, test := range cases {
+		have, err := ParseGraphFormat(test.format)
+
+		if have != test.want || (err != nil) != test.withError {
+			t.Errorf(`ParseGraphFormat("%s") failed!`, test.format)
+		}
+	}
+}
+
+func TestParseDirection(t *testing.T) {
+	cases := []struct {
+		direction string
+		want      commands.Direction
+		withError bool
+	}{
+		{direction: "", want: commands.DirectionDependencies},
+		{direction: "dependencies", want: commands.DirectionDependencies},
+		{direction: "dependents", want: commands.DirectionDependents},
+		{direction: "both", want: commands.DirectionBoth},
+		{direction: "up", want: commands.DirectionDependencies, withError: true},
+	}
+
+	for _, test := range cases {
+		have, err := ParseDirection(test.direction)
+
+		if have != test.want || (err != nil) != test.withError {
+			t.Errorf(`ParseDirection("%s") failed!`, test.direction)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package commands
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine"
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/erd"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/filter"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/log"
+)
+
+// cmdERD команда построения диаграммы "сущность-связь" таблиц БД
+var cmdERD = &cobra.Command{
+	Use:   "erd",
+	Short: "generates an entity-relationship diagram of tables",
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		// сообщения об ошибках не должны содержать паролей из строки соединения и других секретов
+		defer func() {
+			err = log.RedactError(err)
+		}()
+
+		format, err := ParseDiagramFormat(DiagramFormat)
+
+		if err != nil {
+			return err
+		}
+
+		schemas := make([]string, 0, len(Schemas))
+
+		for _, schema := range Schemas {
+			if schema = strings.Trim(schema, " "); schema != "" {
+				schemas = append(schemas, schema)
+			}
+		}
+
+		commandOptions := []commands.ERDOption{commands.WithSchemas(schemas)}
+
+		if len(TableFilter) > 0 {
+			tables, err := filter.New(TableFilter)
+
+			if err != nil {
+				return usageError("--table: %v", err)
+			}
+
+			commandOptions = append(commandOptions, commands.WithTables(tables))
+		}
+
+		Database, err := ConnectionString(cmd.Context(), Database)
+
+		if err != nil {
+			return err
+		}
+
+		logger, closeLog, err := Logger()
+
+		if err != nil {
+			return err
+		}
+
+		defer closeLog()
+
+		engineOptions := make([]engine.Option, 0)
+
+		if logger != nil {
+			engineOptions = append(engineOptions, engine.WithLogger(logger))
+		}
+
+		ctx := cmd.Context()
+
+		if Timeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithTimeout(ctx, Timeout)
+			defer cancel()
+		}
+
+		if QueryTimeout > 0 {
+			engineOptions = append(engineOptions, engine.WithQueryTimeout(QueryTimeout))
+		}
+
+		engn, err := engine.New(ctx, Database, engineOptions...)
+
+		if err != nil {
+			return err
+		}
+
+		command := engn.ERD(commandOptions...)
+
+		if err = command.Run(ctx); err != nil {
+			return err
+		}
+
+		return WriteOutput(OutputFile, func(w io.Writer) error {
+			return command.Diagram().Write(w, format)
+		})
+	},
+}
+
+// ParseDiagramFormat возвращает формат диаграммы "сущность-связь" по его наименованию
+func ParseDiagramFormat(format string) (erd.Format, error) {
+	switch format {
+	case "", "plantuml":
+		return erd.FormatPlantUML, nil
+	case "mermaid":
+		return erd.FormatMermaid, nil
+	case "dot":
+		return erd.FormatDOT, nil
+	default:
+		return erd.FormatPlantUML, usageError("unknown diagram format %s", format)
+	}
+}
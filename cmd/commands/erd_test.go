@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/erd"
+)
+
+func TestParseDiagramFormat(t *testing.T) {
+	cases := []struct {
+		format    string
+		want      erd.Format
+		withError bool
+	}{
+		{format: "", want: erd.FormatPlantUML},
+		{format: "plantuml", want: erd.FormatPlantUML},
+		{format: "mermaid", want: erd.FormatMermaid},
+		{format: "dot", want: erd.FormatDOT},
+		{format: "json", want: erd.FormatPlantUML, withError: true},
+	}
+
+	for _, test := range cases {
+		have, err := ParseDiagramFormat(test.format)
+
+		if have != test.want || (err != nil) != test.withError {
+			t.Errorf(`ParseDiagramFormat("%s") failed!`, test.format)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+)
+
+const (
+	// ExitOK команда выполнена успешно
+	ExitOK = 0
+	// ExitError команда не выполнена: ошибка подключения к БД, чтения метаданных, записи файлов и т.п.
+	ExitError = 1
+	// ExitUsage неверные аргументы командной строки
+	ExitUsage = 2
+	// ExitPartial команда выполнена, но скрипты некоторых объектов БД не созданы или не сохранены
+	ExitPartial = 3
+	// ExitTimeout команда прервана по таймауту (--timeout, --query-timeout)
+	ExitTimeout = 4
+	// ExitInterrupted команда прервана по SIGINT или SIGTERM
+	ExitInterrupted = 130
+)
+
+// UsageError ошибка в аргументах командной строки
+type UsageError struct {
+	message string
+}
+
+// Error возвращает текст ошибки
+func (e *UsageError) Error() string {
+	return e.message
+}
+
+func usageError(format string, args ...interface{}) error {
+	return &UsageError{message: fmt.Sprintf(format, args...)}
+}
+
+// ExitCode возвращает код завершения приложения, соответствующий ошибке err
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return ExitInterrupted
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ExitTimeout
+	}
+
+	var usage *UsageError
+
+	if errors.As(err, &usage) {
+		return ExitUsage
+	}
+
+	var objectErrors commands.MultiError
+
+	if errors.As(err, &objectErrors) && len(objectErrors.Objects()) == len(objectErrors) {
+		return ExitPartial
+	}
+
+	return ExitError
+}
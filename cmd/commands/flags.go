@@ -1,5 +1,9 @@
 package commands
 
+import (
+	"time"
+)
+
 var (
 	// Decrypt необходимость расшифровки объектов
 	Decrypt bool
@@ -15,6 +19,8 @@ var (
 	LogFilename string
 	// LogLevel уровень логирования
 	LogLevel string
+	// LogFormat формат лога: text или json
+	LogFormat string
 	// Filter выражения, содержащие наименования объектов, для которых необходимо создать скрипты
 	// Если срез пуст, то создать скрипты ко всем объектам; заменяет список из FilterPath
 	Filter []string
@@ -23,6 +29,33 @@ var (
 	// Exclude выражения, содержащие наименования объектов, для которых создавать скрипты не надо
 	// Список из указанного файла заменяет значения параметра ExcludePath
 	Exclude []string
+	// WithDependencies добавлять к объектам, выбранным фильтрами, объекты, от которых они зависят
+	WithDependencies bool
+	// WithDependents добавлять к объектам, выбранным фильтрами, объекты, которые зависят от них
+	WithDependents bool
+	// ModifiedSince время по часам сервера БД, начиная с которого должны быть изменены объекты БД, скрипты которых
+	// создаются
+	ModifiedSince string
+	// SinceLastRun создавать скрипты только объектов БД, измененных после последнего успешного запуска команды
+	SinceLastRun bool
+	// StateFile путь к файлу состояния с временем последних успешных запусков команды
+	StateFile string
+	// OutputFile путь к файлу графа или диаграммы. Если не указан, то вывод в stdout
+	OutputFile string
+	// GraphFormat формат графа зависимостей объектов БД: dot, mermaid, json
+	GraphFormat string
+	// Roots правила фильтра корневых объектов графа зависимостей
+	Roots []string
+	// Depth максимальное количество зависимостей от корневых объектов графа
+	Depth int
+	// Direction направление обхода графа зависимостей от корневых объектов: dependencies, dependents, both
+	Direction string
+	// DiagramFormat формат диаграммы "сущность-связь": plantuml, mermaid, dot
+	DiagramFormat string
+	// Schemas схемы таблиц диаграммы "сущность-связь"
+	Schemas []string
+	// TableFilter правила фильтра таблиц диаграммы "сущность-связь"
+	TableFilter []string
 	// ExcludePath имя файла, содержащего наименования объектов, для которых создавать скрипты не надо
 	ExcludePath string
 	// Username имя пользователя базы данных
@@ -33,4 +66,64 @@ var (
 	Password string
 	// SkipPermissions не записывать в скрипты разрешения на объект БД
 	SkipPermissions bool
+	// TargetVersion целевая версия СУБД, для которой создаются скрипты
+	TargetVersion string
+	// ScriptStyle стиль скриптов создания объектов БД
+	ScriptStyle string
+	// Output приемник скриптов: folder, script, tar, zip, stdout
+	Output string
+	// GitAutoCommit фиксировать изменения каталога скриптов в git репозитории
+	GitAutoCommit bool
+	// GitMessage заголовок сообщения коммита
+	GitMessage string
+	// GitAuthor автор коммита в формате "Name <email>"
+	GitAuthor string
+	// Prune удалять скрипты объектов, которых больше нет в БД
+	Prune bool
+	// Parallel количество объектов БД, скрипты которых создаются и записываются параллельно
+	Parallel int
+	// FailFast прерывать выполнение команды на первой ошибке создания или сохранения скрипта
+	FailFast bool
+	// NoProgress не отображать ход выполнения команды
+	NoProgress bool
+	// Timeout максимальное время выполнения команды
+	Timeout time.Duration
+	// QueryTimeout максимальное время выполнения одного запроса к серверу
+	QueryTimeout time.Duration
+	// ReportPath путь к файлу отчета о выполнении команды в формате JSON
+	ReportPath string
+	// JUnitReportPath путь к файлу отчета о выполнении команды в формате JUnit XML
+	JUnitReportPath string
+	// ConfigPath путь к файлу конфигурации проекта. Если не указан, то используется dbmill.yaml рабочего каталога
+	ConfigPath string
+	// Profile наименование профиля конфигурации проекта
+	Profile string
+	// PasswordFile путь к файлу, первая строка которого - пароль пользователя базы данных
+	PasswordFile string
+	// PasswordStdin читать пароль пользователя базы данных из stdin
+	PasswordStdin bool
+	// PasswordCommand внешняя команда, первая строка вывода которой - пароль пользователя базы данных
+	PasswordCommand string
+	// PassFile путь к файлу учетных данных в формате pgpass (host:port:database:username:password)
+	PassFile string
+	// NoPrompt не запрашивать имя пользователя и пароль на терминале
+	NoPrompt bool
+	// Sensitive значения, которые маскируются в логах и сообщениях об ошибках
+	Sensitive []string
+	// Encrypt режим шифрования соединения с сервером: disable, false, true
+	Encrypt string
+	// TrustServerCertificate не проверять сертификат сервера
+	TrustServerCertificate bool
+	// CAFile путь к файлу сертификатов удостоверяющих центров (PEM)
+	CAFile string
+	// HostNameInCertificate имя сервера в его сертификате
+	HostNameInCertificate string
+	// AppName наименование приложения, передаваемое серверу
+	AppName string
+	// PacketSize размер сетевого пакета в байтах
+	PacketSize int
+	// ConnectionTimeout максимальное время подключения к серверу
+	ConnectionTimeout time.Duration
+	// DialTimeout максимальное время установки сетевого соединения с сервером
+	DialTimeout time.Duration
 )
@@ -0,0 +1,63 @@
+package commands
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/vcs"
+)
+
+var authorPattern = regexp.MustCompile(`^\s*([^<]*?)\s*<([^>]*)>\s*$`)
+
+// ParseAuthor возвращает автора коммита по строке в формате "Name <email>"
+func ParseAuthor(author string) (vcs.Signature, error) {
+	match := authorPattern.FindStringSubmatch(author)
+
+	if match == nil || strings.Trim(match[1], " ") == "" {
+		return vcs.Signature{}, usageError("invalid author %s, expected \"Name <email>\"", author)
+	}
+
+	return vcs.Signature{Name: match[1], Email: match[2]}, nil
+}
+
+// GitCommitOptions возвращает опции фиксации изменений каталога скриптов
+func GitCommitOptions(message, author string) ([]vcs.CommitOption, error) {
+	options := make([]vcs.CommitOption, 0)
+
+	if strings.Trim(message, " ") != "" {
+		options = append(options, vcs.WithSubject(message))
+	}
+
+	if strings.Trim(author, " ") != "" {
+		signature, err := ParseAuthor(author)
+
+		if err != nil {
+			return nil, err
+		}
+
+		options = append(options, vcs.WithAuthor(signature))
+	}
+
+	return options, nil
+}
+
+// CommitScripts фиксирует изменения каталога скриптов path в git репозитории. Если изменений нет, то коммит не
+// создается
+func CommitScripts(path string, options ...vcs.CommitOption) error {
+	hash, changes, err := vcs.Commit(path, options...)
+
+	if err == vcs.ErrorNothingToCommit {
+		fmt.Println("git: nothing to commit")
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("git: commit %s (added: %d, modified: %d, removed: %d)\n", hash, len(changes.Added),
+		len(changes.Modified), len(changes.Removed))
+
+	return nil
+}
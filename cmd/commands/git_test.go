@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/vcs"
+)
+
+var authorCases = []struct {
+	author    string
+	want      vcs.Signature
+	withError bool
+}{
+	{
+		author:    "dbmill <dbmill@example.com>",
+		want:      vcs.Signature{Name: "dbmill", Email: "dbmill@example.com"},
+		withError: false,
+	},
+	{
+		author:    "  Nightly Export Job   <ci@example.com> ",
+		want:      vcs.Signature{Name: "Nightly Export Job", Email: "ci@example.com"},
+		withError: false,
+	},
+	{
+		author:    "dbmill",
+		want:      vcs.Signature{},
+		withError: true,
+	},
+	{
+		author:    "<ci@example.com>",
+		want:      vcs.Signature{},
+		withError: true,
+	},
+}
+
+func TestParseAuthor(t *testing.T) {
+	var done bool
+
+	for _, test := range authorCases {
+		have, err := ParseAuthor(test.author)
+		withError := err != nil
+
+		done = have == test.want && withError == test.withError
+
+		if !done {
+			t.Errorf(`ParseAuthor("%s") failed!`, test.author)
+		}
+	}
+}
@@ -1,11 +1,71 @@
 package commands
 
 import (
-	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/log"
 )
 
+// LogStderr значение --log, при котором лог выводится в stderr
+const LogStderr = "-"
+
+// Logger возвращает логгер, настроенный флагами --log, --log-level и --log-format, и функцию закрытия файла лога.
+// Если --log не указан, то возвращается nil
+func Logger() (log.ILogger, func(), error) {
+	if strings.Trim(LogFilename, " ") == "" {
+		return nil, func() {}, nil
+	}
+
+	var logLevel = log.InfoLevel
+
+	if strings.Trim(LogLevel, " ") != "" {
+		level, err := ParseLogLevel(LogLevel)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		logLevel = level
+	}
+
+	logFormat, err := ParseLogFormat(LogFormat)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var out io.Writer = os.Stderr
+
+	closeLog := func() {}
+
+	if LogFilename != LogStderr {
+		fl, err := os.Create(LogFilename)
+
+		if err != nil {
+			return nil, nil, err
+		}
+
+		out = fl
+		closeLog = func() { fl.Close() }
+	}
+
+	return log.New(log.WithLevel(logLevel), log.WithFormat(logFormat), log.WithOutput(out)), closeLog, nil
+}
+
+// ParseLogFormat возвращает формат лога по его наименованию
+func ParseLogFormat(format string) (log.Format, error) {
+	switch format {
+	case "text":
+		return log.Text, nil
+	case "json":
+		return log.JSON, nil
+	default:
+		return log.Text, usageError("unknown log format %s", format)
+	}
+}
+
 // ParseLogLevel возвращает уровень лога по его наименованию
 func ParseLogLevel(level string) (log.Level, error) {
 	switch level {
@@ -24,6 +84,6 @@ func ParseLogLevel(level string) (log.Level, error) {
 	case "panic":
 		return log.PanicLevel, nil
 	default:
-		return log.InfoLevel, fmt.Errorf("unknown log level %s", level)
+		return log.InfoLevel, usageError("unknown log level %s", level)
 	}
 }
@@ -72,3 +72,24 @@ func TestParseLogLevel(t *testing.T) {
 		}
 	}
 }
+
+func TestParseLogFormat(t *testing.T) {
+	cases := []struct {
+		format    string
+		want      log.Format
+		withError bool
+	}{
+		{format: "text", want: log.Text},
+		{format: "json", want: log.JSON},
+		{format: "JSON", want: log.Text, withError: true},
+		{format: "xml", want: log.Text, withError: true},
+	}
+
+	for _, test := range cases {
+		have, err := ParseLogFormat(test.format)
+
+		if have != test.want || (err != nil) != test.withError {
+			t.Errorf(`ParseLogFormat("%s") failed!`, test.format)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package commands
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/crypto/ssh/terminal"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/progress"
+)
+
+// progressDisplay отображение хода выполнения команды scriptsfolder. Скрипты создаются и сохраняются одновременно,
+// поэтому этапы создания и сохранения скриптов отображаются одной строкой этапа сохранения с количеством созданных
+// скриптов
+type progressDisplay struct {
+	reporter *progress.Reporter
+	rendered int
+}
+
+// newProgressDisplay возвращает отображение хода выполнения команды в writer. Если writer - терминал, то строка хода
+// выполнения обновляется на месте
+func newProgressDisplay(writer io.Writer) *progressDisplay {
+	tty := false
+
+	if file, ok := writer.(*os.File); ok {
+		tty = terminal.IsTerminal(int(file.Fd()))
+	}
+
+	return &progressDisplay{reporter: progress.New(writer, progress.WithTTY(tty))}
+}
+
+// Callback обрабатывает событие хода выполнения команды
+func (display *progressDisplay) Callback(event commands.ProgressEvent) {
+	switch event.Phase {
+	case commands.PhaseDone:
+		display.reporter.Finish()
+	case commands.PhaseRendering:
+		if display.rendered == 0 {
+			display.reporter.Update(commands.PhaseWriting.String(), 0, event.Total)
+		}
+
+		display.rendered = event.Done
+		display.reporter.SetDetails(fmt.Sprintf("rendered %d", event.Done))
+	default:
+		display.reporter.Update(event.Phase.String(), event.Done, event.Total)
+	}
+}
+
+// Finish завершает строку хода выполнения
+func (display *progressDisplay) Finish() {
+	display.reporter.Finish()
+}
@@ -0,0 +1,187 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/log"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/report"
+)
+
+// runReport собирает отчет о выполнении команды scriptsfolder
+type runReport struct {
+	report  *report.Report
+	existed bool
+	now     func() time.Time
+	mutex   sync.Mutex
+}
+
+// newRunReport возвращает отчет о выполнении команды command с выводом скриптов в приемник sinkType по пути path
+func newRunReport(command string, sinkType output.SinkType, path string, now func() time.Time) *runReport {
+	rep := report.New(command, now())
+
+	rep.Output = sinkType.String()
+
+	if sinkType != output.SinkStdout {
+		rep.Path = path
+	}
+
+	_, err := os.Stat(path)
+
+	return &runReport{
+		report:  rep,
+		existed: err == nil,
+		now:     now,
+	}
+}
+
+// Object учитывает объект БД, скрипт которого создан. Может вызываться из нескольких горутин
+func (run *runReport) Object(object output.ObjectDefinition) {
+	run.mutex.Lock()
+	defer run.mutex.Unlock()
+
+	run.report.AddScript(object.QualifiedName(), object.Type.String())
+}
+
+// Callback учитывает событие хода выполнения команды при расчете продолжительности этапов
+func (run *runReport) Callback(event commands.ProgressEvent) {
+	run.mutex.Lock()
+	defer run.mutex.Unlock()
+
+	if event.Phase != commands.PhaseDone {
+		run.report.Mark(event.Phase.String(), run.now())
+	}
+}
+
+// Server добавляет в отчет сведения о сервере БД
+func (run *runReport) Server(server commands.ServerInfo) {
+	run.report.Server = report.Server{Version: server.Version, Edition: server.Edition}
+}
+
+// Command добавляет в отчет наименование БД, пропущенные объекты и предупреждения команды
+func (run *runReport) Command(command commands.IScriptsFolderCommand) {
+	run.report.Database = command.DatabaseName()
+
+	for _, skipped := range command.Skipped() {
+		run.report.AddSkipped(skipped.Object, skipped.Type.String(), skipped.Reason.String())
+	}
+
+	run.report.Warnings = append(run.report.Warnings, command.Warnings()...)
+}
+
+// Folder добавляет в отчет файлы каталога скриптов
+func (run *runReport) Folder(folder *output.Folder) {
+	for _, file := range folder.Files() {
+		run.report.AddFile(file.Path, file.Status.String(), file.SHA256)
+	}
+}
+
+// OutputFile добавляет в отчет сохраненный файл вывода скриптов path
+func (run *runReport) OutputFile(path string) error {
+	sum, err := fileSHA256(path)
+
+	if err != nil {
+		return err
+	}
+
+	status := output.FileCreated
+
+	if run.existed {
+		status = output.FileUpdated
+	}
+
+	run.report.AddFile(path, status.String(), sum)
+
+	return nil
+}
+
+// Save завершает отчет о выполнении команды, завершившейся с ошибкой err, и сохраняет его в формате JSON по пути
+// jsonPath и в формате JUnit XML по пути junitPath. Пустой путь означает, что отчет в этом формате не нужен
+func (run *runReport) Save(jsonPath, junitPath string, err error) error {
+	failures, other := splitObjectErrors(err)
+
+	for _, failure := range failures {
+		run.report.AddFailure(failure.Object, log.RedactError(failure.Err))
+	}
+
+	run.report.Finish(run.now(), ExitCode(err), log.RedactError(other))
+
+	if strings.Trim(jsonPath, " ") != "" {
+		if err := saveReport(jsonPath, run.report.WriteJSON); err != nil {
+			return err
+		}
+	}
+
+	if strings.Trim(junitPath, " ") != "" {
+		if err := saveReport(junitPath, run.report.WriteJUnit); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// splitObjectErrors разделяет ошибку выполнения команды на ошибки отдельных объектов БД и остальные ошибки
+func splitObjectErrors(err error) ([]*commands.ObjectError, error) {
+	if err == nil {
+		return nil, nil
+	}
+
+	var errs commands.MultiError
+
+	if !errors.As(err, &errs) {
+		return nil, err
+	}
+
+	failures := make([]*commands.ObjectError, 0, len(errs))
+	other := make(commands.MultiError, 0)
+
+	for _, e := range errs {
+		var objectError *commands.ObjectError
+
+		if errors.As(e, &objectError) {
+			failures = append(failures, objectError)
+		} else {
+			other = append(other, e)
+		}
+	}
+
+	return failures, other.ErrorOrNil()
+}
+
+func saveReport(path string, write func(writer io.Writer) error) error {
+	var buf bytes.Buffer
+
+	if err := write(&buf); err != nil {
+		return err
+	}
+
+	return output.WriteFileAtomic(path, buf.Bytes())
+}
+
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return "", err
+	}
+
+	defer f.Close()
+
+	hash := sha256.New()
+
+	if _, err = io.Copy(hash, f); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -6,10 +6,13 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// cmdRoot команда - точка входа в приложение
+// cmdRoot команда - точка входа в приложение. Ошибки выводятся в main, а справка по использованию - только при
+// ошибке в аргументах командной строки (см. Execute)
 var cmdRoot = &cobra.Command{
-	Use:   app,
-	Short: appDescription,
+	Use:           app,
+	Short:         appDescription,
+	SilenceErrors: true,
+	SilenceUsage:  true,
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println(cmd.UsageString())
 	},
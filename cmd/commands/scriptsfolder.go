@@ -1,112 +1,190 @@
 package commands
 
 import (
-	"bufio"
+	"context"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/vitpelekhaty/dbmill-cli/cmd/engine"
 	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
-	"github.com/vitpelekhaty/dbmill-cli/cmd/input"
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/filter"
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/log"
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/state"
 )
 
 // cmdScriptsFolder команда создания скриптов на основе схемы
 var cmdScriptsFolder = &cobra.Command{
 	Use:   "scriptsfolder",
 	Short: "creates scripts based on the schema",
-	RunE: func(cmd *cobra.Command, args []string) error {
-		if strings.Trim(Username, " ") == "" || strings.Trim(Password, " ") == "" {
-			user, pwd, err := engine.Credentials(Database)
+	RunE: func(cmd *cobra.Command, args []string) (err error) {
+		// сообщения об ошибках не должны содержать паролей из строки соединения и других секретов
+		defer func() {
+			err = log.RedactError(err)
+		}()
 
-			if err != nil {
-				return err
-			}
+		Database, err := ConnectionString(cmd.Context(), Database)
 
-			if strings.Trim(Username, " ") == "" {
-				Username = user
-			}
-
-			if strings.Trim(Password, " ") == "" {
-				Password = pwd
-			}
+		if err != nil {
+			return err
 		}
 
-		if strings.Trim(Username, " ") == "" {
-			Username = input.Username()
-		}
+		logger, closeLog, err := Logger()
 
-		if strings.Trim(Password, " ") == "" {
-			Password = input.Password()
+		if err != nil {
+			return err
 		}
 
-		Database, err := engine.SetCredentials(Database, Username, Password)
+		defer closeLog()
+
+		include, err := ObjectFilter(FilterPath, Filter, filter.New)
 
 		if err != nil {
 			return err
 		}
 
-		var logger log.ILogger
-
-		if strings.Trim(LogFilename, " ") != "" {
-			var logLevel = log.InfoLevel
-
-			if strings.Trim(LogLevel, " ") != "" {
-				logLevel, err = ParseLogLevel(LogLevel)
+		exclude, err := ObjectFilter(ExcludePath, Exclude, filter.NewExclusion)
 
-				if err != nil {
-					return err
-				}
-			}
+		if err != nil {
+			return err
+		}
 
-			fl, err := os.Create(LogFilename)
+		scriptStyle, err := ParseScriptStyle(ScriptStyle)
 
-			if err != nil {
-				return err
-			}
+		if err != nil {
+			return err
+		}
 
-			defer fl.Close()
+		outputDirStruct, err := OutputDirectoryStructure(DirStructFilename)
 
-			logger = log.New(log.WithLevel(logLevel), log.WithOutput(fl))
+		if err != nil {
+			return err
 		}
 
-		include, err := ObjectFilter(FilterPath, Filter)
+		templates, err := output.LoadTemplates(outputDirStruct, filepath.Dir(DirStructFilename))
 
 		if err != nil {
 			return err
 		}
 
-		exclude, err := ObjectFilter(ExcludePath, Exclude)
+		engineOptions := make([]engine.Option, 0)
+		commandOptions := make([]commands.ScriptsFolderOption, 0)
+
+		sinkType, err := ParseSinkType(Output)
 
 		if err != nil {
 			return err
 		}
 
-		outputDirStruct, err := OutputDirectoryStructure(DirStructFilename)
+		if Prune && sinkType != output.SinkFolder {
+			return usageError("--prune can be used only with the folder output")
+		}
+
+		if GitAutoCommit && sinkType != output.SinkFolder {
+			return usageError("--git-commit can be used only with the folder output")
+		}
+
+		commitOptions, err := GitCommitOptions(GitMessage, GitAuthor)
 
 		if err != nil {
 			return err
 		}
 
-		engineOptions := make([]engine.Option, 0)
-		commandOptions := make([]commands.ScriptsFolderOption, 0)
+		if Prune && (include != nil || exclude != nil) {
+			return usageError("--prune cannot be used together with object filters")
+		}
+
+		var since time.Time
+
+		if strings.Trim(ModifiedSince, " ") != "" {
+			if SinceLastRun {
+				return usageError("--modified-since cannot be used together with --since-last-run")
+			}
+
+			since, err = ParseModifiedSince(strings.Trim(ModifiedSince, " "))
+
+			if err != nil {
+				return err
+			}
+		}
+
+		if Prune && (!since.IsZero() || SinceLastRun) {
+			return usageError("--prune cannot be used together with --modified-since and --since-last-run")
+		}
+
+		var (
+			runs     *state.State
+			stateKey string
+		)
+
+		if SinceLastRun {
+			stateKey, err = StateKey(Database, sinkType, Path)
+
+			if err != nil {
+				return err
+			}
+
+			runs, err = state.Load(StateFile)
+
+			if err != nil {
+				return err
+			}
+
+			// при первом запуске скрипты создаются для всех объектов
+			if last, ok := runs.Last(stateKey); ok {
+				since, err = last.Since()
+
+				if err != nil {
+					return fmt.Errorf("%s: %v", StateFile, err)
+				}
+			}
+		}
+
+		var run *runReport
+
+		if strings.Trim(ReportPath, " ") != "" || strings.Trim(JUnitReportPath, " ") != "" {
+			run = newRunReport(cmd.Name(), sinkType, Path, time.Now)
+
+			defer func() {
+				err = joinErrors(err, run.Save(ReportPath, JUnitReportPath, err))
+			}()
+		}
+
+		var sink output.ISink
+
+		folder := output.NewFolder(Path)
 
 		commandOptions = append(commandOptions, commands.WithObjectDefinitionCallback(
-			func(objectCatalog, objectSchema, objectName string, objectType output.DatabaseObjectType,
-				objectDefinition []byte) error {
-				return SaveDefinition(Path, objectCatalog, objectSchema, objectName, objectType, objectDefinition,
-					outputDirStruct)
+			func(object output.ObjectDefinition) error {
+				return sink.Write(object)
 			}))
 
+		if Prune {
+			// скрипты существующих объектов БД, которые не удалось создать, не удаляются
+			commandOptions = append(commandOptions, commands.WithSkippedObjectCallback(
+				func(object output.ObjectDefinition, reason commands.SkipReason) {
+					if reason != commands.SkipEncrypted && reason != commands.SkipUnsupported {
+						return
+					}
+
+					if subdirectory, filename, err := output.ScriptFilename(outputDirStruct, object); err == nil {
+						folder.Keep(subdirectory, filename)
+					}
+				}))
+		}
+
 		commandOptions = append(commandOptions, commands.WithDatabaseObjectTypes(outputDirStruct.DatabaseObjects()))
 
+		// скрипты единого скрипта развертывания упорядочиваются по зависимостям объектов БД
+		if sinkType == output.SinkScript {
+			commandOptions = append(commandOptions, commands.WithObjectDependencies())
+		}
+
 		if logger != nil {
 			engineOptions = append(engineOptions, engine.WithLogger(logger))
 		}
@@ -119,6 +197,18 @@ var cmdScriptsFolder = &cobra.Command{
 			commandOptions = append(commandOptions, commands.WithExcludedObjects(exclude))
 		}
 
+		if WithDependencies {
+			commandOptions = append(commandOptions, commands.WithDependencies())
+		}
+
+		if WithDependents {
+			commandOptions = append(commandOptions, commands.WithDependents())
+		}
+
+		if !since.IsZero() {
+			commandOptions = append(commandOptions, commands.WithModifiedSince(since))
+		}
+
 		if Decrypt {
 			commandOptions = append(commandOptions, commands.WithDecrypt())
 		}
@@ -131,13 +221,136 @@ var cmdScriptsFolder = &cobra.Command{
 			commandOptions = append(commandOptions, commands.WithSkipPermissions())
 		}
 
-		engn, err := engine.New(Database, engineOptions...)
+		if strings.Trim(TargetVersion, " ") != "" {
+			commandOptions = append(commandOptions, commands.WithTargetVersion(TargetVersion))
+		}
+
+		commandOptions = append(commandOptions, commands.WithScriptStyle(scriptStyle))
+
+		if Parallel < 1 {
+			return usageError("--parallel must be greater than zero")
+		}
+
+		commandOptions = append(commandOptions, commands.WithParallel(Parallel))
+
+		if FailFast {
+			commandOptions = append(commandOptions, commands.WithFailFast())
+		}
+
+		if len(templates) > 0 {
+			commandOptions = append(commandOptions, commands.WithTemplates(templates))
+		}
+
+		progressCallbacks := make([]commands.ProgressCallback, 0)
+
+		if !NoProgress {
+			display := newProgressDisplay(os.Stderr)
+			defer display.Finish()
+
+			progressCallbacks = append(progressCallbacks, display.Callback)
+		}
+
+		if run != nil {
+			progressCallbacks = append(progressCallbacks, run.Callback)
+		}
+
+		if len(progressCallbacks) > 0 {
+			commandOptions = append(commandOptions, commands.WithProgressCallback(
+				func(event commands.ProgressEvent) {
+					for _, callback := range progressCallbacks {
+						callback(event)
+					}
+				}))
+		}
+
+		ctx := cmd.Context()
+
+		if Timeout > 0 {
+			var cancel context.CancelFunc
+
+			ctx, cancel = context.WithTimeout(ctx, Timeout)
+			defer cancel()
+		}
+
+		if QueryTimeout > 0 {
+			engineOptions = append(engineOptions, engine.WithQueryTimeout(QueryTimeout))
+		}
+
+		engn, err := engine.New(ctx, Database, engineOptions...)
 
 		if err != nil {
 			return err
 		}
 
-		return engn.ScriptsFolder(commandOptions...).Run()
+		if run != nil {
+			run.Server(engn.Server())
+		}
+
+		// при прерывании на первой ошибке скрипты записываются последовательно, чтобы ошибка записи относилась к
+		// объекту, при сохранении которого она возникла
+		writers := Parallel
+
+		if FailFast {
+			writers = 1
+		}
+
+		// объект учитывается в отчете только после записи его скрипта: при параллельной записи Write приемника
+		// возвращает управление до записи, а ошибки записи возвращаются при закрытии приемника
+		var written func(object output.ObjectDefinition)
+
+		if run != nil {
+			written = run.Object
+		}
+
+		sink, closeSink, err := OutputSink(sinkType, Path, folder, outputDirStruct, writers, written)
+
+		if err != nil {
+			return err
+		}
+
+		command := engn.ScriptsFolder(commandOptions...)
+
+		err = command.Run(ctx)
+
+		// прерванный по сигналу или таймауту вывод в файл не сохраняется
+		committed := ctx.Err() == nil
+		closeErr := closeSink(committed)
+		err = joinErrors(err, closeErr)
+
+		for _, warning := range command.Warnings() {
+			fmt.Fprintln(os.Stderr, "warning:", log.Redact(warning))
+		}
+
+		if run != nil {
+			run.Command(command)
+
+			if sinkType != output.SinkFolder && sinkType != output.SinkStdout && committed && closeErr == nil {
+				err = joinErrors(err, run.OutputFile(Path))
+			}
+		}
+
+		if sinkType == output.SinkFolder {
+			if err == nil && Prune {
+				_, err = folder.Prune(outputDirStruct, PruneTypes(outputDirStruct.DatabaseObjects()))
+			}
+
+			fmt.Println(folder.Summary())
+
+			if run != nil {
+				run.Folder(folder)
+			}
+
+			if err == nil && GitAutoCommit {
+				err = CommitScripts(Path, commitOptions...)
+			}
+		}
+
+		if err == nil && runs != nil {
+			runs.Set(stateKey, command.ServerTime(), time.Now())
+			err = runs.Save(StateFile)
+		}
+
+		return err
 	},
 }
 
@@ -158,55 +371,38 @@ func OutputDirectoryStructure(path string) (output.IScriptsFolderOutput, error)
 	return output.NewScriptsFolderOutput(f)
 }
 
-// ObjectFilter возвращает настроенный фильтр объектов БД
-func ObjectFilter(path string, expressions []string) (filter.IFilter, error) {
-	if len(expressions) > 0 {
-		return filter.New(expressions)
-	}
-
-	if strings.Trim(path, " ") == "" {
-		return nil, nil
-	}
-
-	f, err := os.Open(path)
-
-	if err != nil {
-		return nil, err
-	}
+// ObjectFilter возвращает фильтр объектов БД, созданный функцией newFilter из выражений expressions или, если они не
+// указаны, из файла path. Если в выражениях нет правил (например, файл содержит только комментарии), то возвращается
+// nil: пустой фильтр не ограничивает выбор объектов и ничего не исключает
+func ObjectFilter(path string, expressions []string,
+	newFilter func(expressions []string) (*filter.Filter, error)) (filter.IFilter, error) {
+	if len(expressions) == 0 {
+		if strings.Trim(path, " ") == "" {
+			return nil, nil
+		}
 
-	defer f.Close()
+		f, err := os.Open(path)
 
-	scanner := bufio.NewScanner(f)
-	scanner.Split(bufio.ScanLines)
+		if err != nil {
+			return nil, err
+		}
 
-	var exp []string
+		defer f.Close()
 
-	for scanner.Scan() {
-		exp = append(exp, scanner.Text())
+		if expressions, err = filter.Read(f); err != nil {
+			return nil, err
+		}
 	}
 
-	return filter.New(exp)
-}
-
-// SaveDefinition сохраняет определение объекта БД в скрипт
-func SaveDefinition(path string, objectCatalog, objectSchema, objectName string, objectType output.DatabaseObjectType,
-	objectDefinition []byte, rules output.IScriptsFolderOutput) error {
-	subdirectory, filename, ok := rules.Rules(objectType)
+	objectFilter, err := newFilter(expressions)
 
-	if !ok {
-		return fmt.Errorf("no ouput rules for %v", objectType)
+	if err != nil {
+		return nil, err
 	}
 
-	filename = strings.ReplaceAll(filename, "$schema$", objectSchema)
-	filename = strings.ReplaceAll(filename, "$object$", objectName)
-	filename = strings.ReplaceAll(filename, "$database$", objectCatalog)
-	filename = strings.ReplaceAll(filename, "$type$", objectType.String())
-
-	err := os.MkdirAll(filepath.Join(path, subdirectory), os.ModePerm)
-
-	if err != nil {
-		return err
+	if objectFilter.Empty() {
+		return nil, nil
 	}
 
-	return ioutil.WriteFile(filepath.Join(filepath.Join(path, subdirectory), filename), objectDefinition, 0664)
+	return objectFilter, nil
 }
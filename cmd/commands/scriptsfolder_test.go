@@ -0,0 +1,105 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/filter"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+func TestObjectFilter(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbmill-filter")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	comments := filepath.Join(dir, "comments.txt")
+
+	err = ioutil.WriteFile(comments, []byte("# пока ничего не исключаем\n\n   \n# table:dbo.*\n"), 0664)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := filepath.Join(dir, "rules.txt")
+
+	if err = ioutil.WriteFile(rules, []byte("# процедуры отчетов\nprocedure:rpt.*\n"), 0664); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		usp   = filter.Object{Type: output.Procedure, Schema: "rpt", Name: "usp_Sales"}
+		table = filter.Object{Type: output.Table, Schema: "dbo", Name: "Orders"}
+	)
+
+	cases := []struct {
+		path        string
+		expressions []string
+		newFilter   func(expressions []string) (*filter.Filter, error)
+		empty       bool
+		match       []filter.Object
+		miss        []filter.Object
+	}{
+		{path: "", newFilter: filter.NewExclusion, empty: true},
+		{path: comments, newFilter: filter.NewExclusion, empty: true},
+		{path: comments, newFilter: filter.New, empty: true},
+		{path: "", expressions: []string{"", "  "}, newFilter: filter.NewExclusion, empty: true},
+		{path: rules, newFilter: filter.NewExclusion, match: []filter.Object{usp}, miss: []filter.Object{table}},
+		{
+			path:        comments,
+			expressions: []string{"table:dbo.*"},
+			newFilter:   filter.New,
+			match:       []filter.Object{table},
+			miss:        []filter.Object{usp},
+		},
+		{
+			expressions: []string{"!procedure:rpt.*"},
+			newFilter:   filter.NewExclusion,
+			miss:        []filter.Object{usp, table},
+		},
+	}
+
+	for _, test := range cases {
+		have, err := ObjectFilter(test.path, test.expressions, test.newFilter)
+
+		if err != nil {
+			t.Fatalf("%s %v: %v", test.path, test.expressions, err)
+		}
+
+		if test.empty {
+			if have != nil {
+				t.Errorf("%s %v: the filter is not nil", test.path, test.expressions)
+			}
+
+			continue
+		}
+
+		if have == nil {
+			t.Fatalf("%s %v: the filter is nil", test.path, test.expressions)
+		}
+
+		for _, object := range test.match {
+			if err := have.Match(object); err != nil {
+				t.Errorf("%s %v: %s is not matched", test.path, test.expressions, object.QualifiedName())
+			}
+		}
+
+		for _, object := range test.miss {
+			if err := have.Match(object); err != filter.ErrorNotMatched {
+				t.Errorf("%s %v: %s is matched", test.path, test.expressions, object.QualifiedName())
+			}
+		}
+	}
+}
+
+func TestObjectFilter_notFound(t *testing.T) {
+	if _, err := ObjectFilter(filepath.Join(os.TempDir(), "dbmill-missing-filter.txt"), nil, filter.New); err == nil {
+		t.Error("the error is expected")
+	}
+}
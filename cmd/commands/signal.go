@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+)
+
+// signalContext возвращает контекст, который отменяется при получении SIGINT или SIGTERM. Повторный сигнал
+// завершает приложение немедленно
+func signalContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan struct{})
+	go watchSignals(signals, done, cancel, os.Exit)
+
+	var once sync.Once
+
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+
+		cancel()
+	}
+}
+
+// watchSignals отменяет контекст функцией cancel при первом сигнале из signals и завершает приложение функцией exit
+// при повторном. Отмена контекста не прекращает ожидание повторного сигнала, чтобы зависшую команду можно было
+// завершить. Ожидание прекращается при закрытии done
+func watchSignals(signals <-chan os.Signal, done <-chan struct{}, cancel context.CancelFunc, exit func(code int)) {
+	select {
+	case <-signals:
+		cancel()
+	case <-done:
+		return
+	}
+
+	select {
+	case <-signals:
+		exit(ExitInterrupted)
+	case <-done:
+	}
+}
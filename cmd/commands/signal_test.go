@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWatchSignals(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	signals := make(chan os.Signal, 2)
+	done := make(chan struct{})
+	exited := make(chan int, 1)
+
+	go watchSignals(signals, done, cancel, func(code int) {
+		exited <- code
+	})
+
+	signals <- os.Interrupt
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context is not canceled by the first signal")
+	}
+
+	select {
+	case code := <-exited:
+		t.Fatalf("exit(%d) is called by the first signal", code)
+	default:
+	}
+
+	signals <- os.Interrupt
+
+	select {
+	case code := <-exited:
+		if code != ExitInterrupted {
+			t.Errorf("have exit code %d, want %d", code, ExitInterrupted)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("exit is not called by the second signal")
+	}
+
+	close(done)
+}
@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+// ParseSinkType возвращает тип приемника скриптов по его наименованию
+func ParseSinkType(sinkType string) (output.SinkType, error) {
+	switch sinkType {
+	case "", "folder":
+		return output.SinkFolder, nil
+	case "script":
+		return output.SinkScript, nil
+	case "tar":
+		return output.SinkTar, nil
+	case "zip":
+		return output.SinkZip, nil
+	case "stdout":
+		return output.SinkStdout, nil
+	default:
+		return output.SinkFolder, usageError("unknown output %s", sinkType)
+	}
+}
+
+// OutputSink возвращает приемник скриптов типа sinkType. Для каталога скриптов path - путь к каталогу, для единого
+// скрипта и архивов - путь к создаваемому файлу. Возвращаемая функция close завершает запись скриптов и закрывает
+// файл: при commit = true файл сохраняется по пути path, иначе удаляется, а существующий файл не изменяется. При
+// parallel > 1 скрипты записываются в каталог скриптов параллельно. Функция written, если указана, вызывается после
+// успешной записи скрипта объекта БД, в том числе при параллельной записи - из горутин, записывающих скрипты
+func OutputSink(sinkType output.SinkType, path string, folder *output.Folder, rules output.IScriptsFolderOutput,
+	parallel int, written func(object output.ObjectDefinition)) (sink output.ISink, close func(commit bool) error,
+	err error) {
+	if sinkType == output.SinkFolder {
+		if parallel > 1 {
+			async := output.NewAsyncSink(&writtenSink{ISink: output.NewFolderSink(folder, rules), written: written},
+				parallel)
+			return async, func(bool) error { return closeAsyncSink(async) }, nil
+		}
+
+		sink = &writtenSink{ISink: output.NewFolderSink(folder, rules), written: written}
+
+		return sink, func(bool) error { return sink.Close() }, nil
+	}
+
+	if sinkType == output.SinkStdout {
+		stream := output.NewStreamSink(os.Stdout, rules.Format(output.UnknownObject))
+		sink = &writtenSink{ISink: stream, written: written}
+		return sink, func(bool) error { return sink.Close() }, nil
+	}
+
+	if strings.Trim(path, " ") == "" {
+		return nil, nil, usageError("path to the output file is not specified")
+	}
+
+	file, err := output.CreateAtomic(path)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch sinkType {
+	case output.SinkScript:
+		sink = output.NewScriptSink(file, rules.Format(output.UnknownObject))
+	case output.SinkTar:
+		sink = output.NewTarSink(file, rules)
+	case output.SinkZip:
+		sink = output.NewZipSink(file, rules)
+	}
+
+	sink = &writtenSink{ISink: sink, written: written}
+
+	return sink, func(commit bool) error {
+		err := sink.Close()
+
+		if err != nil || !commit {
+			file.Abort()
+			return err
+		}
+
+		return file.Commit()
+	}, nil
+}
+
+// writtenSink приемник скриптов, вызывающий written после успешной записи скрипта в приемник ISink
+type writtenSink struct {
+	output.ISink
+	written func(object output.ObjectDefinition)
+}
+
+// Write записывает скрипт объекта БД и, если запись выполнена, вызывает written
+func (sink *writtenSink) Write(object output.ObjectDefinition) error {
+	if err := sink.ISink.Write(object); err != nil {
+		return err
+	}
+
+	if sink.written != nil {
+		sink.written(object)
+	}
+
+	return nil
+}
+
+// closeAsyncSink закрывает приемник async и возвращает ошибки записи скриптов списком commands.MultiError, как и
+// ошибки, возникающие при последовательной записи скриптов
+func closeAsyncSink(async *output.AsyncSink) error {
+	err := async.Close()
+
+	writeErrors := async.Errors()
+
+	if len(writeErrors) == 0 {
+		return err
+	}
+
+	errs := make(commands.MultiError, len(writeErrors))
+
+	for index, writeErr := range writeErrors {
+		errs[index] = commands.NewObjectError(writeErr.Object.QualifiedName(), writeErr.Err)
+	}
+
+	return errs
+}
+
+// joinErrors объединяет ошибку выполнения команды err и ошибку закрытия приемника скриптов closeErr. Если обе ошибки
+// не пустые, то возвращается commands.MultiError с ними обеими
+func joinErrors(err, closeErr error) error {
+	if closeErr == nil {
+		return err
+	}
+
+	if err == nil {
+		return closeErr
+	}
+
+	errs, ok := err.(commands.MultiError)
+
+	if !ok {
+		errs = commands.MultiError{err}
+	}
+
+	if closeErrs, ok := closeErr.(commands.MultiError); ok {
+		return append(errs, closeErrs...)
+	}
+
+	return append(errs, closeErr)
+}
+
+// WriteOutput записывает результат команды функцией write в файл path. Если путь не указан или равен -, то результат
+// выводится в stdout. Файл заменяется только после успешной записи
+func WriteOutput(path string, write func(w io.Writer) error) error {
+	if path = strings.Trim(path, " "); path == "" || path == "-" {
+		return write(os.Stdout)
+	}
+
+	var buf bytes.Buffer
+
+	if err := write(&buf); err != nil {
+		return err
+	}
+
+	return output.WriteFileAtomic(path, buf.Bytes())
+}
+
+// unscriptedTypes типы объектов БД, скрипты которых команда не создает
+var unscriptedTypes = map[output.DatabaseObjectType]bool{
+	output.UnknownObject: true,
+	output.StaticData:    true,
+}
+
+// PruneTypes возвращает типы объектов БД из types, скрипты которых создает команда, в порядке types. Устаревшие скрипты
+// удаляются для всех таких типов, даже если объектов типа в БД не осталось. Скрипты типов, которые команда не создает
+// (например, staticData), не должны удаляться --prune
+func PruneTypes(types []output.DatabaseObjectType) []output.DatabaseObjectType {
+	pruned := make([]output.DatabaseObjectType, 0, len(types))
+
+	for _, objectType := range types {
+		if !unscriptedTypes[objectType] {
+			pruned = append(pruned, objectType)
+		}
+	}
+
+	return pruned
+}
@@ -0,0 +1,244 @@
+package commands
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+var sinkTypeCases = []struct {
+	sinkType  string
+	want      output.SinkType
+	withError bool
+}{
+	{
+		sinkType:  "",
+		want:      output.SinkFolder,
+		withError: false,
+	},
+	{
+		sinkType:  "folder",
+		want:      output.SinkFolder,
+		withError: false,
+	},
+	{
+		sinkType:  "script",
+		want:      output.SinkScript,
+		withError: false,
+	},
+	{
+		sinkType:  "tar",
+		want:      output.SinkTar,
+		withError: false,
+	},
+	{
+		sinkType:  "zip",
+		want:      output.SinkZip,
+		withError: false,
+	},
+	{
+		sinkType:  "stdout",
+		want:      output.SinkStdout,
+		withError: false,
+	},
+	{
+		sinkType:  "rar",
+		want:      output.SinkFolder,
+		withError: true,
+	},
+}
+
+func TestParseSinkType(t *testing.T) {
+	var done bool
+
+	for _, test := range sinkTypeCases {
+		have, err := ParseSinkType(test.sinkType)
+		withError := err != nil
+
+		done = have == test.want && withError == test.withError
+
+		if !done {
+			t.Errorf(`ParseSinkType("%s") failed!`, test.sinkType)
+		}
+	}
+}
+
+func TestJoinErrors(t *testing.T) {
+	failed := errors.New("failed")
+	closeFailed := errors.New("close failed")
+
+	orders := commands.NewObjectError("[dbo].[Orders]", failed)
+	getOrders := commands.NewObjectError("[dbo].[GetOrders]", failed)
+
+	var cases = []struct {
+		err      error
+		closeErr error
+		want     error
+	}{
+		{err: nil, closeErr: nil, want: nil},
+		{err: failed, closeErr: nil, want: failed},
+		{err: nil, closeErr: closeFailed, want: closeFailed},
+		{err: failed, closeErr: closeFailed, want: commands.MultiError{failed, closeFailed}},
+		{
+			err:      failed,
+			closeErr: commands.MultiError{getOrders},
+			want:     commands.MultiError{failed, getOrders},
+		},
+		{
+			err:      commands.MultiError{orders},
+			closeErr: commands.MultiError{getOrders},
+			want:     commands.MultiError{orders, getOrders},
+		},
+		{
+			err:      commands.MultiError{orders},
+			closeErr: closeFailed,
+			want:     commands.MultiError{orders, closeFailed},
+		},
+	}
+
+	for _, test := range cases {
+		if have := joinErrors(test.err, test.closeErr); !reflect.DeepEqual(have, test.want) {
+			t.Errorf("joinErrors(%v, %v): have %v, want %v", test.err, test.closeErr, have, test.want)
+		}
+	}
+}
+
+func TestOutputSink_parallelWriteErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbmill-sink")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	rules := output.DefaultScriptsFolderOutput
+	subdirectory, _, _ := rules.Rules(output.View)
+
+	// файл на месте подкаталога представлений: скрипты представлений не записываются
+	if err = ioutil.WriteFile(filepath.Join(dir, subdirectory), []byte("--"), 0664); err != nil {
+		t.Fatal(err)
+	}
+
+	run := newRunReport("scriptsfolder", output.SinkFolder, dir, time.Now)
+
+	sink, closeSink, err := OutputSink(output.SinkFolder, dir, output.NewFolder(dir), rules, 4, run.Object)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, object := range []output.ObjectDefinition{
+		{Schema: "dbo", Name: "Orders", Type: output.View, Definition: []byte("--")},
+		{Schema: "dbo", Name: "GetOrders", Type: output.Procedure, Definition: []byte("--")},
+		{Schema: "dbo", Name: "Sales", Type: output.View, Definition: []byte("--")},
+		{Schema: "dbo", Name: "GetSales", Type: output.Procedure, Definition: []byte("--")},
+	} {
+		if err = sink.Write(object); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = closeSink(true)
+
+	if err == nil {
+		t.Fatal("the error is expected")
+	}
+
+	if err = run.Save("", "", err); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := map[string]int{"procedure": 2}; !reflect.DeepEqual(run.report.Objects, want) {
+		t.Errorf("have objects %v, want %v", run.report.Objects, want)
+	}
+
+	failed := make(map[string]bool)
+
+	for _, failure := range run.report.Failures {
+		failed[failure.Object] = true
+	}
+
+	if want := map[string]bool{"[dbo].[Orders]": true, "[dbo].[Sales]": true}; !reflect.DeepEqual(failed, want) {
+		t.Errorf("have failures %v, want %v", failed, want)
+	}
+
+	for _, script := range run.report.Scripts {
+		if failed[script.Name] {
+			t.Errorf("%s is reported both as a script and as a failure", script.Name)
+		}
+	}
+}
+
+func TestPruneTypes(t *testing.T) {
+	types := []output.DatabaseObjectType{output.Schema, output.Table, output.StaticData, output.Procedure}
+	want := []output.DatabaseObjectType{output.Schema, output.Table, output.Procedure}
+
+	if have := PruneTypes(types); !reflect.DeepEqual(have, want) {
+		t.Errorf("PruneTypes failed: have %v, want %v", have, want)
+	}
+}
+
+func TestPruneTypes_noObjectsLeft(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbmill-prune")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	rules := output.DefaultScriptsFolderOutput
+
+	// в БД не осталось ни одной процедуры, но скрипт удаленной процедуры остался в каталоге
+	files := map[output.DatabaseObjectType]string{
+		output.Procedure:  "dbo.Dropped.sql",
+		output.Table:      "dbo.Orders.sql",
+		output.StaticData: "dbo.Orders.Data.sql",
+	}
+
+	for objectType, filename := range files {
+		subdirectory, _, _ := rules.Rules(objectType)
+
+		if err = os.MkdirAll(filepath.Join(dir, subdirectory), 0775); err != nil {
+			t.Fatal(err)
+		}
+
+		if err = ioutil.WriteFile(filepath.Join(dir, subdirectory, filename), []byte("--"), 0664); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	folder := output.NewFolder(dir)
+
+	subdirectory, _, _ := rules.Rules(output.Table)
+
+	if _, err = folder.Write(subdirectory, files[output.Table], []byte("--")); err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := folder.Prune(rules, PruneTypes(rules.DatabaseObjects()))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subdirectory, _, _ = rules.Rules(output.Procedure)
+
+	if want := []string{filepath.Join(dir, subdirectory, files[output.Procedure])}; !reflect.DeepEqual(deleted, want) {
+		t.Errorf("have deleted %v, want %v", deleted, want)
+	}
+
+	subdirectory, _, _ = rules.Rules(output.StaticData)
+
+	if _, err = os.Stat(filepath.Join(dir, subdirectory, files[output.StaticData])); err != nil {
+		t.Errorf("static data scripts must not be pruned: %v", err)
+	}
+}
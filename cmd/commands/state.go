@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/state"
+)
+
+// modifiedSinceLayouts допустимые форматы значения --modified-since
+var modifiedSinceLayouts = []string{
+	"2006-01-02",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+}
+
+// ParseModifiedSince возвращает время, начиная с которого должны быть изменены объекты БД. Время указывается по часам
+// сервера БД, без часового пояса
+func ParseModifiedSince(value string) (time.Time, error) {
+	for _, layout := range modifiedSinceLayouts {
+		if since, err := time.Parse(layout, value); err == nil {
+			return since, nil
+		}
+	}
+
+	return time.Time{}, usageError("invalid --modified-since value %s, expected YYYY-MM-DD or YYYY-MM-DDThh:mm:ss",
+		value)
+}
+
+// StateKey возвращает ключ запуска команды в файле состояния для БД строки соединения connection и приемника скриптов
+// вида sinkType с путем path
+func StateKey(connection string, sinkType output.SinkType, path string) (string, error) {
+	host, port, database, err := engine.Endpoint(connection)
+
+	if err != nil {
+		return "", err
+	}
+
+	if port != "" {
+		host = host + ":" + port
+	}
+
+	if abs, err := filepath.Abs(path); err == nil {
+		path = abs
+	}
+
+	return state.Key(host, database, sinkType.String()+":"+path), nil
+}
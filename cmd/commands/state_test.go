@@ -0,0 +1,28 @@
+package commands
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseModifiedSince(t *testing.T) {
+	cases := []struct {
+		value     string
+		want      time.Time
+		withError bool
+	}{
+		{value: "2026-10-01", want: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{value: "2026-10-01T22:30:15", want: time.Date(2026, 10, 1, 22, 30, 15, 0, time.UTC)},
+		{value: "2026-10-01 22:30:15", want: time.Date(2026, 10, 1, 22, 30, 15, 0, time.UTC)},
+		{value: "01.10.2026", withError: true},
+		{value: "2026-10-01T22:30:15+03:00", withError: true},
+	}
+
+	for _, test := range cases {
+		have, err := ParseModifiedSince(test.value)
+
+		if !have.Equal(test.want) || (err != nil) != test.withError {
+			t.Errorf(`ParseModifiedSince("%s") failed!`, test.value)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package commands
+
+import (
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+)
+
+// ParseScriptStyle возвращает стиль скриптов создания объектов БД по его наименованию
+func ParseScriptStyle(style string) (commands.ScriptStyle, error) {
+	switch style {
+	case "", "create":
+		return commands.ScriptStyleCreate, nil
+	case "if-not-exists":
+		return commands.ScriptStyleIfNotExists, nil
+	case "create-or-alter":
+		return commands.ScriptStyleCreateOrAlter, nil
+	case "drop-create":
+		return commands.ScriptStyleDropCreate, nil
+	default:
+		return commands.ScriptStyleCreate, usageError("unknown script style %s", style)
+	}
+}
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+)
+
+var scriptStyleCases = []struct {
+	style     string
+	want      commands.ScriptStyle
+	withError bool
+}{
+	{
+		style:     "",
+		want:      commands.ScriptStyleCreate,
+		withError: false,
+	},
+	{
+		style:     "create",
+		want:      commands.ScriptStyleCreate,
+		withError: false,
+	},
+	{
+		style:     "if-not-exists",
+		want:      commands.ScriptStyleIfNotExists,
+		withError: false,
+	},
+	{
+		style:     "create-or-alter",
+		want:      commands.ScriptStyleCreateOrAlter,
+		withError: false,
+	},
+	{
+		style:     "drop-create",
+		want:      commands.ScriptStyleDropCreate,
+		withError: false,
+	},
+	{
+		style:     "alter",
+		want:      commands.ScriptStyleCreate,
+		withError: true,
+	},
+}
+
+func TestParseScriptStyle(t *testing.T) {
+	var done bool
+
+	for _, test := range scriptStyleCases {
+		have, err := ParseScriptStyle(test.style)
+		withError := err != nil
+
+		done = have == test.want && withError == test.withError
+
+		if !done {
+			t.Errorf(`ParseScriptStyle("%s") failed!`, test.style)
+		}
+	}
+}
@@ -1,7 +1,11 @@
 package commands
 
+import (
+	"context"
+)
+
 // IEngineCommand интерфейс команды "движка"
 type IEngineCommand interface {
-	// Run запускает выполнение команды
-	Run() error
+	// Run запускает выполнение команды. Выполнение прерывается при отмене контекста ctx
+	Run(ctx context.Context) error
 }
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"time"
+)
+
+// ConnectionParameters параметры соединения с сервером БД. Пустые (нулевые) значения не изменяют параметры строки
+// соединения
+type ConnectionParameters struct {
+	// Encrypt режим шифрования соединения: disable - соединение не шифруется, false - шифруется только вход на
+	// сервер, true - шифруется все соединение, а сертификат сервера проверяется
+	Encrypt string
+	// TrustServerCertificate не проверять сертификат сервера
+	TrustServerCertificate bool
+	// CAFile путь к файлу сертификатов удостоверяющих центров (PEM), которым подписан сертификат сервера
+	CAFile string
+	// HostNameInCertificate имя сервера в его сертификате, если оно отличается от имени сервера в строке соединения
+	HostNameInCertificate string
+	// AppName наименование приложения, передаваемое серверу
+	AppName string
+	// PacketSize размер сетевого пакета в байтах
+	PacketSize int
+	// ConnectionTimeout максимальное время подключения к серверу, включая вход на сервер
+	ConnectionTimeout time.Duration
+	// DialTimeout максимальное время установки сетевого соединения с сервером
+	DialTimeout time.Duration
+}
@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/filter"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/graph"
+)
+
+// Direction направление обхода графа зависимостей от корневых объектов БД
+type Direction byte
+
+const (
+	// DirectionDependencies объекты, от которых зависят корневые объекты (по умолчанию)
+	DirectionDependencies Direction = iota
+	// DirectionDependents объекты, которые зависят от корневых объектов
+	DirectionDependents
+	// DirectionBoth объекты, от которых зависят корневые объекты, и объекты, которые зависят от них
+	DirectionBoth
+)
+
+// String возвращает наименование направления обхода графа зависимостей
+func (direction Direction) String() string {
+	switch direction {
+	case DirectionDependents:
+		return "dependents"
+	case DirectionBoth:
+		return "both"
+	default:
+		return "dependencies"
+	}
+}
+
+// DependenciesOption тип параметра выполнения команды Dependencies
+type DependenciesOption func(command IDependenciesCommand)
+
+// WithRootObjects указывает ограничить граф зависимостей объектами БД, выбранными фильтром filter, и объектами,
+// связанными с ними зависимостями
+func WithRootObjects(filter filter.IFilter) DependenciesOption {
+	return func(command IDependenciesCommand) {
+		command.SetRootObjects(filter)
+	}
+}
+
+// WithDepth указывает включать в граф объекты БД не далее depth зависимостей от корневых объектов
+func WithDepth(depth int) DependenciesOption {
+	return func(command IDependenciesCommand) {
+		command.SetDepth(depth)
+	}
+}
+
+// WithDirection указывает направление обхода графа зависимостей от корневых объектов БД
+func WithDirection(direction Direction) DependenciesOption {
+	return func(command IDependenciesCommand) {
+		command.SetDirection(direction)
+	}
+}
+
+// IDependenciesCommand интерфейс команды Dependencies - построения графа зависимостей объектов БД
+type IDependenciesCommand interface {
+	IEngineCommand
+
+	// SetRootObjects устанавливает фильтр корневых объектов БД. nil - граф строится для всех объектов БД
+	SetRootObjects(filter filter.IFilter)
+	// SetDepth устанавливает максимальное количество зависимостей от корневых объектов. 0 - без ограничения
+	SetDepth(depth int)
+	// SetDirection устанавливает направление обхода графа зависимостей от корневых объектов
+	SetDirection(direction Direction)
+	// Graph возвращает граф зависимостей, построенный командой
+	Graph() *graph.Graph
+}
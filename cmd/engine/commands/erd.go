@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/erd"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/filter"
+)
+
+// ERDOption тип параметра выполнения команды ERD
+type ERDOption func(command IERDCommand)
+
+// WithSchemas указывает включить в диаграмму только таблицы схем schemas
+func WithSchemas(schemas []string) ERDOption {
+	return func(command IERDCommand) {
+		command.SetSchemas(schemas)
+	}
+}
+
+// WithTables указывает включить в диаграмму только таблицы, выбранные фильтром filter
+func WithTables(filter filter.IFilter) ERDOption {
+	return func(command IERDCommand) {
+		command.SetTables(filter)
+	}
+}
+
+// IERDCommand интерфейс команды ERD - построения диаграммы "сущность-связь" таблиц БД
+type IERDCommand interface {
+	IEngineCommand
+
+	// SetSchemas устанавливает схемы таблиц диаграммы. Пустой список - все схемы
+	SetSchemas(schemas []string)
+	// SetTables устанавливает фильтр таблиц диаграммы. nil - все таблицы
+	SetTables(filter filter.IFilter)
+	// Diagram возвращает диаграмму, построенную командой
+	Diagram() *erd.Diagram
+}
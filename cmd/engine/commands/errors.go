@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ObjectError ошибка создания или сохранения скрипта объекта БД
+type ObjectError struct {
+	// Object наименование объекта БД
+	Object string
+	// Err причина ошибки
+	Err error
+}
+
+// NewObjectError конструктор ObjectError
+func NewObjectError(object string, err error) *ObjectError {
+	return &ObjectError{Object: object, Err: err}
+}
+
+// Error возвращает текст ошибки
+func (e *ObjectError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Object, e.Err)
+}
+
+// Unwrap возвращает причину ошибки
+func (e *ObjectError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError ошибки, возникшие при выполнении команды. Команда не прерывается на первой ошибке и возвращает все
+// ошибки, если не указан режим WithFailFast
+type MultiError []error
+
+// Error возвращает текст ошибки со списком всех ошибок
+func (errs MultiError) Error() string {
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+
+	messages := make([]string, len(errs))
+
+	for index, err := range errs {
+		messages[index] = "\t* " + err.Error()
+	}
+
+	return fmt.Sprintf("%d errors occurred:\n%s", len(errs), strings.Join(messages, "\n"))
+}
+
+// Objects возвращает наименования объектов БД, при обработке которых возникли ошибки
+func (errs MultiError) Objects() []string {
+	objects := make([]string, 0)
+
+	for _, err := range errs {
+		if objectErr, ok := err.(*ObjectError); ok {
+			objects = append(objects, objectErr.Object)
+		}
+	}
+
+	return objects
+}
+
+// Is проверяет, соответствует ли target одна из ошибок списка
+func (errs MultiError) Is(target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As находит в списке первую ошибку, соответствующую target, и присваивает ее target
+func (errs MultiError) As(target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ErrorOrNil возвращает nil, если ошибок нет
+func (errs MultiError) ErrorOrNil() error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
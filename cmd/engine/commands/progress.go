@@ -0,0 +1,56 @@
+package commands
+
+// Phase этап выполнения команды
+type Phase byte
+
+const (
+	// PhaseMetadata чтение метаданных БД
+	PhaseMetadata Phase = iota
+	// PhaseEnumeration получение списка объектов БД
+	PhaseEnumeration
+	// PhaseRendering создание скриптов объектов БД
+	PhaseRendering
+	// PhaseWriting сохранение скриптов объектов БД
+	PhaseWriting
+	// PhaseDone выполнение команды завершено
+	PhaseDone
+)
+
+// String возвращает наименование этапа
+func (phase Phase) String() string {
+	switch phase {
+	case PhaseMetadata:
+		return "metadata reading"
+	case PhaseEnumeration:
+		return "object enumeration"
+	case PhaseRendering:
+		return "rendering"
+	case PhaseWriting:
+		return "writing"
+	default:
+		return "done"
+	}
+}
+
+// ProgressEvent событие хода выполнения команды
+type ProgressEvent struct {
+	// Phase этап выполнения команды
+	Phase Phase
+	// Object наименование обработанного объекта БД (для этапов создания и сохранения скриптов)
+	Object string
+	// Done количество обработанных элементов этапа
+	Done int
+	// Total общее количество элементов этапа (0, если неизвестно)
+	Total int
+}
+
+// ProgressCallback тип callback-функции, вызываемой при изменении хода выполнения команды. Вызовы callback-функции
+// не пересекаются по времени, даже если скрипты создаются параллельно
+type ProgressCallback func(event ProgressEvent)
+
+// WithProgressCallback устанавливает callback для отслеживания хода выполнения команды
+func WithProgressCallback(fn ProgressCallback) ScriptsFolderOption {
+	return func(command IScriptsFolderCommand) {
+		command.SetProgressCallback(fn)
+	}
+}
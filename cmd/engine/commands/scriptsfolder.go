@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"time"
+
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/filter"
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
 )
@@ -24,9 +26,40 @@ func WithExcludedObjects(filter filter.IFilter) ScriptsFolderOption {
 	}
 }
 
+// WithDependencies указывает добавить к объектам БД, выбранным фильтрами, объекты, от которых они зависят прямо или
+// косвенно (таблицы, типы, представления, функции и т.п.), чтобы скрипты можно было развернуть
+func WithDependencies() ScriptsFolderOption {
+	return func(command IScriptsFolderCommand) {
+		command.IncludeDependencies(true)
+	}
+}
+
+// WithDependents указывает добавить к объектам БД, выбранным фильтрами, объекты, которые зависят от них прямо или
+// косвенно
+func WithDependents() ScriptsFolderOption {
+	return func(command IScriptsFolderCommand) {
+		command.IncludeDependents(true)
+	}
+}
+
+// WithObjectDependencies указывает передавать в скриптах объектов БД объекты, от которых они непосредственно зависят
+// (например, для упорядочивания скриптов в едином скрипте развертывания)
+func WithObjectDependencies() ScriptsFolderOption {
+	return func(command IScriptsFolderCommand) {
+		command.ObjectDependencies(true)
+	}
+}
+
+// WithModifiedSince указывает создавать скрипты только объектов БД, измененных начиная с времени since по часам
+// сервера БД
+func WithModifiedSince(since time.Time) ScriptsFolderOption {
+	return func(command IScriptsFolderCommand) {
+		command.SetModifiedSince(since)
+	}
+}
+
 // ObjectDefinitionCallback тип callback-функции, вызываемой при чтении определения объекта БД
-type ObjectDefinitionCallback func(objectCatalog, objectSchema, objectName string, objectType output.DatabaseObjectType,
-	objectDefinition []byte) error
+type ObjectDefinitionCallback func(object output.ObjectDefinition) error
 
 // WithObjectDefinitionCallback устанавливает callback для чтения определений объектов БД
 func WithObjectDefinitionCallback(fn ObjectDefinitionCallback) ScriptsFolderOption {
@@ -35,6 +68,16 @@ func WithObjectDefinitionCallback(fn ObjectDefinitionCallback) ScriptsFolderOpti
 	}
 }
 
+// SkippedObjectCallback тип callback-функции, вызываемой для объекта БД, скрипт которого не создан
+type SkippedObjectCallback func(object output.ObjectDefinition, reason SkipReason)
+
+// WithSkippedObjectCallback устанавливает callback для объектов БД, скрипты которых не созданы
+func WithSkippedObjectCallback(fn SkippedObjectCallback) ScriptsFolderOption {
+	return func(command IScriptsFolderCommand) {
+		command.SetSkippedObjectCallback(fn)
+	}
+}
+
 // WithStaticData опция выгрузки скриптов вставки данных в таблицы
 func WithStaticData() ScriptsFolderOption {
 	return func(command IScriptsFolderCommand) {
@@ -63,6 +106,72 @@ func WithSkipPermissions() ScriptsFolderOption {
 	}
 }
 
+// WithTargetVersion указывает целевую версию СУБД, для которой создаются скрипты
+func WithTargetVersion(version string) ScriptsFolderOption {
+	return func(command IScriptsFolderCommand) {
+		command.SetTargetVersion(version)
+	}
+}
+
+// ScriptStyle стиль скриптов создания объектов БД
+type ScriptStyle byte
+
+const (
+	// ScriptStyleCreate простые инструкции CREATE (по умолчанию)
+	ScriptStyleCreate ScriptStyle = iota
+	// ScriptStyleIfNotExists инструкции CREATE с предварительной проверкой существования объекта БД
+	ScriptStyleIfNotExists
+	// ScriptStyleCreateOrAlter инструкции CREATE OR ALTER для SQL модулей, для остальных объектов БД - инструкции
+	// CREATE с предварительной проверкой существования объекта БД
+	ScriptStyleCreateOrAlter
+	// ScriptStyleDropCreate удаление объекта БД (DROP IF EXISTS) перед его созданием
+	ScriptStyleDropCreate
+)
+
+// String возвращает строковое представление стиля скриптов
+func (style ScriptStyle) String() string {
+	switch style {
+	case ScriptStyleIfNotExists:
+		return "if-not-exists"
+	case ScriptStyleCreateOrAlter:
+		return "create-or-alter"
+	case ScriptStyleDropCreate:
+		return "drop-create"
+	default:
+		return "create"
+	}
+}
+
+// WithScriptStyle указывает стиль скриптов создания объектов БД
+func WithScriptStyle(style ScriptStyle) ScriptsFolderOption {
+	return func(command IScriptsFolderCommand) {
+		command.SetScriptStyle(style)
+	}
+}
+
+// WithTemplates указывает пользовательские шаблоны скриптов объектов БД
+func WithTemplates(templates output.Templates) ScriptsFolderOption {
+	return func(command IScriptsFolderCommand) {
+		command.SetTemplates(templates)
+	}
+}
+
+// WithParallel указывает количество объектов БД, скрипты которых создаются параллельно. Скрипты передаются в
+// callback в исходном порядке объектов БД
+func WithParallel(parallel int) ScriptsFolderOption {
+	return func(command IScriptsFolderCommand) {
+		command.SetParallel(parallel)
+	}
+}
+
+// WithFailFast указывает прервать выполнение команды на первой ошибке создания или сохранения скрипта. По умолчанию
+// команда обрабатывает все объекты БД и возвращает MultiError со списком ошибок
+func WithFailFast() ScriptsFolderOption {
+	return func(command IScriptsFolderCommand) {
+		command.FailFast(true)
+	}
+}
+
 // IScriptsFolderCommand интерфейс команды ScriptsFolder
 type IScriptsFolderCommand interface {
 	IEngineCommand
@@ -73,8 +182,21 @@ type IScriptsFolderCommand interface {
 	// SetExcludedObjects устанавливает фильтр, позволяющий игнорировать объекты БД, которые должны быть заторонуты
 	// обработкой
 	SetExcludedObjects(filter filter.IFilter)
+	// IncludeDependencies включает/выключает добавление к выбранным объектам БД объектов, от которых они зависят
+	IncludeDependencies(on bool)
+	// IncludeDependents включает/выключает добавление к выбранным объектам БД объектов, которые зависят от них
+	IncludeDependents(on bool)
+	// ObjectDependencies включает/выключает передачу в скриптах объектов БД объектов, от которых они непосредственно
+	// зависят (output.ObjectDefinition.Dependencies)
+	ObjectDependencies(on bool)
+	// SetModifiedSince устанавливает время по часам сервера БД, начиная с которого должны быть изменены объекты БД,
+	// скрипты которых создаются. Нулевое время - без ограничения
+	SetModifiedSince(since time.Time)
 	// SetObjectDefinitionCallback устанавливает callback для чтения определений объектов БД
 	SetObjectDefinitionCallback(callback ObjectDefinitionCallback)
+	// SetSkippedObjectCallback устанавливает callback для объектов БД, скрипты которых не созданы. Callback может
+	// вызываться одновременно из нескольких горутин
+	SetSkippedObjectCallback(callback SkippedObjectCallback)
 	// StaticData опция выгрузки скриптов вставки данных
 	StaticData(on bool)
 	// Decrypt по возможности расшифровывать определения объектов БД
@@ -83,4 +205,26 @@ type IScriptsFolderCommand interface {
 	SetDatabaseObjectTypes(types []output.DatabaseObjectType)
 	// SkipPermissions не добавлять в скрипты разрешения на объект
 	SkipPermissions(on bool)
+	// SetTargetVersion устанавливает целевую версию СУБД, для которой создаются скрипты
+	SetTargetVersion(version string)
+	// SetScriptStyle устанавливает стиль скриптов создания объектов БД
+	SetScriptStyle(style ScriptStyle)
+	// SetTemplates устанавливает пользовательские шаблоны скриптов объектов БД
+	SetTemplates(templates output.Templates)
+	// SetParallel устанавливает количество объектов БД, скрипты которых создаются параллельно
+	SetParallel(parallel int)
+	// FailFast включает/выключает прерывание выполнения команды на первой ошибке
+	FailFast(on bool)
+	// SetProgressCallback устанавливает callback для отслеживания хода выполнения команды
+	SetProgressCallback(fn ProgressCallback)
+	// Warnings возвращает предупреждения, сформированные при выполнении команды (например, об объектах, которые не
+	// могут быть выражены на целевой версии СУБД)
+	Warnings() []string
+	// Skipped возвращает объекты БД, скрипты которых не созданы, с причинами
+	Skipped() []SkippedObject
+	// DatabaseName возвращает наименование БД, для которой создаются скрипты
+	DatabaseName() string
+	// ServerTime возвращает время сервера БД на момент начала выполнения команды. Объекты БД, измененные после
+	// этого времени, могут быть изменены во время выполнения команды
+	ServerTime() time.Time
 }
@@ -0,0 +1,51 @@
+package commands
+
+import (
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+// SkipReason причина, по которой скрипт объекта БД не создан
+type SkipReason byte
+
+const (
+	// SkipFiltered объект БД исключен фильтрами или отсутствует в описании структуры каталога скриптов
+	SkipFiltered SkipReason = iota
+	// SkipEncrypted определение объекта БД зашифровано
+	SkipEncrypted
+	// SkipUnsupported создание скриптов объектов БД этого типа не поддерживается
+	SkipUnsupported
+	// SkipUnchanged объект БД не изменялся после указанного времени
+	SkipUnchanged
+)
+
+// String возвращает строковое представление причины
+func (reason SkipReason) String() string {
+	switch reason {
+	case SkipEncrypted:
+		return "encrypted"
+	case SkipUnsupported:
+		return "unsupported"
+	case SkipUnchanged:
+		return "unchanged"
+	default:
+		return "filtered"
+	}
+}
+
+// SkippedObject объект БД, скрипт которого не создан
+type SkippedObject struct {
+	// Object наименование объекта БД
+	Object string
+	// Type тип объекта БД
+	Type output.DatabaseObjectType
+	// Reason причина, по которой скрипт не создан
+	Reason SkipReason
+}
+
+// ServerInfo сведения о сервере БД
+type ServerInfo struct {
+	// Version версия сервера
+	Version string
+	// Edition редакция сервера
+	Edition string
+}
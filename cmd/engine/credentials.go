@@ -1,6 +1,7 @@
 package engine
 
 import (
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
 	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/sqlserver"
 )
 
@@ -35,3 +36,35 @@ func SetCredentials(connection, username, password string) (string, error) {
 		return "", ErrorUnsupportedDatabaseType
 	}
 }
+
+// Endpoint возвращает сервер, порт и наименование БД, извлеченные из строки соединения с базой данных
+func Endpoint(connection string) (host, port, database string, err error) {
+	rdbms, err := RDBMS(connection)
+
+	if err != nil {
+		return "", "", "", err
+	}
+
+	switch rdbms {
+	case RDBMSSQLServer:
+		return sqlserver.Endpoint(connection)
+	default:
+		return "", "", "", ErrorUnsupportedDatabaseType
+	}
+}
+
+// SetConnectionParameters проверяет параметры соединения params и заменяет ими параметры строки соединения с БД
+func SetConnectionParameters(connection string, params commands.ConnectionParameters) (string, error) {
+	rdbms, err := RDBMS(connection)
+
+	if err != nil {
+		return "", err
+	}
+
+	switch rdbms {
+	case RDBMSSQLServer:
+		return sqlserver.SetConnectionParameters(connection, params)
+	default:
+		return "", ErrorUnsupportedDatabaseType
+	}
+}
@@ -1,6 +1,9 @@
 package engine
 
 import (
+	"context"
+	"time"
+
 	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
 	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/sqlserver"
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/log"
@@ -10,8 +13,18 @@ import (
 type IEngine interface {
 	// SetLogger устанавливает логгер событий
 	SetLogger(logger log.ILogger)
+	// SetQueryTimeout устанавливает максимальное время выполнения одного запроса к серверу
+	SetQueryTimeout(timeout time.Duration)
+	// Connect подключается к серверу БД
+	Connect(ctx context.Context) error
+	// Server возвращает сведения о сервере БД, к которому подключен "движок"
+	Server() commands.ServerInfo
 	// ScriptsFolder создает скрипты объектов БД по указанному пути path
 	ScriptsFolder(options ...commands.ScriptsFolderOption) commands.IScriptsFolderCommand
+	// Dependencies строит граф зависимостей объектов БД
+	Dependencies(options ...commands.DependenciesOption) commands.IDependenciesCommand
+	// ERD строит диаграмму "сущность-связь" таблиц БД
+	ERD(options ...commands.ERDOption) commands.IERDCommand
 }
 
 // Option опция "движка" базы данных
@@ -24,8 +37,15 @@ func WithLogger(logger log.ILogger) Option {
 	}
 }
 
-// New возвращает экземпляр "движка" БД
-func New(connection string, options ...Option) (IEngine, error) {
+// WithQueryTimeout указывает "движку" ограничить время выполнения каждого запроса к серверу таймаутом timeout
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(engine IEngine) {
+		engine.SetQueryTimeout(timeout)
+	}
+}
+
+// New возвращает экземпляр "движка" БД, подключенный к серверу. Подключение прерывается при отмене контекста ctx
+func New(ctx context.Context, connection string, options ...Option) (IEngine, error) {
 	engn, err := engine(connection)
 
 	if err != nil {
@@ -36,6 +56,10 @@ func New(connection string, options ...Option) (IEngine, error) {
 		option(engn)
 	}
 
+	if err = engn.Connect(ctx); err != nil {
+		return nil, err
+	}
+
 	return engn, nil
 }
 
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -622,7 +623,7 @@ func (col *Column) SetOptions(options ...ColumnOption) {
 // Columns поля
 type Columns map[string]*Column
 
-// Slice возвращает срез полей
+// Slice возвращает срез полей, упорядоченный по идентификатору поля
 func (columns Columns) Slice() []*Column {
 	length := len(columns)
 
@@ -639,6 +640,10 @@ func (columns Columns) Slice() []*Column {
 		index++
 	}
 
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+
 	return list
 }
 
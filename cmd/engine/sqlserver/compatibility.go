@@ -0,0 +1,179 @@
+package sqlserver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+// moduleFeature возможность T-SQL, используемая в определении SQL модуля и доступная начиная с версии version
+type moduleFeature struct {
+	pattern *regexp.Regexp
+	name    string
+	version int
+}
+
+var moduleFeatures = []moduleFeature{
+	{
+		pattern: regexp.MustCompile(`(?i)\bstring_agg\s*\(`),
+		name:    "STRING_AGG",
+		version: ServerVersion2017,
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)\bconcat_ws\s*\(`),
+		name:    "CONCAT_WS",
+		version: ServerVersion2017,
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)\btranslate\s*\(`),
+		name:    "TRANSLATE",
+		version: ServerVersion2017,
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)\btrim\s*\(`),
+		name:    "TRIM",
+		version: ServerVersion2017,
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)\bapprox_count_distinct\s*\(`),
+		name:    "APPROX_COUNT_DISTINCT",
+		version: ServerVersion2019,
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)\bgreatest\s*\(`),
+		name:    "GREATEST",
+		version: ServerVersion2022,
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)\bleast\s*\(`),
+		name:    "LEAST",
+		version: ServerVersion2022,
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)\bgenerate_series\s*\(`),
+		name:    "GENERATE_SERIES",
+		version: ServerVersion2022,
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)\bdatetrunc\s*\(`),
+		name:    "DATETRUNC",
+		version: ServerVersion2022,
+	},
+	{
+		pattern: regexp.MustCompile(`(?i)\bdate_bucket\s*\(`),
+		name:    "DATE_BUCKET",
+		version: ServerVersion2022,
+	},
+}
+
+// createOrAlterSupported проверяет, поддерживает ли целевая версия SQL Server инструкцию CREATE OR ALTER. В SQL Server
+// 2016 она доступна только начиная с SP1, поэтому для версии 2016 считается недоступной
+func (command *ScriptsFolderCommand) createOrAlterSupported() bool {
+	return command.targetServerVersion == 0 || command.targetServerVersion >= ServerVersion2017
+}
+
+// adaptedModuleDefinition возвращает определение SQL модуля, адаптированное к целевой версии SQL Server: если
+// CREATE OR ALTER не поддерживается, то заголовок CREATE OR ALTER заменяется на CREATE
+func (command *ScriptsFolderCommand) adaptedModuleDefinition(definition string) string {
+	if command.createOrAlterSupported() {
+		return definition
+	}
+
+	return createOrAlterPattern.ReplaceAllString(definition, "${1}CREATE")
+}
+
+// adaptIndexes адаптирует определения индексов indexes к целевой версии SQL Server: параметры индексов, которые она
+// не поддерживает (OPTIMIZE_FOR_SEQUENTIAL_KEY для версий младше 2019), не записываются в скрипты
+func (command *ScriptsFolderCommand) adaptIndexes(indexes ObjectsIndexes) {
+	for _, objectIndexes := range indexes {
+		for _, index := range objectIndexes {
+			index.SetOptions(WithIndexTargetVersion(command.targetServerVersion))
+		}
+	}
+}
+
+// isUTF8Collation проверяет, является ли collation UTF-8 collation (SQL Server 2019+)
+func isUTF8Collation(collation string) bool {
+	return strings.HasSuffix(strings.ToUpper(collation), "_UTF8")
+}
+
+// targetVersionIssues возвращает список причин, по которым объект БД не может быть выражен на целевой версии
+// SQL Server. Если целевая версия не указана, то возвращается пустой список
+func (command *ScriptsFolderCommand) targetVersionIssues(object IDatabaseObject) []string {
+	if command.targetServerVersion == 0 {
+		return nil
+	}
+
+	issues := make([]string, 0)
+
+	switch object.Type() {
+	case output.Table:
+		issues = append(issues, command.tableTargetVersionIssues(object.SchemaAndName(true))...)
+	case output.UserDefinedTableType:
+		issues = append(issues, command.columnsTargetVersionIssues(object.SchemaAndName(true))...)
+	case output.UserDefinedDataType:
+		if domain, ok := command.userDefinedTypes[object.SchemaAndName(true)]; ok {
+			if domain.HasCollation() && isUTF8Collation(domain.Collation()) &&
+				command.targetServerVersion < ServerVersion2019 {
+				issues = append(issues, fmt.Sprintf("UTF-8 collation %s requires %s", domain.Collation(),
+					ServerVersionName(ServerVersion2019)))
+			}
+		}
+	case output.Procedure, output.Function, output.View, output.Trigger:
+		issues = append(issues, command.moduleTargetVersionIssues(string(object.Definition()))...)
+	}
+
+	return issues
+}
+
+func (command *ScriptsFolderCommand) tableTargetVersionIssues(name string) []string {
+	issues := command.columnsTargetVersionIssues(name)
+
+	if table, ok := command.tables[name]; ok {
+		if (table.IsNode || table.IsEdge) && command.targetServerVersion < ServerVersion2017 {
+			issues = append(issues, fmt.Sprintf("graph tables require %s", ServerVersionName(ServerVersion2017)))
+		}
+
+		if table.HasHistoryRetentionPeriod() && command.targetServerVersion < ServerVersion2017 {
+			issues = append(issues, fmt.Sprintf("HISTORY_RETENTION_PERIOD requires %s",
+				ServerVersionName(ServerVersion2017)))
+		}
+	}
+
+	return issues
+}
+
+func (command *ScriptsFolderCommand) columnsTargetVersionIssues(name string) []string {
+	issues := make([]string, 0)
+
+	if command.targetServerVersion >= ServerVersion2019 {
+		return issues
+	}
+
+	for _, column := range command.columns[name].Slice() {
+		if column.HasCollation() && isUTF8Collation(column.Collation()) {
+			issues = append(issues, fmt.Sprintf("UTF-8 collation %s of column [%s] requires %s",
+				column.Collation(), column.Name, ServerVersionName(ServerVersion2019)))
+		}
+	}
+
+	return issues
+}
+
+func (command *ScriptsFolderCommand) moduleTargetVersionIssues(definition string) []string {
+	issues := make([]string, 0)
+
+	for _, feature := range moduleFeatures {
+		if command.targetServerVersion >= feature.version {
+			continue
+		}
+
+		if feature.pattern.MatchString(definition) {
+			issues = append(issues, fmt.Sprintf("%s requires %s", feature.name, ServerVersionName(feature.version)))
+		}
+	}
+
+	return issues
+}
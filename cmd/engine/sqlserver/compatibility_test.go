@@ -0,0 +1,158 @@
+package sqlserver
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestScriptsFolderCommand_moduleTargetVersionIssues(t *testing.T) {
+	var cases = []struct {
+		definition    string
+		targetVersion int
+		want          []string
+	}{
+		{
+			definition:    "CREATE PROCEDURE [dbo].[Test] AS SELECT STRING_AGG(name, ',') FROM sys.objects",
+			targetVersion: ServerVersion2019,
+			want:          []string{},
+		},
+		{
+			definition:    "CREATE PROCEDURE [dbo].[Test] AS SELECT STRING_AGG(name, ',') FROM sys.objects",
+			targetVersion: ServerVersion2016,
+			want:          []string{"STRING_AGG requires SQL Server 2017"},
+		},
+		{
+			definition: "-- header\n/* comment */\n" +
+				"create or alter view [dbo].[Test] as select trim(name) as name from t",
+			targetVersion: ServerVersion2016,
+			want:          []string{"TRIM requires SQL Server 2017"},
+		},
+		{
+			definition:    "CREATE FUNCTION [dbo].[Test]() RETURNS INT AS BEGIN RETURN GREATEST(1, 2) END",
+			targetVersion: ServerVersion2019,
+			want:          []string{"GREATEST requires SQL Server 2022"},
+		},
+		{
+			definition:    "CREATE VIEW [dbo].[Test] AS SELECT [ltrim_value] = LTRIM(name) FROM t",
+			targetVersion: ServerVersion2016,
+			want:          []string{},
+		},
+	}
+
+	for _, test := range cases {
+		command := &ScriptsFolderCommand{targetServerVersion: test.targetVersion}
+		have := command.moduleTargetVersionIssues(test.definition)
+
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("moduleTargetVersionIssues(%q) failed: have %v, want %v", test.definition, have, test.want)
+		}
+	}
+}
+
+func TestScriptsFolderCommand_tableTargetVersionIssues(t *testing.T) {
+	command := &ScriptsFolderCommand{
+		targetServerVersion: ServerVersion2016,
+		tables: Tables{
+			"[dbo].[Test]": &Table{
+				Schema: "dbo",
+				Name:   "Test",
+				IsNode: true,
+			},
+		},
+		columns: ObjectColumns{
+			"[dbo].[Test]": Columns{
+				"value": &Column{
+					ID:       2,
+					Name:     "value",
+					TypeName: "varchar",
+					collation: sql.NullString{
+						String: "Latin1_General_100_CI_AS_SC_UTF8",
+						Valid:  true,
+					},
+				},
+				"key": &Column{
+					ID:       1,
+					Name:     "key",
+					TypeName: "int",
+				},
+			},
+		},
+	}
+
+	want := []string{
+		"UTF-8 collation Latin1_General_100_CI_AS_SC_UTF8 of column [value] requires SQL Server 2019",
+		"graph tables require SQL Server 2017",
+	}
+
+	have := command.tableTargetVersionIssues("[dbo].[Test]")
+
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("tableTargetVersionIssues failed: have %v, want %v", have, want)
+	}
+
+	command.targetServerVersion = ServerVersion2019
+
+	if have = command.tableTargetVersionIssues("[dbo].[Test]"); len(have) > 0 {
+		t.Errorf("tableTargetVersionIssues failed: have %v, want no issues", have)
+	}
+}
+
+func TestScriptsFolderCommand_adaptedModuleDefinition(t *testing.T) {
+	var cases = []struct {
+		definition    string
+		targetVersion int
+		want          string
+	}{
+		{
+			definition:    "-- header\ncreate or alter view [dbo].[Test] as select 1 as [one]",
+			targetVersion: 0,
+			want:          "-- header\ncreate or alter view [dbo].[Test] as select 1 as [one]",
+		},
+		{
+			definition:    "-- header\ncreate or alter view [dbo].[Test] as select 1 as [one]",
+			targetVersion: ServerVersion2017,
+			want:          "-- header\ncreate or alter view [dbo].[Test] as select 1 as [one]",
+		},
+		{
+			definition:    "-- header\ncreate or alter view [dbo].[Test] as select 1 as [one]",
+			targetVersion: ServerVersion2016,
+			want:          "-- header\nCREATE view [dbo].[Test] as select 1 as [one]",
+		},
+		{
+			definition:    "CREATE VIEW [dbo].[Test] AS SELECT 'create or alter' AS [one]",
+			targetVersion: ServerVersion2016,
+			want:          "CREATE VIEW [dbo].[Test] AS SELECT 'create or alter' AS [one]",
+		},
+	}
+
+	for _, test := range cases {
+		command := &ScriptsFolderCommand{targetServerVersion: test.targetVersion}
+
+		if have := command.adaptedModuleDefinition(test.definition); have != test.want {
+			t.Errorf("adaptedModuleDefinition(%q) failed: have %q, want %q", test.definition, have, test.want)
+		}
+	}
+}
+
+func TestScriptsFolderCommand_adaptIndexes(t *testing.T) {
+	var cases = []struct {
+		targetVersion int
+		want          []string
+	}{
+		{targetVersion: 0, want: []string{"OPTIMIZE_FOR_SEQUENTIAL_KEY = ON"}},
+		{targetVersion: ServerVersion2019, want: []string{"OPTIMIZE_FOR_SEQUENTIAL_KEY = ON"}},
+		{targetVersion: ServerVersion2017, want: []string{}},
+	}
+
+	for _, test := range cases {
+		index := &Index{Name: "IX_Orders", Type: "NONCLUSTERED", OptimizeForSequentialKey: true}
+
+		command := &ScriptsFolderCommand{targetServerVersion: test.targetVersion}
+		command.adaptIndexes(ObjectsIndexes{"[dbo].[Orders]": Indexes{index.Name: index}})
+
+		if have := index.Flags(); !reflect.DeepEqual(have, test.want) {
+			t.Errorf("target version %d: have %v, want %v", test.targetVersion, have, test.want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package sqlserver
+
+import (
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+)
+
+const (
+	// MinPacketSize минимальный размер сетевого пакета TDS
+	MinPacketSize = 512
+	// MaxPacketSize максимальный размер сетевого пакета TDS
+	MaxPacketSize = 32767
+)
+
+// SetConnectionParameters проверяет параметры соединения params и заменяет ими параметры строки соединения с БД.
+// Указание файла сертификатов или имени сервера в сертификате без режима шифрования включает шифрование соединения с
+// проверкой сертификата сервера
+func SetConnectionParameters(connection string, params commands.ConnectionParameters) (string, error) {
+	values, err := connectionParameters(params)
+
+	if err != nil {
+		return connection, err
+	}
+
+	if len(values) == 0 {
+		return connection, nil
+	}
+
+	u, err := url.Parse(connection)
+
+	if err != nil {
+		return connection, err
+	}
+
+	q, err := url.ParseQuery(u.RawQuery)
+
+	if err != nil {
+		return connection, err
+	}
+
+	// драйвер не различает регистр наименований параметров, поэтому заменяются параметры с любым регистром
+	for key := range q {
+		if _, ok := values[strings.ToLower(key)]; ok {
+			q.Del(key)
+		}
+	}
+
+	for key, value := range values {
+		q.Set(key, value)
+	}
+
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
+
+// connectionParameters возвращает параметры строки соединения, соответствующие params
+func connectionParameters(params commands.ConnectionParameters) (map[string]string, error) {
+	values := make(map[string]string)
+
+	encrypt := strings.ToLower(strings.TrimSpace(params.Encrypt))
+
+	switch encrypt {
+	case "", "true", "false", "disable":
+	default:
+		return nil, fmt.Errorf("unknown encrypt mode %s, want disable, false or true", params.Encrypt)
+	}
+
+	verifiable := params.CAFile != "" || params.HostNameInCertificate != ""
+
+	if verifiable && encrypt == "disable" {
+		return nil, fmt.Errorf("a CA file and a host name in the certificate cannot be used with encrypt mode disable")
+	}
+
+	if verifiable && params.TrustServerCertificate {
+		return nil, fmt.Errorf("a CA file and a host name in the certificate cannot be used when the server " +
+			"certificate is trusted without verification")
+	}
+
+	if encrypt == "" && verifiable {
+		encrypt = "true"
+	}
+
+	if encrypt != "" {
+		values["encrypt"] = encrypt
+	}
+
+	if params.TrustServerCertificate {
+		values["trustservercertificate"] = "true"
+	} else if verifiable {
+		values["trustservercertificate"] = "false"
+	}
+
+	if params.CAFile != "" {
+		if err := checkCAFile(params.CAFile); err != nil {
+			return nil, err
+		}
+
+		values["certificate"] = params.CAFile
+	}
+
+	if params.HostNameInCertificate != "" {
+		values["hostnameincertificate"] = params.HostNameInCertificate
+	}
+
+	if params.AppName != "" {
+		values["app name"] = params.AppName
+	}
+
+	if params.PacketSize != 0 {
+		if params.PacketSize < MinPacketSize || params.PacketSize > MaxPacketSize {
+			return nil, fmt.Errorf("packet size %d is out of range %d-%d", params.PacketSize, MinPacketSize,
+				MaxPacketSize)
+		}
+
+		values["packet size"] = strconv.Itoa(params.PacketSize)
+	}
+
+	if err := setTimeout(values, "connection timeout", params.ConnectionTimeout); err != nil {
+		return nil, err
+	}
+
+	if err := setTimeout(values, "dial timeout", params.DialTimeout); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// setTimeout записывает в параметры строки соединения таймаут timeout в секундах, округляя его вверх
+func setTimeout(values map[string]string, name string, timeout time.Duration) error {
+	if timeout < 0 {
+		return fmt.Errorf("%s must not be negative", name)
+	}
+
+	if timeout == 0 {
+		return nil
+	}
+
+	seconds := (timeout + time.Second - 1) / time.Second
+	values[name] = strconv.FormatInt(int64(seconds), 10)
+
+	return nil
+}
+
+// checkCAFile проверяет, что файл path содержит хотя бы один сертификат в формате PEM
+func checkCAFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		return fmt.Errorf("failed to read the CA file: %w", err)
+	}
+
+	if !x509.NewCertPool().AppendCertsFromPEM(data) {
+		return fmt.Errorf("the CA file %s does not contain PEM certificates", path)
+	}
+
+	return nil
+}
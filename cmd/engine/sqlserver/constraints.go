@@ -31,6 +31,14 @@ func WithIndexOwner(owner ColumnOrIndexOwner) IndexOption {
 	}
 }
 
+// WithIndexTargetVersion целевая версия SQL Server, для которой создается определение индекса (по умолчанию - версия
+// не ограничена)
+func WithIndexTargetVersion(version int) IndexOption {
+	return func(col *Index) {
+		col.targetVersion = version
+	}
+}
+
 // Index определение индекса
 // (https://docs.microsoft.com/en-us/sql/relational-databases/system-catalog-views/sys-indexes-transact-sql)
 type Index struct {
@@ -76,7 +84,8 @@ type Index struct {
 	bucketCount      sql.NullInt64
 	description      sql.NullString
 
-	owner ColumnOrIndexOwner
+	owner         ColumnOrIndexOwner
+	targetVersion int
 }
 
 // BucketCount возвращает число контейнеров, которые необходимо создать в хэш-индексе
@@ -161,6 +170,12 @@ func (index Index) Flags() []string {
 		flags = append(flags, "IGNORE_DUP_KEY = ON")
 	}
 
+	if index.OptimizeForSequentialKey && index.owner != OwnerUserDefinedTableDataType {
+		if index.targetVersion == 0 || index.targetVersion >= ServerVersion2019 {
+			flags = append(flags, "OPTIMIZE_FOR_SEQUENTIAL_KEY = ON")
+		}
+	}
+
 	return flags
 }
 
@@ -215,10 +230,6 @@ func (index Index) constraintDefinitionForTableType() string {
 	columns := index.Columns.Slice()
 
 	if len(columns) > 0 {
-		sort.Slice(columns, func(i, j int) bool {
-			return columns[i].KeyOrdinal < columns[j].KeyOrdinal
-		})
-
 		ct := columns.Join(true, ", ")
 
 		builder.WriteSpace()
@@ -237,10 +248,6 @@ func (index Index) constraintDefinitionForTableType() string {
 	includedColumns := index.IncludedColumns.Slice()
 
 	if len(includedColumns) > 0 {
-		sort.Slice(includedColumns, func(i, j int) bool {
-			return includedColumns[i].KeyOrdinal < includedColumns[j].KeyOrdinal
-		})
-
 		ict := includedColumns.Join(true, ", ")
 
 		builder.WriteSpace()
@@ -309,7 +316,8 @@ func (columns IndexedColumnsSlice) Join(useBrackets bool, sep string) string {
 // IndexedColumns тип справочника индексируемых полей. Ключ справочника - наименование поля
 type IndexedColumns map[string]*IndexedColumn
 
-// Slice возвращает срез индексируемых полей
+// Slice возвращает срез индексируемых полей, упорядоченный по порядковому номеру внутри набора ключевых столбцов и
+// идентификатору поля в индексе
 func (columns IndexedColumns) Slice() IndexedColumnsSlice {
 	if len(columns) == 0 {
 		return nil
@@ -323,6 +331,18 @@ func (columns IndexedColumns) Slice() IndexedColumnsSlice {
 		i++
 	}
 
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].KeyOrdinal != out[j].KeyOrdinal {
+			return out[i].KeyOrdinal < out[j].KeyOrdinal
+		}
+
+		if out[i].ID != out[j].ID {
+			return out[i].ID < out[j].ID
+		}
+
+		return out[i].Name < out[j].Name
+	})
+
 	return out
 }
 
@@ -357,7 +377,7 @@ func (indexes Indexes) filterByIndexType(indexType IndexType) Indexes {
 	return out
 }
 
-// Slice возвращает срез индексов объекта БД
+// Slice возвращает срез индексов объекта БД, упорядоченный по наименованию индекса
 func (indexes Indexes) Slice() []*Index {
 	if len(indexes) == 0 {
 		return nil
@@ -371,6 +391,10 @@ func (indexes Indexes) Slice() []*Index {
 		i++
 	}
 
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
 	return out
 }
 
@@ -416,6 +440,25 @@ func (fk ForeignKey) Description() string {
 	return ""
 }
 
+// References возвращает ссылки ключевых полей, упорядоченные по идентификатору ссылки
+func (fk ForeignKey) References() []*ColumnReference {
+	if len(fk.ColumnsReferences) == 0 {
+		return nil
+	}
+
+	out := make([]*ColumnReference, 0, len(fk.ColumnsReferences))
+
+	for _, reference := range fk.ColumnsReferences {
+		out = append(out, reference)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].ID < out[j].ID
+	})
+
+	return out
+}
+
 // ColumnReference ссылка поля на поле внешнего объекта
 type ColumnReference struct {
 	// ID идентификатор ссылки
@@ -429,7 +472,7 @@ type ColumnReference struct {
 // ForeignKeys тип справочника определений внешних ключей. Ключ справочника - наименование внешнего ключа
 type ForeignKeys map[string]*ForeignKey
 
-// Slice возвращает срез внешних ключей
+// Slice возвращает срез внешних ключей, упорядоченный по наименованию ключа
 func (keys ForeignKeys) Slice() []*ForeignKey {
 	if len(keys) == 0 {
 		return nil
@@ -443,6 +486,10 @@ func (keys ForeignKeys) Slice() []*ForeignKey {
 		i++
 	}
 
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
 	return out
 }
 
@@ -1,6 +1,7 @@
 package sqlserver
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -120,3 +121,48 @@ func TestIndex_String(t *testing.T) {
 		}
 	}
 }
+
+func TestIndex_Flags(t *testing.T) {
+	var cases = []struct {
+		index *Index
+		want  []string
+	}{
+		{
+			index: &Index{
+				Name:                     "IX_Test1",
+				Type:                     "NONCLUSTERED",
+				OptimizeForSequentialKey: true,
+			},
+			want: []string{"OPTIMIZE_FOR_SEQUENTIAL_KEY = ON"},
+		},
+		{
+			index: &Index{
+				Name:                     "IX_Test2",
+				Type:                     "NONCLUSTERED",
+				OptimizeForSequentialKey: true,
+
+				targetVersion: ServerVersion2017,
+			},
+			want: []string{},
+		},
+		{
+			index: &Index{
+				Name:                     "IX_Test3",
+				Type:                     "NONCLUSTERED",
+				IgnoreDupKey:             true,
+				OptimizeForSequentialKey: true,
+
+				owner: OwnerUserDefinedTableDataType,
+			},
+			want: []string{"IGNORE_DUP_KEY = ON"},
+		},
+	}
+
+	for _, test := range cases {
+		have := test.index.Flags()
+
+		if !reflect.DeepEqual(have, test.want) {
+			t.Errorf("[%s].Flags() failed: have %v, want %v", test.index.Name, have, test.want)
+		}
+	}
+}
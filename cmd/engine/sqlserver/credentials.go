@@ -102,3 +102,30 @@ func SetCredentials(connection, username, password string) (string, error) {
 
 	return u.String(), nil
 }
+
+// DefaultPort порт SQL Server по умолчанию
+const DefaultPort = "1433"
+
+// Endpoint возвращает сервер, порт и наименование БД, извлеченные из строки соединения с базой данных. Если порт не
+// указан, то возвращается порт по умолчанию
+func Endpoint(connection string) (host, port, database string, err error) {
+	u, err := url.Parse(connection)
+
+	if err != nil {
+		return "", "", "", err
+	}
+
+	q, err := url.ParseQuery(u.RawQuery)
+
+	if err != nil {
+		return "", "", "", err
+	}
+
+	port = u.Port()
+
+	if port == "" {
+		port = DefaultPort
+	}
+
+	return u.Hostname(), port, q.Get("database"), nil
+}
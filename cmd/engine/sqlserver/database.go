@@ -1,12 +1,143 @@
 package sqlserver
 
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+	str "github.com/vitpelekhaty/dbmill-cli/internal/pkg/strings"
+)
+
 // Database параметры базы данных
 type Database struct {
+	// Name наименование базы данных
+	Name string
+	// Collation collation базы данных
+	Collation string
+	// EngineEdition редакция ядра SQL Server, на котором размещена база данных
+	EngineEdition EngineEdition
+	// Server наименование сервера
+	Server string
+
+	edition          sql.NullString
+	serviceObjective sql.NullString
+	elasticPool      sql.NullString
+}
+
+// Edition возвращает редакцию базы данных Azure SQL Database (Basic, Standard, Premium, GeneralPurpose etc)
+func (db *Database) Edition() string {
+	if db.edition.Valid {
+		return db.edition.String
+	}
+
+	return ""
+}
+
+// ServiceObjective возвращает целевой уровень обслуживания базы данных Azure SQL Database (S0, P1, GP_Gen5_2 etc)
+func (db *Database) ServiceObjective() string {
+	if db.serviceObjective.Valid {
+		return db.serviceObjective.String
+	}
+
+	return ""
+}
+
+// ElasticPool возвращает наименование эластичного пула, в который входит база данных Azure SQL Database
+func (db *Database) ElasticPool() string {
+	if db.elasticPool.Valid {
+		return db.elasticPool.String
+	}
+
+	return ""
 }
 
 // String возвращает скрипт создания базы данных
 func (db *Database) String() string {
-	return ""
+	statement := db.Statement()
+
+	if statement == "" {
+		return ""
+	}
+
+	return statement + "\nGO"
+}
+
+// Statement возвращает инструкцию создания базы данных. Предложения, описывающие файлы и файловые группы, в скрипт не
+// включаются. Параметры Azure SQL Database (редакция, целевой уровень обслуживания, эластичный пул) записываются в
+// скрипт в виде комментариев
+func (db *Database) Statement() string {
+	if strings.Trim(db.Name, " ") == "" {
+		return ""
+	}
+
+	builder := str.NewBuilder("CREATE DATABASE " + output.Bracketed(db.Name))
+
+	if strings.Trim(db.Collation, " ") != "" {
+		builder.WriteString("\nCOLLATE " + db.Collation)
+	}
+
+	if db.EngineEdition.IsAzureSQLDatabase() {
+		if edition := db.Edition(); strings.Trim(edition, " ") != "" {
+			builder.WriteString(fmt.Sprintf("\n-- EDITION = '%s'", edition))
+		}
+
+		if elasticPool := db.ElasticPool(); strings.Trim(elasticPool, " ") != "" {
+			builder.WriteString(fmt.Sprintf("\n-- SERVICE_OBJECTIVE = ELASTIC_POOL (name = %s)",
+				output.Bracketed(elasticPool)))
+		} else {
+			if serviceObjective := db.ServiceObjective(); strings.Trim(serviceObjective, " ") != "" {
+				builder.WriteString(fmt.Sprintf("\n-- SERVICE_OBJECTIVE = '%s'", serviceObjective))
+			}
+		}
+	}
+
+	return builder.String()
+}
+
+func (command *ScriptsFolderCommand) writeDatabaseDefinition(ctx context.Context, object interface{}) (interface{},
+	error) {
+	obj, ok := object.(IDatabaseObject)
+
+	if !ok {
+		return object, errors.New("object is not a database object")
+	}
+
+	if obj.Type() != output.Database {
+		return object, fmt.Errorf("object %s is not a database", obj.SchemaAndName(true))
+	}
+
+	if command.database == nil {
+		return object, fmt.Errorf("no info about database %s", obj.Name())
+	}
+
+	obj.SetDefinition([]byte(command.styledStatement(obj, command.database.Statement())))
+
+	return obj, nil
 }
 
 const selectDatabaseCollation = `select isnull(DATABASEPROPERTYEX(db_name(), 'Collation'), N'') AS collation`
+
+const selectDatabase = `
+select
+    [name] = db_name(),
+    [collation] = isnull(cast(databasepropertyex(db_name(), 'Collation') as nvarchar(128)), N''),
+    [server] = isnull(cast(serverproperty('ServerName') as nvarchar(128)), N''),
+    [edition] = cast(null as nvarchar(128)),
+    [service_objective] = cast(null as nvarchar(128)),
+    [elastic_pool] = cast(null as nvarchar(128))
+`
+
+const selectDatabaseAzure = `
+select
+    [name] = db_name(),
+    [collation] = isnull(cast(databasepropertyex(db_name(), 'Collation') as nvarchar(128)), N''),
+    [server] = isnull(cast(serverproperty('ServerName') as nvarchar(128)), N''),
+    [edition] = dso.edition,
+    [service_objective] = dso.service_objective,
+    [elastic_pool] = dso.elastic_pool_name
+from (select [database_id] = db_id()) as db
+    left join sys.database_service_objectives as dso on (db.database_id = dso.database_id)
+`
@@ -0,0 +1,88 @@
+package sqlserver
+
+import (
+	"database/sql"
+	"testing"
+)
+
+func TestDatabase_String(t *testing.T) {
+	var cases = []struct {
+		database Database
+		want     string
+	}{
+		{
+			database: Database{
+				Name:          "AdventureWorks",
+				Collation:     "SQL_Latin1_General_CP1_CI_AS",
+				EngineEdition: EngineEditionEnterprise,
+			},
+			want: "CREATE DATABASE [AdventureWorks]\nCOLLATE SQL_Latin1_General_CP1_CI_AS\nGO",
+		},
+		{
+			database: Database{
+				Name:          "AdventureWorks",
+				Collation:     "SQL_Latin1_General_CP1_CI_AS",
+				EngineEdition: EngineEditionAzureSQLDatabase,
+				edition: sql.NullString{
+					String: "Standard",
+					Valid:  true,
+				},
+				serviceObjective: sql.NullString{
+					String: "S1",
+					Valid:  true,
+				},
+			},
+			want: "CREATE DATABASE [AdventureWorks]\nCOLLATE SQL_Latin1_General_CP1_CI_AS\n-- EDITION = 'Standard'\n" +
+				"-- SERVICE_OBJECTIVE = 'S1'\nGO",
+		},
+		{
+			database: Database{
+				Name:          "AdventureWorks",
+				EngineEdition: EngineEditionAzureSQLDatabase,
+				edition: sql.NullString{
+					String: "Standard",
+					Valid:  true,
+				},
+				serviceObjective: sql.NullString{
+					String: "ElasticPool",
+					Valid:  true,
+				},
+				elasticPool: sql.NullString{
+					String: "pool1",
+					Valid:  true,
+				},
+			},
+			want: "CREATE DATABASE [AdventureWorks]\n-- EDITION = 'Standard'\n" +
+				"-- SERVICE_OBJECTIVE = ELASTIC_POOL (name = [pool1])\nGO",
+		},
+		{
+			database: Database{
+				Name:          "AdventureWorks",
+				EngineEdition: EngineEditionAzureSQLManagedInstance,
+				edition: sql.NullString{
+					String: "Standard",
+					Valid:  true,
+				},
+			},
+			want: "CREATE DATABASE [AdventureWorks]\nGO",
+		},
+		{
+			database: Database{
+				Name:          "Adventure]Works",
+				EngineEdition: EngineEditionEnterprise,
+			},
+			want: "CREATE DATABASE [Adventure]]Works]\nGO",
+		},
+	}
+
+	var have string
+
+	for _, test := range cases {
+		have = test.database.String()
+
+		if have != test.want {
+			t.Errorf("TestDatabase_String failed for %s: have %s, want %s", test.database.EngineEdition, have,
+				test.want)
+		}
+	}
+}
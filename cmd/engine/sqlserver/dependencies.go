@@ -0,0 +1,395 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/filter"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/graph"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/log"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+// Dependency зависимость объекта БД от другого объекта БД из sys.sql_expression_dependencies
+type Dependency struct {
+	// Object зависимый объект БД. Для вычисляемых полей и ограничений CHECK и DEFAULT - таблица
+	Object graph.Node
+	// Referenced объект БД, от которого зависит Object
+	Referenced graph.Node
+	// Kind вид зависимости: ссылка модуля, вычисляемого поля или ограничения
+	Kind graph.Kind
+}
+
+// Dependencies возвращает зависимости объектов БД друг от друга, найденные сервером в определениях модулей,
+// вычисляемых полей и ограничений. Ссылки на объекты других БД и неизвестные серверу объекты не возвращаются
+func (meta *MetadataReader) Dependencies(ctx context.Context) ([]Dependency, error) {
+	ctx, cancel := meta.queryContext(ctx)
+	defer cancel()
+
+	stmt, err := meta.db.PrepareContext(ctx, selectDependencies)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	dependencies := make([]Dependency, 0)
+
+	var (
+		schema           string
+		name             string
+		objectType       sql.NullString
+		referencedSchema string
+		referencedName   string
+		referencedType   sql.NullString
+		kind             string
+	)
+
+	for rows.Next() {
+		err = rows.Scan(&schema, &name, &objectType, &referencedSchema, &referencedName, &referencedType, &kind)
+
+		if err != nil {
+			return nil, err
+		}
+
+		// зависимости объектов, скрипты которых не создаются (синонимов, последовательностей и т.п.), не нужны
+		if !objectType.Valid || !referencedType.Valid {
+			continue
+		}
+
+		dependencies = append(dependencies, Dependency{
+			Object: graph.Node{Type: parseObjectType(objectType.String), Schema: schema, Name: name},
+			Referenced: graph.Node{Type: parseObjectType(referencedType.String), Schema: referencedSchema,
+				Name: referencedName},
+			Kind: graph.Kind(kind),
+		})
+	}
+
+	return dependencies, rows.Err()
+}
+
+// GraphObjects возвращает объекты БД, которые могут быть вершинами графа зависимостей: пользовательские типы,
+// таблицы, представления, триггеры, функции и процедуры. Определения объектов не читаются
+func (meta *MetadataReader) GraphObjects(ctx context.Context) ([]IDatabaseObject, error) {
+	ctx, cancel := meta.queryContext(ctx)
+	defer cancel()
+
+	stmt, err := meta.db.PrepareContext(ctx, selectGraphObjects)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer stmt.Close()
+
+	rows, err := stmt.QueryContext(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer rows.Close()
+
+	objects := make([]IDatabaseObject, 0)
+
+	for rows.Next() {
+		object := &databaseObject{}
+
+		if err = rows.Scan(&object.schema, &object.name, &object.objectType, &object.parent); err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, object)
+	}
+
+	return objects, rows.Err()
+}
+
+// objectNode возвращает вершину графа зависимостей объекта БД object
+func objectNode(object IDatabaseObject) graph.Node {
+	return graph.Node{Type: object.Type(), Schema: object.Schema(), Name: object.Name()}
+}
+
+// DependencyGraph возвращает граф зависимостей объектов БД objects: ссылок из dependencies, внешних ключей
+// foreignKeys, пользовательских типов полей columns и триггеров таблиц и представлений. Зависимости от объектов,
+// которых нет в objects, в граф не включаются
+func DependencyGraph(objects []IDatabaseObject, dependencies []Dependency, foreignKeys ObjectsForeignKeys,
+	columns ObjectColumns) *graph.Graph {
+	g := graph.New()
+
+	// справочник объектов по наименованию: табличный тип и таблица, например, могут называться одинаково
+	byName := make(map[string][]graph.Node)
+
+	for _, object := range objects {
+		switch object.Type() {
+		case output.UnknownObject, output.Database, output.Schema:
+			continue
+		}
+
+		node := objectNode(object)
+
+		g.AddNode(node)
+		byName[node.String()] = append(byName[node.String()], node)
+	}
+
+	lookup := func(schema, name string, types ...output.DatabaseObjectType) []graph.Node {
+		nodes := make([]graph.Node, 0, 1)
+
+		for _, node := range byName[SchemaAndObject(schema, name, true)] {
+			for _, objType := range types {
+				if node.Type == objType {
+					nodes = append(nodes, node)
+				}
+			}
+		}
+
+		return nodes
+	}
+
+	for _, dependency := range dependencies {
+		if g.Has(dependency.Object) && g.Has(dependency.Referenced) {
+			g.AddEdge(dependency.Object, dependency.Referenced, dependency.Kind)
+		}
+	}
+
+	for object, keys := range foreignKeys {
+		for _, from := range byName[object] {
+			if from.Type != output.Table {
+				continue
+			}
+
+			for _, fk := range keys {
+				for _, to := range lookup(fk.ReferencedObjectSchema, fk.ReferencedObjectName, output.Table) {
+					g.AddEdge(from, to, graph.KindForeignKey)
+				}
+			}
+		}
+	}
+
+	for object, cols := range columns {
+		for _, from := range byName[object] {
+			for _, column := range cols {
+				if !column.IsUserDefinedType {
+					continue
+				}
+
+				for _, to := range lookup(column.TypeSchema, column.TypeName, output.UserDefinedDataType,
+					output.UserDefinedTableType) {
+					g.AddEdge(from, to, graph.KindType)
+				}
+			}
+		}
+	}
+
+	for _, object := range objects {
+		if object.Type() != output.Trigger || object.Parent() == "" {
+			continue
+		}
+
+		// триггер создается в схеме своей таблицы или представления
+		for _, to := range lookup(object.Schema(), object.Parent(), output.Table, output.View) {
+			g.AddEdge(objectNode(object), to, graph.KindTrigger)
+		}
+	}
+
+	return g
+}
+
+const selectDependencies = `
+with referencing (object_id, [schema], name, type, kind) as (
+    select objects.object_id, schema_name(parents.schema_id), parents.name, parents.type,
+        iif(objects.type in ('C', 'D'), N'constraint', null)
+    from sys.objects as objects
+        inner join sys.objects as parents
+            on (parents.object_id = iif(objects.type in ('C', 'D'), objects.parent_object_id, objects.object_id))
+)
+select distinct
+    [schema] = referencing.[schema],
+    [name] = referencing.name,
+    [type] = case referencing.type
+        when 'U' then N'BASE TABLE'
+        when 'V' then N'VIEW'
+        when 'TR' then N'TRIGGER'
+        when 'FN' then N'FUNCTION'
+        when 'IF' then N'FUNCTION'
+        when 'TF' then N'FUNCTION'
+        when 'P' then N'PROCEDURE'
+        else null
+    end,
+    [referenced_schema] = schema_name(iif(deps.referenced_class = 6, types.schema_id, objects.schema_id)),
+    [referenced_name] = iif(deps.referenced_class = 6, types.name, objects.name),
+    [referenced_type] = case
+        when deps.referenced_class = 6 then iif(types.is_table_type != cast(0 as bit), N'TABLE TYPE', N'DATA TYPE')
+        when objects.type = 'U' then N'BASE TABLE'
+        when objects.type = 'V' then N'VIEW'
+        when objects.type in ('FN', 'IF', 'TF') then N'FUNCTION'
+        when objects.type = 'P' then N'PROCEDURE'
+        else null
+    end,
+    [kind] = isnull(referencing.kind, iif(deps.referencing_minor_id != 0, N'column', N'reference'))
+from sys.sql_expression_dependencies as deps
+    inner join referencing on (deps.referencing_id = referencing.object_id)
+    left join sys.objects as objects on (deps.referenced_class = 1) and (deps.referenced_id = objects.object_id)
+    left join sys.types as types on (deps.referenced_class = 6) and (deps.referenced_id = types.user_type_id)
+        and (types.is_user_defined != cast(0 as bit))
+where (deps.referencing_class = 1) and (deps.referenced_database_name is null)
+    and ((objects.object_id is not null) or (types.user_type_id is not null))
+order by [schema], [name], [referenced_schema], [referenced_name]
+`
+
+const selectGraphObjects = `
+select
+    [schema] = schema_name(types.schema_id),
+    [name] = types.name,
+    [type] = iif(types.is_table_type != cast(0 as bit), N'TABLE TYPE', N'DATA TYPE'),
+    [parent] = null
+from sys.types as types
+where (types.is_user_defined != cast(0 as bit))
+union all
+select
+    [schema] = schema_name(objects.schema_id),
+    [name] = objects.name,
+    [type] = case objects.type
+        when 'U' then N'BASE TABLE'
+        when 'V' then N'VIEW'
+        when 'TR' then N'TRIGGER'
+        when 'P' then N'PROCEDURE'
+        else N'FUNCTION'
+    end,
+    [parent] = object_name(nullif(objects.parent_object_id, 0))
+from sys.objects as objects
+where objects.type in ('U', 'V', 'TR', 'FN', 'IF', 'TF', 'P')
+order by [schema], [name]
+`
+
+// DependenciesCommand реализация интерфейса IDependenciesCommand для SQL Server
+type DependenciesCommand struct {
+	engine     *Engine
+	metaReader *MetadataReader
+	roots      filter.IFilter
+	depth      int
+	direction  commands.Direction
+	graph      *graph.Graph
+}
+
+// NewDependenciesCommand конструктор DependenciesCommand
+func NewDependenciesCommand(engine *Engine, options ...commands.DependenciesOption) *DependenciesCommand {
+	metaReader, _ := engine.MetadataReader()
+
+	command := &DependenciesCommand{
+		engine:     engine,
+		metaReader: metaReader,
+		roots:      nil,
+		depth:      0,
+		direction:  commands.DirectionDependencies,
+		graph:      nil,
+	}
+
+	for _, option := range options {
+		option(command)
+	}
+
+	return command
+}
+
+// Run строит граф зависимостей объектов БД. При отмене контекста ctx выполнение прерывается и возвращается ошибка
+// контекста
+func (command *DependenciesCommand) Run(ctx context.Context) error {
+	started := time.Now()
+
+	command.engine.LogFields(log.DebugLevel, log.Fields{log.FieldPhase: commands.PhaseMetadata.String()},
+		"metadata reading...")
+
+	objects, err := command.metaReader.GraphObjects(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	dependencies, err := command.metaReader.Dependencies(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	foreignKeys, err := command.metaReader.ForeignKeys(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	columns, err := command.metaReader.ObjectColumns(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	command.graph = command.selectGraph(DependencyGraph(objects, dependencies, foreignKeys, columns), objects)
+
+	command.engine.LogFields(log.DebugLevel, log.Fields{
+		log.FieldPhase:    commands.PhaseDone.String(),
+		log.FieldDuration: time.Since(started).Seconds(),
+	}, "done")
+
+	return nil
+}
+
+// selectGraph возвращает часть графа g из корневых объектов БД и объектов, связанных с ними зависимостями в
+// направлении direction. Если фильтр корневых объектов не указан, то возвращается весь граф
+func (command *DependenciesCommand) selectGraph(g *graph.Graph, objects []IDatabaseObject) *graph.Graph {
+	if command.roots == nil {
+		return g
+	}
+
+	roots := make([]graph.Node, 0)
+
+	for _, object := range objects {
+		if command.roots.Match(filterObject(object)) == nil {
+			roots = append(roots, objectNode(object))
+		}
+	}
+
+	var nodes []graph.Node
+
+	switch command.direction {
+	case commands.DirectionDependents:
+		nodes = g.Dependents(roots, command.depth)
+	case commands.DirectionBoth:
+		nodes = append(g.Dependencies(roots, command.depth), g.Dependents(roots, command.depth)...)
+	default:
+		nodes = g.Dependencies(roots, command.depth)
+	}
+
+	return g.Subgraph(nodes)
+}
+
+// SetRootObjects устанавливает фильтр корневых объектов БД. nil - граф строится для всех объектов БД
+func (command *DependenciesCommand) SetRootObjects(filter filter.IFilter) {
+	command.roots = filter
+}
+
+// SetDepth устанавливает максимальное количество зависимостей от корневых объектов. 0 - без ограничения
+func (command *DependenciesCommand) SetDepth(depth int) {
+	command.depth = depth
+}
+
+// SetDirection устанавливает направление обхода графа зависимостей от корневых объектов
+func (command *DependenciesCommand) SetDirection(direction commands.Direction) {
+	command.direction = direction
+}
+
+// Graph возвращает граф зависимостей, построенный командой
+func (command *DependenciesCommand) Graph() *graph.Graph {
+	return command.graph
+}
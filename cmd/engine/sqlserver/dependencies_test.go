@@ -0,0 +1,257 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/filter"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/graph"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+var (
+	nodeOrders     = graph.Node{Type: output.Table, Schema: "dbo", Name: "Orders"}
+	nodeCustomers  = graph.Node{Type: output.Table, Schema: "dbo", Name: "Customers"}
+	nodePhone      = graph.Node{Type: output.UserDefinedDataType, Schema: "dbo", Name: "Phone"}
+	nodeOrderTotal = graph.Node{Type: output.Function, Schema: "dbo", Name: "OrderTotal"}
+	nodeOrderList  = graph.Node{Type: output.View, Schema: "rpt", Name: "OrderList"}
+	nodeGetOrders  = graph.Node{Type: output.Procedure, Schema: "rpt", Name: "GetOrders"}
+	nodeAudit      = graph.Node{Type: output.Trigger, Schema: "dbo", Name: "Orders_Audit"}
+	nodeOrderItems = graph.Node{Type: output.UserDefinedTableType, Schema: "dbo", Name: "OrderItems"}
+)
+
+func testDependencyObjects() []IDatabaseObject {
+	audit := testDatabaseObject("dbo", "Orders_Audit", "TRIGGER")
+	audit.parent = sql.NullString{String: "Orders", Valid: true}
+
+	return []IDatabaseObject{
+		testDatabaseObject("", "Sales", "DATABASE"),
+		testDatabaseObject("dbo", "", "SCHEMA"),
+		testDatabaseObject("rpt", "", "SCHEMA"),
+		testDatabaseObject("dbo", "Phone", "DATA TYPE"),
+		testDatabaseObject("dbo", "OrderItems", "TABLE TYPE"),
+		testDatabaseObject("dbo", "Customers", "BASE TABLE"),
+		testDatabaseObject("dbo", "Orders", "BASE TABLE"),
+		testDatabaseObject("dbo", "Log", "BASE TABLE"),
+		testDatabaseObject("rpt", "OrderList", "VIEW"),
+		audit,
+		testDatabaseObject("dbo", "OrderTotal", "FUNCTION"),
+		testDatabaseObject("rpt", "GetOrders", "PROCEDURE"),
+	}
+}
+
+func testDependencyCommand() *ScriptsFolderCommand {
+	command := goldenCommand()
+
+	command.types = map[output.DatabaseObjectType]bool{output.Database: true, output.Schema: true,
+		output.UserDefinedDataType: true, output.UserDefinedTableType: true, output.Table: true, output.View: true,
+		output.Trigger: true, output.Function: true, output.Procedure: true}
+
+	command.dependencies = []Dependency{
+		{Object: nodeOrders, Referenced: nodeOrderTotal, Kind: graph.KindColumn},
+		{Object: nodeOrderTotal, Referenced: nodeOrders, Kind: graph.KindReference},
+		{Object: nodeOrderList, Referenced: nodeOrders, Kind: graph.KindReference},
+		{Object: nodeGetOrders, Referenced: nodeOrderList, Kind: graph.KindReference},
+		{Object: nodeGetOrders, Referenced: nodeOrderItems, Kind: graph.KindType},
+		{Object: nodeGetOrders, Referenced: graph.Node{Type: output.Table, Schema: "arch", Name: "Orders"},
+			Kind: graph.KindReference},
+	}
+
+	command.foreignKeys = ObjectsForeignKeys{
+		"[dbo].[Orders]": ForeignKeys{
+			"FK_Orders_Customers": &ForeignKey{Name: "FK_Orders_Customers", ReferencedObjectSchema: "dbo",
+				ReferencedObjectName: "Customers"},
+		},
+	}
+
+	command.columns = ObjectColumns{
+		"[dbo].[Customers]": Columns{
+			"ID":    &Column{ID: 1, Name: "ID", TypeName: "int", TypeSchema: "sys"},
+			"Phone": &Column{ID: 2, Name: "Phone", TypeName: "Phone", TypeSchema: "dbo", IsUserDefinedType: true},
+		},
+	}
+
+	return command
+}
+
+func TestDependencyGraph(t *testing.T) {
+	command := testDependencyCommand()
+
+	g := DependencyGraph(testDependencyObjects(), command.dependencies, command.foreignKeys, command.columns)
+
+	want := []graph.Edge{
+		{From: nodeCustomers, To: nodePhone, Kind: graph.KindType},
+		{From: nodeOrderTotal, To: nodeOrders, Kind: graph.KindReference},
+		{From: nodeOrders, To: nodeCustomers, Kind: graph.KindForeignKey},
+		{From: nodeOrders, To: nodeOrderTotal, Kind: graph.KindColumn},
+		{From: nodeAudit, To: nodeOrders, Kind: graph.KindTrigger},
+		{From: nodeGetOrders, To: nodeOrderItems, Kind: graph.KindType},
+		{From: nodeGetOrders, To: nodeOrderList, Kind: graph.KindReference},
+		{From: nodeOrderList, To: nodeOrders, Kind: graph.KindReference},
+	}
+
+	if have := g.Edges(); !reflect.DeepEqual(have, want) {
+		t.Errorf("have edges\n%v\nwant\n%v", have, want)
+	}
+}
+
+func TestScriptsFolderCommand_expand(t *testing.T) {
+	var cases = []struct {
+		include      []string
+		exclude      []string
+		dependencies bool
+		dependents   bool
+		want         []string
+	}{
+		{
+			include: []string{"procedure:rpt.GetOrders"},
+			want:    []string{"[rpt].[GetOrders]"},
+		},
+		{
+			include:      []string{"procedure:rpt.GetOrders"},
+			dependencies: true,
+			want: []string{"[dbo]", "[rpt]", "[dbo].[Phone]", "[dbo].[OrderItems]", "[dbo].[Customers]",
+				"[dbo].[Orders]", "[rpt].[OrderList]", "[dbo].[OrderTotal]", "[rpt].[GetOrders]"},
+		},
+		{
+			include:      []string{"procedure:rpt.GetOrders"},
+			exclude:      []string{"view:*"},
+			dependencies: true,
+			want: []string{"[dbo]", "[rpt]", "[dbo].[Phone]", "[dbo].[OrderItems]", "[dbo].[Customers]",
+				"[dbo].[Orders]", "[dbo].[OrderTotal]", "[rpt].[GetOrders]"},
+		},
+		{
+			include:    []string{"table:dbo.Customers"},
+			dependents: true,
+			want: []string{"[dbo].[Customers]", "[dbo].[Orders]", "[rpt].[OrderList]", "[dbo].[Orders_Audit]",
+				"[dbo].[OrderTotal]", "[rpt].[GetOrders]"},
+		},
+		{
+			include:    []string{"function:dbo.OrderTotal"},
+			dependents: true,
+			want: []string{"[dbo].[Orders]", "[rpt].[OrderList]", "[dbo].[Orders_Audit]", "[dbo].[OrderTotal]",
+				"[rpt].[GetOrders]"},
+		},
+	}
+
+	for _, test := range cases {
+		command := testDependencyCommand()
+
+		include, err := filter.New(test.include)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		command.SetIncludedObjects(include)
+
+		if len(test.exclude) > 0 {
+			exclude, err := filter.New(test.exclude)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			command.SetExcludedObjects(exclude)
+		}
+
+		command.IncludeDependencies(test.dependencies)
+		command.IncludeDependents(test.dependents)
+
+		items, total := command.enumerate(context.Background(), testObjectsChannel(testDependencyObjects()))
+
+		have := make([]string, 0, total)
+
+		for item := range items {
+			if object := item.V.(IDatabaseObject); command.included(object) {
+				have = append(have, object.SchemaAndName(true))
+			}
+		}
+
+		if len(have) != total || !reflect.DeepEqual(have, test.want) {
+			t.Errorf("%v, dependencies %v, dependents %v: have %v (total %d), want %v", test.include,
+				test.dependencies, test.dependents, have, total, test.want)
+		}
+	}
+}
+
+func TestDependenciesCommand_selectGraph(t *testing.T) {
+	var cases = []struct {
+		roots     []string
+		depth     int
+		direction commands.Direction
+		want      []graph.Node
+	}{
+		{
+			want: []graph.Node{nodeCustomers, {Type: output.Table, Schema: "dbo", Name: "Log"}, nodeOrderItems,
+				nodeOrderTotal, nodeOrders, nodeAudit, nodePhone, nodeGetOrders, nodeOrderList},
+		},
+		{
+			roots: []string{"view:rpt.OrderList"},
+			depth: 1,
+			want:  []graph.Node{nodeOrders, nodeOrderList},
+		},
+		{
+			roots:     []string{"view:rpt.OrderList"},
+			depth:     1,
+			direction: commands.DirectionBoth,
+			want:      []graph.Node{nodeOrders, nodeGetOrders, nodeOrderList},
+		},
+		{
+			roots:     []string{"table:dbo.Orders"},
+			direction: commands.DirectionDependents,
+			want:      []graph.Node{nodeOrderTotal, nodeOrders, nodeAudit, nodeGetOrders, nodeOrderList},
+		},
+	}
+
+	for _, test := range cases {
+		data := testDependencyCommand()
+		objects := testDependencyObjects()
+
+		command := &DependenciesCommand{depth: test.depth, direction: test.direction}
+
+		if len(test.roots) > 0 {
+			roots, err := filter.New(test.roots)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			command.SetRootObjects(roots)
+		}
+
+		g := command.selectGraph(DependencyGraph(objects, data.dependencies, data.foreignKeys, data.columns), objects)
+
+		if have := g.Nodes(); !reflect.DeepEqual(have, test.want) {
+			t.Errorf("%v, depth %d, %s: have %v, want %v", test.roots, test.depth, test.direction, have, test.want)
+		}
+	}
+}
+
+func TestScriptsFolderCommand_objectDependencies(t *testing.T) {
+	objects := testDependencyObjects()
+	orders := objects[6]
+
+	command := testDependencyCommand()
+	command.enumerate(context.Background(), testObjectsChannel(objects))
+
+	if have := command.objectDefinition(orders).Dependencies; have != nil {
+		t.Errorf("have dependencies %v, want none", have)
+	}
+
+	command = testDependencyCommand()
+	command.ObjectDependencies(true)
+	command.enumerate(context.Background(), testObjectsChannel(objects))
+
+	want := []output.ObjectReference{
+		{Type: output.Table, Schema: "dbo", Name: "Customers"},
+		{Type: output.Function, Schema: "dbo", Name: "OrderTotal"},
+	}
+
+	if have := command.objectDefinition(orders).Dependencies; !reflect.DeepEqual(have, want) {
+		t.Errorf("have dependencies %v, want %v", have, want)
+	}
+}
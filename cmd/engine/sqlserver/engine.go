@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	_ "github.com/denisenkom/go-mssqldb"
 
@@ -19,9 +20,11 @@ type Engine struct {
 	output output.IScriptsFolderOutput
 
 	serverVersion int
+	engineEdition EngineEdition
+	queryTimeout  time.Duration
 }
 
-// NewEngine возвращает экземпляр Engine
+// NewEngine возвращает экземпляр Engine. Подключение к серверу выполняется методом Connect
 func NewEngine(connection string) (*Engine, error) {
 	db, err := sql.Open("sqlserver", connection)
 
@@ -29,21 +32,52 @@ func NewEngine(connection string) (*Engine, error) {
 		return nil, err
 	}
 
-	serverVersion, err := serverVersion(db, context.Background())
-
-	if err != nil {
-		return nil, fmt.Errorf("failed to get a version of the server: %v", err)
-	}
-
 	return &Engine{
 		db:     db,
 		logger: nil,
 		output: output.DefaultScriptsFolderOutput,
-
-		serverVersion: serverVersion,
 	}, nil
 }
 
+// Connect подключается к серверу и определяет его версию и редакцию. Каждый запрос ограничен таймаутом запроса
+func (engine *Engine) Connect(ctx context.Context) error {
+	versionCtx, cancel := withTimeout(ctx, engine.queryTimeout)
+	defer cancel()
+
+	serverVersion, err := serverVersion(engine.db, versionCtx)
+
+	if err != nil {
+		return fmt.Errorf("failed to get a version of the server: %w", err)
+	}
+
+	editionCtx, cancel := withTimeout(ctx, engine.queryTimeout)
+	defer cancel()
+
+	engineEdition, err := serverEngineEdition(engine.db, editionCtx)
+
+	if err != nil {
+		return fmt.Errorf("failed to get an edition of the server: %w", err)
+	}
+
+	engine.serverVersion = serverVersion
+	engine.engineEdition = engineEdition
+
+	return nil
+}
+
+// Server возвращает сведения о сервере
+func (engine *Engine) Server() commands.ServerInfo {
+	return commands.ServerInfo{
+		Version: ServerVersionName(engine.serverVersion),
+		Edition: engine.engineEdition.String(),
+	}
+}
+
+// SetQueryTimeout устанавливает максимальное время выполнения одного запроса к серверу. 0 - без ограничения
+func (engine *Engine) SetQueryTimeout(timeout time.Duration) {
+	engine.queryTimeout = timeout
+}
+
 // SetLogger устанавливает логгер событий
 func (engine *Engine) SetLogger(logger log.ILogger) {
 	engine.logger = logger
@@ -63,9 +97,33 @@ func (engine *Engine) ScriptsFolder(options ...commands.ScriptsFolderOption) com
 	return NewScriptsFolderCommand(engine, options...)
 }
 
+// Dependencies строит граф зависимостей объектов БД
+func (engine *Engine) Dependencies(options ...commands.DependenciesOption) commands.IDependenciesCommand {
+	return NewDependenciesCommand(engine, options...)
+}
+
+// ERD строит диаграмму "сущность-связь" таблиц БД
+func (engine *Engine) ERD(options ...commands.ERDOption) commands.IERDCommand {
+	return NewERDCommand(engine, options...)
+}
+
 // MetadataReader возвращает объект чтения метаданных
 func (engine *Engine) MetadataReader() (*MetadataReader, error) {
-	return NewMetadataReader(engine, engine.serverVersion)
+	return NewMetadataReader(engine, engine.serverVersion, engine.engineEdition)
+}
+
+// EngineEdition возвращает редакцию ядра SQL Server
+func (engine *Engine) EngineEdition() EngineEdition {
+	return engine.engineEdition
+}
+
+// withTimeout возвращает контекст, ограниченный таймаутом timeout. При нулевом таймауте контекст не ограничивается
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
 }
 
 // Log создает запись в логе, если указан логгер
@@ -77,6 +135,15 @@ func (engine *Engine) Log(level log.Level, args ...interface{}) {
 	engine.logger.Print(level, args...)
 }
 
+// LogFields создает запись в логе со структурированными полями fields, если указан логгер
+func (engine *Engine) LogFields(level log.Level, fields log.Fields, args ...interface{}) {
+	if engine.logger == nil {
+		return
+	}
+
+	engine.logger.WithFields(fields).Print(level, args...)
+}
+
 // Logf создает фомрматированную запись в логе, если указан логгер
 func (engine *Engine) Logf(level log.Level, format string, args ...interface{}) {
 	if engine.logger == nil {
@@ -0,0 +1,262 @@
+package sqlserver
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/erd"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/filter"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/log"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+// ERDCommand реализация интерфейса IERDCommand для SQL Server
+type ERDCommand struct {
+	engine     *Engine
+	metaReader *MetadataReader
+	schemas    []string
+	tables     filter.IFilter
+	diagram    *erd.Diagram
+}
+
+// NewERDCommand конструктор ERDCommand
+func NewERDCommand(engine *Engine, options ...commands.ERDOption) *ERDCommand {
+	metaReader, _ := engine.MetadataReader()
+
+	command := &ERDCommand{
+		engine:     engine,
+		metaReader: metaReader,
+		schemas:    nil,
+		tables:     nil,
+		diagram:    nil,
+	}
+
+	for _, option := range options {
+		option(command)
+	}
+
+	return command
+}
+
+// Run строит диаграмму "сущность-связь" таблиц БД. При отмене контекста ctx выполнение прерывается и возвращается
+// ошибка контекста
+func (command *ERDCommand) Run(ctx context.Context) error {
+	started := time.Now()
+
+	command.engine.LogFields(log.DebugLevel, log.Fields{log.FieldPhase: commands.PhaseMetadata.String()},
+		"metadata reading...")
+
+	tables, err := command.metaReader.Tables(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	columns, err := command.metaReader.ObjectColumns(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	indexes, err := command.metaReader.Indexes(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	foreignKeys, err := command.metaReader.ForeignKeys(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	command.diagram = EntityRelationshipDiagram(tables, columns, indexes, foreignKeys, command.selected)
+
+	command.engine.LogFields(log.DebugLevel, log.Fields{
+		log.FieldPhase:    commands.PhaseDone.String(),
+		log.FieldDuration: time.Since(started).Seconds(),
+	}, "done")
+
+	return nil
+}
+
+// selected проверяет, что таблица table выбрана схемами и фильтром таблиц команды
+func (command *ERDCommand) selected(table *Table) bool {
+	if len(command.schemas) > 0 {
+		found := false
+
+		for _, schema := range command.schemas {
+			found = found || strings.EqualFold(schema, table.Schema)
+		}
+
+		if !found {
+			return false
+		}
+	}
+
+	if command.tables == nil {
+		return true
+	}
+
+	return command.tables.Match(filter.Object{Type: output.Table, Schema: table.Schema, Name: table.Name}) == nil
+}
+
+// SetSchemas устанавливает схемы таблиц диаграммы. Пустой список - все схемы
+func (command *ERDCommand) SetSchemas(schemas []string) {
+	command.schemas = schemas
+}
+
+// SetTables устанавливает фильтр таблиц диаграммы. nil - все таблицы
+func (command *ERDCommand) SetTables(filter filter.IFilter) {
+	command.tables = filter
+}
+
+// Diagram возвращает диаграмму, построенную командой
+func (command *ERDCommand) Diagram() *erd.Diagram {
+	return command.diagram
+}
+
+// EntityRelationshipDiagram возвращает диаграмму "сущность-связь" таблиц tables, выбранных функцией selected, с их
+// полями columns, первичными ключами из indexes и внешними ключами foreignKeys. Внешние ключи, ссылающиеся на
+// невыбранные таблицы, в диаграмму не включаются
+func EntityRelationshipDiagram(tables Tables, columns ObjectColumns, indexes ObjectsIndexes,
+	foreignKeys ObjectsForeignKeys, selected func(table *Table) bool) *erd.Diagram {
+	names := make([]string, 0, len(tables))
+
+	for name, table := range tables {
+		if selected(table) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		if tables[names[i]].Schema != tables[names[j]].Schema {
+			return tables[names[i]].Schema < tables[names[j]].Schema
+		}
+
+		return tables[names[i]].Name < tables[names[j]].Name
+	})
+
+	diagram := &erd.Diagram{
+		Entities:      make([]erd.Entity, 0, len(names)),
+		Relationships: make([]erd.Relationship, 0),
+	}
+
+	included := make(map[string]bool, len(names))
+
+	for _, name := range names {
+		included[name] = true
+	}
+
+	for _, name := range names {
+		table := tables[name]
+		entity := erd.Entity{Schema: table.Schema, Name: table.Name}
+
+		primaryKey := make(map[string]bool)
+
+		for _, index := range indexes[name].PrimaryKeys() {
+			for column := range index.Columns {
+				primaryKey[column] = true
+			}
+		}
+
+		foreignKey := make(map[string]bool)
+
+		for _, fk := range foreignKeys[name] {
+			for _, reference := range fk.ColumnsReferences {
+				foreignKey[reference.Column] = true
+			}
+		}
+
+		for _, column := range columns[name].Slice() {
+			entity.Attributes = append(entity.Attributes, erd.Attribute{
+				Name:       column.Name,
+				Type:       columnType(column),
+				Nullable:   column.IsNullable,
+				PrimaryKey: primaryKey[column.Name],
+				ForeignKey: foreignKey[column.Name],
+			})
+		}
+
+		diagram.Entities = append(diagram.Entities, entity)
+
+		for _, fk := range foreignKeys[name].Slice() {
+			parent := SchemaAndObject(fk.ReferencedObjectSchema, fk.ReferencedObjectName, true)
+
+			if !included[parent] {
+				continue
+			}
+
+			relationship := erd.Relationship{
+				Name:   fk.Name,
+				Child:  entity.String(),
+				Parent: erd.Entity{Schema: fk.ReferencedObjectSchema, Name: fk.ReferencedObjectName}.String(),
+				Unique: uniqueColumns(indexes[name], fk),
+			}
+
+			for _, reference := range fk.ColumnsReferences {
+				if column, ok := columns[name][reference.Column]; ok && column.IsNullable {
+					relationship.Optional = true
+				}
+			}
+
+			diagram.Relationships = append(diagram.Relationships, relationship)
+		}
+	}
+
+	return diagram
+}
+
+// uniqueColumns проверяет, что поля внешнего ключа fk уникальны: все ключевые поля одного из действующих первичных
+// ключей или уникальных индексов indexes входят во внешний ключ
+func uniqueColumns(indexes Indexes, fk *ForeignKey) bool {
+	columns := make(map[string]bool, len(fk.ColumnsReferences))
+
+	for _, reference := range fk.ColumnsReferences {
+		columns[reference.Column] = true
+	}
+
+	for _, index := range indexes {
+		if !index.IsUnique && !index.IsPrimaryKey || index.IsDisabled || index.IsHypothetical ||
+			len(index.Columns) == 0 {
+			continue
+		}
+
+		unique := true
+
+		for column := range index.Columns {
+			unique = unique && columns[column]
+		}
+
+		if unique {
+			return true
+		}
+	}
+
+	return false
+}
+
+// columnType возвращает тип данных поля column для диаграммы: без квадратных скобок, со схемой для пользовательских
+// типов, длиной или точностью и масштабом, например, nvarchar(50) или decimal(18, 2)
+func columnType(column *Column) string {
+	typeName := column.TypeName
+
+	if column.IsUserDefinedType && strings.Trim(column.TypeSchema, " ") != "" {
+		typeName = column.TypeSchema + "." + typeName
+	}
+
+	switch {
+	case column.HasMaxLength():
+		return typeName + "(" + column.MaxLength() + ")"
+	case column.HasPrecision() && column.Scale() > 0:
+		return typeName + "(" + strconv.Itoa(column.Precision()) + ", " + strconv.Itoa(column.Scale()) + ")"
+	case column.HasPrecision():
+		return typeName + "(" + strconv.Itoa(column.Precision()) + ")"
+	default:
+		return typeName
+	}
+}
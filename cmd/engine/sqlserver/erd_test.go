@@ -0,0 +1,143 @@
+package sqlserver
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/erd"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/filter"
+)
+
+func TestEntityRelationshipDiagram(t *testing.T) {
+	tables := Tables{
+		"[dbo].[Customers]":  &Table{Schema: "dbo", Name: "Customers"},
+		"[dbo].[Orders]":     &Table{Schema: "dbo", Name: "Orders"},
+		"[dbo].[Log]":        &Table{Schema: "dbo", Name: "Log"},
+		"[sales].[Invoices]": &Table{Schema: "sales", Name: "Invoices"},
+	}
+
+	columns := ObjectColumns{
+		"[dbo].[Customers]": Columns{
+			"ID":    &Column{ID: 1, Name: "ID", TypeName: "int"},
+			"Phone": &Column{ID: 2, Name: "Phone", TypeName: "Phone", TypeSchema: "dbo", IsUserDefinedType: true},
+		},
+		"[dbo].[Orders]": Columns{
+			"ID":         &Column{ID: 1, Name: "ID", TypeName: "int"},
+			"CustomerID": &Column{ID: 2, Name: "CustomerID", TypeName: "int"},
+			"Total": &Column{ID: 3, Name: "Total", TypeName: "decimal", IsNullable: true,
+				precision: sql.NullInt32{Int32: 18, Valid: true}, scale: sql.NullInt32{Int32: 2, Valid: true}},
+			"LogID": &Column{ID: 4, Name: "LogID", TypeName: "int", IsNullable: true},
+		},
+		"[sales].[Invoices]": Columns{
+			"OrderID": &Column{ID: 1, Name: "OrderID", TypeName: "int"},
+			"Number": &Column{ID: 2, Name: "Number", TypeName: "nvarchar",
+				maxLength: sql.NullString{String: "20", Valid: true}},
+			"ManagerID": &Column{ID: 3, Name: "ManagerID", TypeName: "int", IsNullable: true},
+		},
+	}
+
+	indexes := ObjectsIndexes{
+		"[dbo].[Customers]": Indexes{
+			"PK_Customers": &Index{Name: "PK_Customers", IsPrimaryKey: true, IsUnique: true,
+				Columns: IndexedColumns{"ID": &IndexedColumn{ID: 1, Name: "ID"}}},
+		},
+		"[dbo].[Orders]": Indexes{
+			"PK_Orders": &Index{Name: "PK_Orders", IsPrimaryKey: true, IsUnique: true,
+				Columns: IndexedColumns{"ID": &IndexedColumn{ID: 1, Name: "ID"}}},
+		},
+		"[sales].[Invoices]": Indexes{
+			"UQ_Invoices_OrderID": &Index{Name: "UQ_Invoices_OrderID", IsUnique: true,
+				Columns: IndexedColumns{"OrderID": &IndexedColumn{ID: 1, Name: "OrderID"}}},
+		},
+	}
+
+	foreignKeys := ObjectsForeignKeys{
+		"[dbo].[Orders]": ForeignKeys{
+			"FK_Orders_Customers": &ForeignKey{Name: "FK_Orders_Customers", ReferencedObjectSchema: "dbo",
+				ReferencedObjectName: "Customers", ColumnsReferences: map[string]*ColumnReference{
+					"1": {ID: 1, Column: "CustomerID", ReferencedColumn: "ID"}}},
+			"FK_Orders_Log": &ForeignKey{Name: "FK_Orders_Log", ReferencedObjectSchema: "dbo",
+				ReferencedObjectName: "Log", ColumnsReferences: map[string]*ColumnReference{
+					"1": {ID: 1, Column: "LogID", ReferencedColumn: "ID"}}},
+		},
+		"[sales].[Invoices]": ForeignKeys{
+			"FK_Invoices_Orders": &ForeignKey{Name: "FK_Invoices_Orders", ReferencedObjectSchema: "dbo",
+				ReferencedObjectName: "Orders", ColumnsReferences: map[string]*ColumnReference{
+					"1": {ID: 1, Column: "OrderID", ReferencedColumn: "ID"}}},
+			"FK_Invoices_Customers": &ForeignKey{Name: "FK_Invoices_Customers", ReferencedObjectSchema: "dbo",
+				ReferencedObjectName: "Customers", ColumnsReferences: map[string]*ColumnReference{
+					"1": {ID: 1, Column: "ManagerID", ReferencedColumn: "ID"}}},
+		},
+	}
+
+	diagram := EntityRelationshipDiagram(tables, columns, indexes, foreignKeys, func(table *Table) bool {
+		return table.Name != "Log"
+	})
+
+	want := &erd.Diagram{
+		Entities: []erd.Entity{
+			{Schema: "dbo", Name: "Customers", Attributes: []erd.Attribute{
+				{Name: "ID", Type: "int", PrimaryKey: true},
+				{Name: "Phone", Type: "dbo.Phone"},
+			}},
+			{Schema: "dbo", Name: "Orders", Attributes: []erd.Attribute{
+				{Name: "ID", Type: "int", PrimaryKey: true},
+				{Name: "CustomerID", Type: "int", ForeignKey: true},
+				{Name: "Total", Type: "decimal(18, 2)", Nullable: true},
+				{Name: "LogID", Type: "int", Nullable: true, ForeignKey: true},
+			}},
+			{Schema: "sales", Name: "Invoices", Attributes: []erd.Attribute{
+				{Name: "OrderID", Type: "int", ForeignKey: true},
+				{Name: "Number", Type: "nvarchar(20)"},
+				{Name: "ManagerID", Type: "int", Nullable: true, ForeignKey: true},
+			}},
+		},
+		Relationships: []erd.Relationship{
+			{Name: "FK_Orders_Customers", Child: "dbo.Orders", Parent: "dbo.Customers"},
+			{Name: "FK_Invoices_Customers", Child: "sales.Invoices", Parent: "dbo.Customers", Optional: true},
+			{Name: "FK_Invoices_Orders", Child: "sales.Invoices", Parent: "dbo.Orders", Unique: true},
+		},
+	}
+
+	if !reflect.DeepEqual(diagram, want) {
+		t.Errorf("have\n%+v\nwant\n%+v", diagram, want)
+	}
+}
+
+func TestERDCommand_selected(t *testing.T) {
+	var cases = []struct {
+		schemas []string
+		tables  []string
+		table   *Table
+		want    bool
+	}{
+		{table: &Table{Schema: "dbo", Name: "Orders"}, want: true},
+		{schemas: []string{"Sales"}, table: &Table{Schema: "sales", Name: "Invoices"}, want: true},
+		{schemas: []string{"sales"}, table: &Table{Schema: "dbo", Name: "Orders"}, want: false},
+		{tables: []string{"table:dbo.Order*"}, table: &Table{Schema: "dbo", Name: "Orders"}, want: true},
+		{tables: []string{"!table:dbo.Log"}, table: &Table{Schema: "dbo", Name: "Log"}, want: false},
+		{schemas: []string{"dbo"}, tables: []string{"view:*"}, table: &Table{Schema: "dbo", Name: "Orders"},
+			want: false},
+	}
+
+	for _, test := range cases {
+		command := &ERDCommand{}
+		command.SetSchemas(test.schemas)
+
+		if len(test.tables) > 0 {
+			tables, err := filter.New(test.tables)
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			command.SetTables(tables)
+		}
+
+		if have := command.selected(test.table); have != test.want {
+			t.Errorf("%v, %v, %s.%s: have %v, want %v", test.schemas, test.tables, test.table.Schema,
+				test.table.Name, have, test.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package sqlserver
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// goldenRuns количество повторных созданий скрипта. Справочники метаданных являются map, поэтому порядок их обхода
+// от запуска к запуску меняется, и скрипт должен совпадать с эталоном при каждом создании
+const goldenRuns = 20
+
+func goldenCommand() *ScriptsFolderCommand {
+	permissions := make(ObjectPermissions)
+
+	_ = permissions.Append("", "rpt", "SELECT", "GRANT", "reporter")
+	_ = permissions.Append("", "rpt", "EXECUTE", "GRANT", "reporter")
+	_ = permissions.Append("", "rpt", "DELETE", "DENY", "reporter")
+	_ = permissions.Append("", "rpt", "SELECT", "GRANT", "auditor")
+	_ = permissions.Append("dbo", "GetOrders", "EXECUTE", "GRANT", "web")
+	_ = permissions.Append("dbo", "GetOrders", "VIEW DEFINITION", "GRANT", "web")
+	_ = permissions.Append("dbo", "GetOrders", "EXECUTE", "DENY", "guest")
+	_ = permissions.Append("dbo", "GetOrders", "EXECUTE", "GRANT", "admin")
+
+	columns := make(ObjectColumns)
+
+	_ = columns.Append("dbo", "OrderList", &Column{ID: 3, Name: "Amount", TypeName: "money", IsNullable: true})
+	_ = columns.Append("dbo", "OrderList", &Column{ID: 1, Name: "ID", TypeName: "int"})
+	_ = columns.Append("dbo", "OrderList", &Column{ID: 2, Name: "Number", TypeName: "int"})
+	_ = columns.Append("dbo", "OrderList", &Column{ID: 4, Name: "Customer", TypeName: "int"})
+
+	indexes := ObjectsIndexes{
+		"[dbo].[OrderList]": Indexes{
+			"PK_OrderList": &Index{
+				Name:         "PK_OrderList",
+				Type:         "CLUSTERED",
+				IsUnique:     true,
+				IsPrimaryKey: true,
+				Columns: IndexedColumns{
+					"ID": &IndexedColumn{ID: 1, Name: "ID", KeyOrdinal: 1},
+				},
+			},
+			"IX_OrderList_Number": &Index{
+				Name: "IX_OrderList_Number",
+				Type: "NONCLUSTERED",
+				Columns: IndexedColumns{
+					"Number":   &IndexedColumn{ID: 1, Name: "Number", KeyOrdinal: 1},
+					"Customer": &IndexedColumn{ID: 2, Name: "Customer", KeyOrdinal: 2, IsDescendingKey: true},
+				},
+				IncludedColumns: IndexedColumns{
+					"Amount": &IndexedColumn{ID: 3, Name: "Amount"},
+					"ID":     &IndexedColumn{ID: 4, Name: "ID"},
+				},
+			},
+			"IX_OrderList_Customer": &Index{
+				Name: "IX_OrderList_Customer",
+				Type: "NONCLUSTERED",
+				Columns: IndexedColumns{
+					"Customer": &IndexedColumn{ID: 1, Name: "Customer", KeyOrdinal: 1},
+				},
+			},
+		},
+	}
+
+	userDefinedTypes := make(UserDefinedTypes)
+	userDefinedTypes.append(&UserDefinedType{Schema: "dbo", TypeName: "OrderList", IsTableType: true})
+
+	return &ScriptsFolderCommand{
+		engine:            &Engine{},
+		permissions:       permissions,
+		columns:           columns,
+		indexes:           indexes,
+		userDefinedTypes:  userDefinedTypes,
+		databaseCollation: "Cyrillic_General_CI_AS",
+	}
+}
+
+func TestScriptsFolderCommand_golden(t *testing.T) {
+	var cases = []struct {
+		golden string
+		object func() IDatabaseObject
+	}{
+		{
+			golden: "schema.golden",
+			object: func() IDatabaseObject {
+				object := testDatabaseObject("rpt", "", "SCHEMA")
+				object.owner = sql.NullString{String: "dbo", Valid: true}
+
+				return object
+			},
+		},
+		{
+			golden: "procedure.golden",
+			object: func() IDatabaseObject {
+				object := &module{
+					databaseObject:       *testDatabaseObject("dbo", "GetOrders", "PROCEDURE"),
+					usesANSINulls:        sql.NullBool{Bool: true, Valid: true},
+					usesQuotedIdentifier: sql.NullBool{Bool: true, Valid: true},
+				}
+
+				object.SetDefinition([]byte("CREATE PROCEDURE [dbo].[GetOrders] AS SELECT 1 AS [one]"))
+
+				return object
+			},
+		},
+		{
+			golden: "tabletype.golden",
+			object: func() IDatabaseObject {
+				return testDatabaseObject("dbo", "OrderList", "TABLE TYPE")
+			},
+		},
+	}
+
+	for _, test := range cases {
+		path := filepath.Join("testdata", test.golden)
+
+		var want []byte
+
+		for run := 0; run < goldenRuns; run++ {
+			object, err := goldenCommand().writeDefinition(context.Background(), test.object())
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			have := object.(IDatabaseObject).Definition()
+
+			if *update && run == 0 {
+				if err = ioutil.WriteFile(path, have, 0664); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if want == nil {
+				if want, err = ioutil.ReadFile(path); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if string(have) != string(want) {
+				t.Fatalf("%s: run %d: have\n%s\nwant\n%s", test.golden, run, have, want)
+			}
+		}
+	}
+}
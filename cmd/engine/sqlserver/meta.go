@@ -3,6 +3,7 @@ package sqlserver
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 // MetadataReader объект чтения метаданных из базы
@@ -10,15 +11,44 @@ type MetadataReader struct {
 	db *sql.DB
 
 	serverVersion int
+	engineEdition EngineEdition
+	queryTimeout  time.Duration
 }
 
 // NewMetadataReader конструктор MetadataReader
-func NewMetadataReader(engine *Engine, serverVersion int) (*MetadataReader, error) {
-	return &MetadataReader{db: engine.db, serverVersion: serverVersion}, nil
+func NewMetadataReader(engine *Engine, serverVersion int, engineEdition EngineEdition) (*MetadataReader, error) {
+	return &MetadataReader{db: engine.db, serverVersion: serverVersion, engineEdition: engineEdition,
+		queryTimeout: engine.queryTimeout}, nil
+}
+
+// queryContext возвращает контекст выполнения запроса, ограниченный таймаутом запроса
+func (meta *MetadataReader) queryContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, meta.queryTimeout)
+}
+
+// versionedQuery возвращает текст запроса из набора queries для версии SQL Server, ближайшей снизу к версии,
+// с которой работает объект чтения метаданных. Если подходящего варианта нет, то возвращается fallback
+func (meta *MetadataReader) versionedQuery(queries map[int]string, fallback string) string {
+	version := effectiveServerVersion(meta.serverVersion, meta.engineEdition)
+
+	query := fallback
+	selected := 0
+
+	for v, q := range queries {
+		if v <= version && v > selected {
+			query = q
+			selected = v
+		}
+	}
+
+	return query
 }
 
 // Permissions возвращает разрешения на объекты БД
 func (reader *MetadataReader) Permissions(ctx context.Context) (ObjectPermissions, error) {
+	ctx, cancel := reader.queryContext(ctx)
+	defer cancel()
+
 	stmt, err := reader.db.PrepareContext(ctx, selectPermissions)
 
 	if err != nil {
@@ -70,6 +100,9 @@ func (reader *MetadataReader) Permissions(ctx context.Context) (ObjectPermission
 
 // UserDefinedTypes возвращает список пользовательских типов
 func (reader *MetadataReader) UserDefinedTypes(ctx context.Context) (UserDefinedTypes, error) {
+	ctx, cancel := reader.queryContext(ctx)
+	defer cancel()
+
 	stmt, err := reader.db.PrepareContext(ctx, selectUserDefinedTypes)
 
 	if err != nil {
@@ -132,6 +165,9 @@ func (reader *MetadataReader) UserDefinedTypes(ctx context.Context) (UserDefined
 
 // ObjectColumns возвращает поля объектов БД (таблиц/табличных типов)
 func (reader *MetadataReader) ObjectColumns(ctx context.Context) (ObjectColumns, error) {
+	ctx, cancel := reader.queryContext(ctx)
+	defer cancel()
+
 	stmt, err := reader.db.PrepareContext(ctx, selectColumns)
 
 	if err != nil {
@@ -259,8 +295,58 @@ func (reader *MetadataReader) ObjectColumns(ctx context.Context) (ObjectColumns,
 	return columns, nil
 }
 
+// Database возвращает параметры базы данных
+func (meta *MetadataReader) Database(ctx context.Context) (*Database, error) {
+	ctx, cancel := meta.queryContext(ctx)
+	defer cancel()
+
+	query := selectDatabase
+
+	if meta.engineEdition.IsAzureSQLDatabase() {
+		query = selectDatabaseAzure
+	}
+
+	stmt, err := meta.db.PrepareContext(ctx, query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer stmt.Close()
+
+	var (
+		name             string
+		collation        string
+		server           string
+		edition          sql.NullString
+		serviceObjective sql.NullString
+		elasticPool      sql.NullString
+	)
+
+	err = stmt.QueryRowContext(ctx).Scan(&name, &collation, &server, &edition, &serviceObjective,
+		&elasticPool)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &Database{
+		Name:          name,
+		Collation:     collation,
+		EngineEdition: meta.engineEdition,
+		Server:        server,
+
+		edition:          edition,
+		serviceObjective: serviceObjective,
+		elasticPool:      elasticPool,
+	}, nil
+}
+
 // DatabaseCollation возвращает collation базы данных
 func (meta *MetadataReader) DatabaseCollation(ctx context.Context) (string, error) {
+	ctx, cancel := meta.queryContext(ctx)
+	defer cancel()
+
 	stmt, err := meta.db.PrepareContext(ctx, selectDatabaseCollation)
 
 	if err != nil {
@@ -276,24 +362,38 @@ func (meta *MetadataReader) DatabaseCollation(ctx context.Context) (string, erro
 	return collation, err
 }
 
+// ServerTime возвращает текущее время сервера БД. Время возвращается без часового пояса, как и время изменения
+// объектов БД (sys.objects.modify_date), чтобы их можно было сравнивать
+func (meta *MetadataReader) ServerTime(ctx context.Context) (time.Time, error) {
+	ctx, cancel := meta.queryContext(ctx)
+	defer cancel()
+
+	var now time.Time
+
+	err := meta.db.QueryRowContext(ctx, selectServerTime).Scan(&now)
+
+	return now, err
+}
+
+const selectServerTime = `select getdate()`
+
 var selectIndexesQueries = map[int]string{
-	13: selectIndexes2016,
-	15: selectIndexes2019,
+	ServerVersion2016: selectIndexes2016,
+	ServerVersion2019: selectIndexes2019,
 }
 
 // selectIndexesQuery возвращает текст запроса набора индексов для соответствующей версии SQL Server.
-// Если для указанной версии нет варианта текста запроса, то возвращается текст для минимальной поддерживаемой
-// версии
+// Если для указанной версии нет варианта текста запроса, то возвращается текст для ближайшей младшей версии, а
+// при ее отсутствии - для минимальной поддерживаемой версии
 func (meta *MetadataReader) selectIndexesQuery() string {
-	if query, ok := selectIndexesQueries[meta.serverVersion]; ok {
-		return query
-	}
-
-	return selectIndexes2016
+	return meta.versionedQuery(selectIndexesQueries, selectIndexes2016)
 }
 
 // ObjectsIndexes возвращает справочник индексов из БД, сгруппированных по объектам
 func (meta *MetadataReader) Indexes(ctx context.Context) (ObjectsIndexes, error) {
+	ctx, cancel := meta.queryContext(ctx)
+	defer cancel()
+
 	stmt, err := meta.db.PrepareContext(ctx, meta.selectIndexesQuery())
 
 	if err != nil {
@@ -424,6 +524,9 @@ func (meta *MetadataReader) Indexes(ctx context.Context) (ObjectsIndexes, error)
 
 // ObjectsForeignKeys возвращает справочник внешних ключей
 func (meta *MetadataReader) ForeignKeys(ctx context.Context) (ObjectsForeignKeys, error) {
+	ctx, cancel := meta.queryContext(ctx)
+	defer cancel()
+
 	stmt, err := meta.db.PrepareContext(ctx, selectForeignKeys)
 
 	if err != nil {
@@ -511,24 +614,28 @@ func (meta *MetadataReader) ForeignKeys(ctx context.Context) (ObjectsForeignKeys
 }
 
 var selectTablesQueries = map[int]string{
-	13: selectTables2016,
-	14: selectTables2017,
-	15: selectTables2019,
+	ServerVersion2016: selectTables2016,
+	ServerVersion2017: selectTables2017,
+	ServerVersion2019: selectTables2019,
 }
 
 // selectTablesQuery возвращает текст запроса набора таблиц для соответствующей версии SQL Server.
-// Если для указанной версии нет варианта текста запроса, то возвращается текст для минимальной поддерживаемой
-// версии
+// Если для указанной версии нет варианта текста запроса, то возвращается текст для ближайшей младшей версии, а
+// при ее отсутствии - для минимальной поддерживаемой версии. Для Azure SQL Database используется отдельный
+// вариант запроса, не обращающийся к файловым группам
 func (meta *MetadataReader) selectTablesQuery() string {
-	if query, ok := selectTablesQueries[meta.serverVersion]; ok {
-		return query
+	if meta.engineEdition.IsAzureSQLDatabase() {
+		return selectTablesAzure
 	}
 
-	return selectTables2016
+	return meta.versionedQuery(selectTablesQueries, selectTables2016)
 }
 
 // Tables возвращает коллекцию пользовтельских таблиц, имеющихся в БД
 func (meta *MetadataReader) Tables(ctx context.Context) (Tables, error) {
+	ctx, cancel := meta.queryContext(ctx)
+	defer cancel()
+
 	stmt, err := meta.db.PrepareContext(ctx, meta.selectTablesQuery())
 
 	if err != nil {
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
 	"strings"
 
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
@@ -12,18 +11,6 @@ import (
 
 type descriptionCallback func() string
 
-const procedureDescription = `
-EXECUTE sp_addextendedproperty @name = N'MS_Description', @level0type = N'SCHEMA', @level0name = N'%s', @level1type = N'PROCEDURE', @level0name = N'%s', @value = N'%s'
-GO`
-
-const functionDescription = `
-EXECUTE sp_addextendedproperty @name = N'MS_Description', @level0type = N'SCHEMA', @level0name = N'%s', @level1type = N'FUNCTION', @level0name = N'%s', @value = N'%s'
-GO`
-
-const viewDescription = `
-EXECUTE sp_addextendedproperty @name = N'MS_Description', @level0type = N'SCHEMA', @level0name = N'%s', @level1type = N'VIEW', @level0name = N'%s', @value = N'%s'
-GO`
-
 func (command *ScriptsFolderCommand) writeProcedureDefinition(ctx context.Context, object interface{}) (interface{},
 	error) {
 	obj, ok := object.(ISQLModule)
@@ -43,7 +30,7 @@ func (command *ScriptsFolderCommand) writeProcedureDefinition(ctx context.Contex
 			return ""
 		}
 
-		return fmt.Sprintf(procedureDescription, obj.Schema(), obj.Name(), obj.Description())
+		return "\n" + command.descriptionStatement(obj.Schema(), "PROCEDURE", obj.Name(), description)
 	})
 }
 
@@ -66,7 +53,7 @@ func (command *ScriptsFolderCommand) writeFunctionDefinition(ctx context.Context
 			return ""
 		}
 
-		return fmt.Sprintf(functionDescription, obj.Schema(), obj.Name(), obj.Description())
+		return "\n" + command.descriptionStatement(obj.Schema(), "FUNCTION", obj.Name(), description)
 	})
 }
 
@@ -89,7 +76,7 @@ func (command *ScriptsFolderCommand) writeViewDefinition(ctx context.Context, ob
 			return ""
 		}
 
-		return fmt.Sprintf(viewDescription, obj.Schema(), obj.Name(), obj.Description())
+		return "\n" + command.descriptionStatement(obj.Schema(), "VIEW", obj.Name(), description)
 	})
 }
 
@@ -113,10 +100,10 @@ func (command *ScriptsFolderCommand) writeModuleDefinition(ctx context.Context,
 	definition := string(object.Definition())
 
 	if strings.Trim(definition, " ") == "" {
-		return object, nil
+		return object, errorEncrypted
 	}
 
-	definition = fmt.Sprintf("%s\nGO", strings.Trim(definition, "\n"))
+	definition = command.styledStatement(object, command.adaptedModuleDefinition(strings.Trim(definition, "\n")))
 
 	mod := make([]string, 0)
 
@@ -143,17 +130,11 @@ func (command *ScriptsFolderCommand) writeModuleDefinition(ctx context.Context,
 		permissions := command.permissions[name]
 
 		if len(permissions) > 0 {
-			users := permissions.Users()
-			sort.Strings(users)
-
-			for _, user := range users {
+			for _, user := range permissions.Users() {
 				states := permissions[user]
 
-				for state, perms := range states {
-					ps := perms.List()
-					sort.Strings(ps)
-
-					for _, p := range ps {
+				for _, state := range states.States() {
+					for _, p := range states[state].List() {
 						definition = fmt.Sprintf("%s\n\n%s %s ON %s TO [%s]\nGO", definition, state, p, name, user)
 					}
 				}
@@ -2,6 +2,7 @@ package sqlserver
 
 import (
 	"database/sql"
+	"time"
 
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
 )
@@ -28,6 +29,11 @@ type IDatabaseObject interface {
 	Owner() string
 	// Description возвращает описание объекта БД
 	Description() string
+	// Parent возвращает наименование родительского объекта БД (таблицы или представления триггера)
+	Parent() string
+	// ModifyDate возвращает время последнего изменения объекта БД по часам сервера. Нулевое время - время изменения
+	// неизвестно (база данных, схемы, пользовательские типы данных)
+	ModifyDate() time.Time
 }
 
 // ISQLModule интерфейс SQL модуля (процедура, скалярная/табличная функция, представление, триггер...)
@@ -60,6 +66,10 @@ type databaseObject struct {
 	owner sql.NullString
 	// description описание объекта БД
 	description sql.NullString
+	// parent наименование родительского объекта БД
+	parent sql.NullString
+	// modifyDate время последнего изменения объекта БД
+	modifyDate sql.NullTime
 }
 
 // Catalog наименование базы данных
@@ -121,8 +131,11 @@ func (object databaseObject) Type() output.DatabaseObjectType {
 		return output.UnknownObject
 	}
 
-	objectType := object.objectType.String
+	return parseObjectType(object.objectType.String)
+}
 
+// parseObjectType возвращает тип объекта БД по его наименованию в результатах запросов метаданных
+func parseObjectType(objectType string) output.DatabaseObjectType {
 	switch objectType {
 	case "DATABASE":
 		return output.Database
@@ -173,6 +186,24 @@ func (object databaseObject) Description() string {
 	return ""
 }
 
+// Parent наименование родительского объекта БД (таблицы или представления триггера)
+func (object databaseObject) Parent() string {
+	if object.parent.Valid {
+		return object.parent.String
+	}
+
+	return ""
+}
+
+// ModifyDate время последнего изменения объекта БД
+func (object databaseObject) ModifyDate() time.Time {
+	if object.modifyDate.Valid {
+		return object.modifyDate.Time
+	}
+
+	return time.Time{}
+}
+
 type module struct {
 	databaseObject
 
@@ -2,12 +2,13 @@ package sqlserver
 
 import (
 	"fmt"
+	"sort"
 )
 
 // Permissions разрешения
 type Permissions map[string]bool
 
-// List возвращает срез разрешений
+// List возвращает срез разрешений, упорядоченный по наименованию
 func (perms Permissions) List() []string {
 	if len(perms) == 0 {
 		return nil
@@ -22,6 +23,8 @@ func (perms Permissions) List() []string {
 		index++
 	}
 
+	sort.Strings(list)
+
 	return list
 }
 
@@ -76,6 +79,21 @@ func NewPermissionState(value string) PermissionState {
 // PermStates состояния разрешений (GRANT, DENY etc)
 type PermStates map[PermissionState]Permissions
 
+// States возвращает упорядоченный список состояний разрешений
+func (states PermStates) States() []PermissionState {
+	list := make([]PermissionState, 0, len(states))
+
+	for state := range states {
+		list = append(list, state)
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i] < list[j]
+	})
+
+	return list
+}
+
 // UserPerms разрешения пользователя
 type UserPerms map[string]PermStates
 
@@ -131,7 +149,7 @@ func (perms ObjectPermissions) append(objectName, permission string, state Permi
 	return nil
 }
 
-// Users возвращает список пользователей, обладающих правами на указанный объект
+// Users возвращает упорядоченный список пользователей, обладающих правами на указанный объект
 func (perms UserPerms) Users() []string {
 	users := make([]string, len(perms))
 	var index int
@@ -141,9 +159,48 @@ func (perms UserPerms) Users() []string {
 		index++
 	}
 
+	sort.Strings(users)
+
 	return users
 }
 
+// Permission разрешение пользователя на объект БД
+type Permission struct {
+	// User пользователь
+	User string
+	// State состояние разрешения (GRANT, DENY etc)
+	State PermissionState
+	// Permission разрешение (SELECT, EXECUTE etc)
+	Permission string
+}
+
+// Permissions возвращает список разрешений пользователей, упорядоченный по пользователю, состоянию и разрешению
+func (perms UserPerms) Permissions() []Permission {
+	list := make([]Permission, 0)
+
+	for user, states := range perms {
+		for state, permissions := range states {
+			for permission := range permissions {
+				list = append(list, Permission{User: user, State: state, Permission: permission})
+			}
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].User != list[j].User {
+			return list[i].User < list[j].User
+		}
+
+		if list[i].State != list[j].State {
+			return list[i].State < list[j].State
+		}
+
+		return list[i].Permission < list[j].Permission
+	})
+
+	return list
+}
+
 const selectPermissions = `
 select permissions.[schema], permissions.object, permissions.permission, permissions.state, permissions.[user]
 from (
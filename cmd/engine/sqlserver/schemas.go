@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"sort"
 	"strings"
 
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
@@ -32,6 +31,8 @@ func (command *ScriptsFolderCommand) writeSchemaDefinition(ctx context.Context,
 		definition = fmt.Sprintf(schemaShortDefinition, obj.Schema())
 	}
 
+	definition = command.styledStatement(obj, definition)
+
 	if !command.skipPermissions {
 		objectName := obj.SchemaAndName(true)
 		permissions := command.permissions[objectName]
@@ -39,12 +40,11 @@ func (command *ScriptsFolderCommand) writeSchemaDefinition(ctx context.Context,
 		if len(permissions) > 0 {
 			var stringPermissions string
 
-			for user, userPermissions := range permissions {
-				for state, permissions := range userPermissions {
-					if len(permissions) > 0 {
-						perms := permissions.List()
-						sort.Strings(perms)
+			for _, user := range permissions.Users() {
+				userPermissions := permissions[user]
 
+				for _, state := range userPermissions.States() {
+					if perms := userPermissions[state].List(); len(perms) > 0 {
 						stringPermissions = strings.Join(perms, ",\n  ")
 						stringPermissions = fmt.Sprintf("%s\n  %s\nON SCHEMA :: %s TO [%s]\nGO", state.String(),
 							stringPermissions, objectName, user)
@@ -59,8 +59,7 @@ func (command *ScriptsFolderCommand) writeSchemaDefinition(ctx context.Context,
 	description := obj.Description()
 
 	if strings.Trim(description, " ") != "" {
-		description = fmt.Sprintf(schemaDescription, obj.SchemaAndName(false), description)
-		definition = fmt.Sprintf("%s\n%s", definition, description)
+		definition = fmt.Sprintf("%s\n\n%s", definition, command.descriptionStatement(obj.Schema(), "", "", description))
 	}
 
 	obj.SetDefinition([]byte(definition))
@@ -68,12 +67,6 @@ func (command *ScriptsFolderCommand) writeSchemaDefinition(ctx context.Context,
 	return obj, nil
 }
 
-const schemaDefinition = `CREATE SCHEMA [%s] AUTHORIZATION [%s]
-GO`
-
-const schemaShortDefinition = `CREATE SCHEMA [%s]
-GO`
+const schemaDefinition = `CREATE SCHEMA [%s] AUTHORIZATION [%s]`
 
-const schemaDescription = `
-EXECUTE sp_addextendedproperty @name = N'MS_Description', @level0type = N'SCHEMA', @level0name = N'%s', @value = N'%s'
-GO`
+const schemaShortDefinition = `CREATE SCHEMA [%s]`
@@ -3,11 +3,18 @@ package sqlserver
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/reactivex/rxgo/v2"
 
 	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/filter"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/graph"
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/log"
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
 )
@@ -17,11 +24,17 @@ type ScriptsFolderCommand struct {
 	engine             *Engine
 	include            filter.IFilter
 	exclude            filter.IFilter
+	withDependencies   bool
+	withDependents     bool
+	objectDependencies bool
+	modifiedSince      time.Time
+	serverTime         time.Time
 	decrypt            bool
 	includeStaticData  bool
 	skipPermissions    bool
 	types              map[output.DatabaseObjectType]bool
 	definitionCallback commands.ObjectDefinitionCallback
+	skippedCallback    commands.SkippedObjectCallback
 	metaReader         *MetadataReader
 
 	userDefinedTypes UserDefinedTypes
@@ -30,8 +43,29 @@ type ScriptsFolderCommand struct {
 	indexes          ObjectsIndexes
 	foreignKeys      ObjectsForeignKeys
 	tables           Tables
+	database         *Database
+	dependencies     []Dependency
+
+	// dependencyGraph граф зависимостей всех объектов БД. nil - зависимости не читаются
+	dependencyGraph *graph.Graph
+
+	// expanded объекты БД, выбранные фильтрами, вместе с их зависимостями и зависимыми объектами. nil - зависимости
+	// не добавляются
+	expanded map[graph.Node]bool
 
 	databaseCollation string
+
+	targetVersion       string
+	targetServerVersion int
+	scriptStyle         commands.ScriptStyle
+	templates           output.Templates
+	parallel            int
+	failFast            bool
+	progressCallback    commands.ProgressCallback
+	progressMutex       sync.Mutex
+	warnings            []string
+	resultMutex         sync.Mutex
+	skipped             []commands.SkippedObject
 }
 
 // NewScriptsFolderCommand конструктор ScriptsFolderCommand
@@ -42,11 +76,15 @@ func NewScriptsFolderCommand(engine *Engine, options ...commands.ScriptsFolderOp
 		engine:             engine,
 		include:            nil,
 		exclude:            nil,
+		withDependencies:   false,
+		withDependents:     false,
+		objectDependencies: false,
 		decrypt:            false,
 		includeStaticData:  false,
 		skipPermissions:    false,
 		types:              nil,
 		definitionCallback: nil,
+		skippedCallback:    nil,
 		metaReader:         metaReader,
 
 		permissions:      nil,
@@ -55,8 +93,20 @@ func NewScriptsFolderCommand(engine *Engine, options ...commands.ScriptsFolderOp
 		indexes:          nil,
 		foreignKeys:      nil,
 		tables:           nil,
+		database:         nil,
+		dependencies:     nil,
 
 		databaseCollation: "",
+
+		targetVersion:       "",
+		targetServerVersion: 0,
+		scriptStyle:         commands.ScriptStyleCreate,
+		templates:           nil,
+		parallel:            1,
+		failFast:            false,
+		progressCallback:    nil,
+		warnings:            nil,
+		skipped:             nil,
 	}
 
 	for _, option := range options {
@@ -66,61 +116,350 @@ func NewScriptsFolderCommand(engine *Engine, options ...commands.ScriptsFolderOp
 	return command
 }
 
-// Run запускает выполнение команды
-func (command *ScriptsFolderCommand) Run() error {
-	ctx := context.Background()
+// Run запускает выполнение команды. Ошибки создания и сохранения скриптов отдельных объектов БД не прерывают
+// выполнение команды (если не указан режим FailFast) и возвращаются списком commands.MultiError. При отмене
+// контекста ctx выполнение прерывается и возвращается ошибка контекста
+func (command *ScriptsFolderCommand) Run(ctx context.Context) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if strings.Trim(command.targetVersion, " ") != "" {
+		version, err := ParseServerVersion(command.targetVersion)
+
+		if err != nil {
+			return fmt.Errorf("invalid target version: %v", err)
+		}
+
+		command.targetServerVersion = version
+	}
+
+	started := time.Now()
+
+	command.engine.LogFields(log.DebugLevel, log.Fields{log.FieldPhase: commands.PhaseMetadata.String()},
+		"metadata reading...")
+
+	serverTime, err := command.metaReader.ServerTime(ctx)
+
+	if err != nil {
+		return err
+	}
 
-	command.engine.Log(log.DebugLevel, "metadata reading...")
+	command.serverTime = serverTime
 
-	err := command.ReadMetadata(ctx)
+	err = command.ReadMetadata(ctx)
 
 	if err != nil {
 		return err
 	}
 
+	command.engine.LogFields(log.DebugLevel, log.Fields{
+		log.FieldPhase:    commands.PhaseMetadata.String(),
+		log.FieldDuration: time.Since(started).Seconds(),
+	}, "metadata read")
+
 	objects, err := command.databaseObjects(ctx)
 
 	if err != nil {
 		return err
 	}
 
-	observable := rxgo.FromChannel(objects)
+	objects, total := command.enumerate(ctx, objects)
 
-	<-observable.
-		Filter(func(item interface{}) bool {
-			object := item.(IDatabaseObject)
-			return command.ObjectTypeIncluded(object.Type())
-		}).
-		Filter(func(item interface{}) bool {
-			object := item.(IDatabaseObject)
-			return command.Included(object.SchemaAndName(true)) == nil
-		}).
+	err = command.process(ctx, objects, total)
+
+	command.progress(commands.ProgressEvent{Phase: commands.PhaseDone, Done: total, Total: total})
+
+	command.engine.LogFields(log.DebugLevel, log.Fields{
+		log.FieldPhase:    commands.PhaseDone.String(),
+		log.FieldDuration: time.Since(started).Seconds(),
+	}, "done")
+
+	return err
+}
+
+// enumerate получает список объектов БД, которые будут обработаны командой, и возвращает их вместе с их
+// количеством. Количество объектов известно только после чтения всего списка, поэтому объекты буферизуются
+func (command *ScriptsFolderCommand) enumerate(ctx context.Context, objects chan rxgo.Item) (chan rxgo.Item, int) {
+	items := make([]rxgo.Item, 0)
+	total := 0
+
+	command.progress(commands.ProgressEvent{Phase: commands.PhaseEnumeration})
+
+	for item := range objects {
+		if ctx.Err() != nil {
+			break
+		}
+
+		items = append(items, item)
+	}
+
+	if command.needsDependencies() {
+		command.dependencyGraph = DependencyGraph(itemObjects(items), command.dependencies, command.foreignKeys,
+			command.columns)
+
+		if command.withDependencies || command.withDependents {
+			command.expand(items)
+		}
+	}
+
+	for _, item := range items {
+		if item.Error() {
+			continue
+		}
+
+		object := item.V.(IDatabaseObject)
+
+		switch {
+		case command.included(object):
+			total++
+			command.progress(commands.ProgressEvent{Phase: commands.PhaseEnumeration, Done: total})
+		case object.Type() == output.UnknownObject:
+			command.skip(object, commands.PhaseEnumeration, commands.SkipUnsupported)
+		case command.matched(object) && !command.modified(object):
+			command.skip(object, commands.PhaseEnumeration, commands.SkipUnchanged)
+		default:
+			command.skip(object, commands.PhaseEnumeration, commands.SkipFiltered)
+		}
+	}
+
+	out := make(chan rxgo.Item, len(items))
+
+	for _, item := range items {
+		out <- item
+	}
+
+	close(out)
+
+	return out, total
+}
+
+// included проверяет, должен ли объект object обрабатываться командой
+func (command *ScriptsFolderCommand) included(object IDatabaseObject) bool {
+	if !command.ObjectTypeIncluded(object.Type()) || command.Excluded(object) != filter.ErrorNotMatched {
+		return false
+	}
+
+	if command.expanded != nil {
+		return command.expanded[objectNode(object)]
+	}
+
+	return command.Included(object) == nil && command.modified(object)
+}
+
+// matched проверяет, что объект object выбран фильтрами и его тип обрабатывается командой
+func (command *ScriptsFolderCommand) matched(object IDatabaseObject) bool {
+	return command.ObjectTypeIncluded(object.Type()) && command.Included(object) == nil &&
+		command.Excluded(object) == filter.ErrorNotMatched
+}
+
+// modified проверяет, изменен ли объект object начиная с времени SetModifiedSince. Объекты с неизвестным временем
+// изменения (база данных, схемы, пользовательские типы данных) считаются измененными, чтобы их скрипты не пропадали
+// из выгрузки
+func (command *ScriptsFolderCommand) modified(object IDatabaseObject) bool {
+	if command.modifiedSince.IsZero() {
+		return true
+	}
+
+	modifyDate := object.ModifyDate()
+
+	return modifyDate.IsZero() || !modifyDate.Before(command.modifiedSince)
+}
+
+// expand добавляет к объектам БД items, выбранным фильтрами, их зависимости вместе со схемами и (или) зависимые от
+// них объекты. Исключенные фильтром объекты не добавляются, но зависимости через них учитываются
+func (command *ScriptsFolderCommand) expand(items []rxgo.Item) {
+	objects := itemObjects(items)
+	roots := make([]graph.Node, 0)
+
+	for _, object := range objects {
+		if command.included(object) {
+			roots = append(roots, objectNode(object))
+		}
+	}
+
+	dependencies := command.dependencyGraph
+	expanded := make(map[graph.Node]bool, len(roots))
+
+	for _, node := range roots {
+		expanded[node] = true
+	}
+
+	if command.withDependencies {
+		schemas := make(map[string]bool)
+
+		for _, node := range dependencies.Dependencies(roots, 0) {
+			expanded[node] = true
+			schemas[node.Schema] = true
+		}
+
+		// схемы объектов нужны для их создания
+		for _, object := range objects {
+			if object.Type() == output.Schema && schemas[object.Schema()] {
+				expanded[objectNode(object)] = true
+			}
+		}
+	}
+
+	if command.withDependents {
+		for _, node := range dependencies.Dependents(roots, 0) {
+			expanded[node] = true
+		}
+	}
+
+	command.expanded = expanded
+}
+
+// itemObjects возвращает объекты БД из списка items без ошибок чтения
+func itemObjects(items []rxgo.Item) []IDatabaseObject {
+	objects := make([]IDatabaseObject, 0, len(items))
+
+	for _, item := range items {
+		if !item.Error() {
+			objects = append(objects, item.V.(IDatabaseObject))
+		}
+	}
+
+	return objects
+}
+
+// needsDependencies проверяет, нужны ли команде зависимости объектов БД
+func (command *ScriptsFolderCommand) needsDependencies() bool {
+	return command.withDependencies || command.withDependents || command.objectDependencies
+}
+
+// indexedObject объект БД с порядковым номером в списке обрабатываемых объектов. Порядковый номер позволяет
+// передавать скрипты в callback в исходном порядке при параллельном создании скриптов
+type indexedObject struct {
+	index   int
+	object  IDatabaseObject
+	err     error
+	skipped bool
+	// duration длительность создания скрипта объекта
+	duration time.Duration
+}
+
+// process создает скрипты объектов БД objects и передает их в callback в порядке следования объектов. При
+// parallel > 1 скрипты создаются параллельно, а созданные вне очереди скрипты придерживаются до тех пор, пока не
+// будут переданы скрипты предшествующих объектов. Возвращает ошибки обработки объектов в порядке следования
+// объектов. total - количество обрабатываемых объектов для отображения хода выполнения
+func (command *ScriptsFolderCommand) process(parent context.Context, objects chan rxgo.Item, total int) error {
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+
+	var (
+		errs     commands.MultiError
+		index    int
+		next     int
+		stopped  bool
+		rendered int32
+	)
+
+	fail := func(err error, fields log.Fields) {
+		command.engine.LogFields(log.ErrorLevel, fields, err)
+		errs = append(errs, err)
+
+		if command.failFast {
+			stopped = true
+			cancel()
+		}
+	}
+
+	pending := make(map[int]*indexedObject)
+	options := []rxgo.Option{rxgo.WithContext(ctx), rxgo.WithErrorStrategy(rxgo.ContinueOnError)}
+	mapOptions := options
+
+	if command.parallel > 1 {
+		mapOptions = append(mapOptions, rxgo.WithPool(command.parallel))
+	}
+
+	<-rxgo.FromChannel(objects, options...).
 		Filter(func(item interface{}) bool {
-			object := item.(IDatabaseObject)
-			return command.Excluded(object.SchemaAndName(true)) == filter.ErrorNotMatched
-		}).
+			return command.included(item.(IDatabaseObject))
+		}, options...).
+		Map(func(ctx context.Context, item interface{}) (interface{}, error) {
+			indexed := &indexedObject{index: index, object: item.(IDatabaseObject)}
+			index++
+
+			return indexed, nil
+		}, options...).
 		Map(func(ctx context.Context, item interface{}) (interface{}, error) {
-			obj := item.(IDatabaseObject)
-			return command.writeDefinition(ctx, obj)
-		}).
+			indexed := item.(*indexedObject)
+			started := time.Now()
+
+			_, err := command.writeDefinition(ctx, indexed.object)
+
+			indexed.duration = time.Since(started)
+
+			switch err {
+			case nil:
+			case errorEncrypted:
+				indexed.skipped = true
+				command.skip(indexed.object, commands.PhaseRendering, commands.SkipEncrypted)
+			case errorUnsupported:
+				indexed.skipped = true
+				command.skip(indexed.object, commands.PhaseRendering, commands.SkipUnsupported)
+			default:
+				indexed.err = commands.NewObjectError(indexed.object.SchemaAndName(true), err)
+			}
+
+			command.progress(commands.ProgressEvent{
+				Phase:  commands.PhaseRendering,
+				Object: indexed.object.SchemaAndName(true),
+				Done:   int(atomic.AddInt32(&rendered, 1)),
+				Total:  total,
+			})
+
+			return indexed, nil
+		}, mapOptions...).
 		ForEach(func(item interface{}) {
-			object := item.(IDatabaseObject)
-			command.engine.Log(log.DebugLevel, object.SchemaAndName(true))
+			indexed := item.(*indexedObject)
+			pending[indexed.index] = indexed
+
+			for indexed, ok := pending[next]; ok && !stopped && ctx.Err() == nil; indexed, ok = pending[next] {
+				delete(pending, next)
+				next++
+
+				if indexed.err != nil {
+					fail(indexed.err, objectFields(indexed.object, commands.PhaseRendering, indexed.duration))
+					continue
+				}
+
+				if indexed.skipped {
+					continue
+				}
+
+				object := indexed.object
+				started := time.Now()
+
+				err := command.callObjectDefinitionCallback(object)
+
+				// длительность обработки объекта складывается из создания и сохранения его скрипта
+				fields := objectFields(object, commands.PhaseWriting, indexed.duration+time.Since(started))
 
-			err := command.callObjectDefinitionCallback(object)
+				if err != nil {
+					fail(commands.NewObjectError(object.SchemaAndName(true), err), fields)
+				} else {
+					command.engine.LogFields(log.DebugLevel, fields, object.SchemaAndName(true))
+				}
 
-			if err != nil {
-				command.engine.Log(log.ErrorLevel, err)
+				command.progress(commands.ProgressEvent{
+					Phase:  commands.PhaseWriting,
+					Object: object.SchemaAndName(true),
+					Done:   next,
+					Total:  total,
+				})
 			}
 		}, func(err error) {
-			if err != nil {
-				command.engine.Log(log.ErrorLevel, err)
+			if !stopped {
+				fail(err, nil)
 			}
-		}, func() {
-			command.engine.Log(log.DebugLevel, "done")
-		})
+		}, func() {}, options...)
 
-	return nil
+	if err := parent.Err(); err != nil {
+		return err
+	}
+
+	return errs.ErrorOrNil()
 }
 
 func (command *ScriptsFolderCommand) callObjectDefinitionCallback(object IDatabaseObject) error {
@@ -128,34 +467,93 @@ func (command *ScriptsFolderCommand) callObjectDefinitionCallback(object IDataba
 		return nil
 	}
 
-	return command.definitionCallback(object.Catalog(), object.Schema(), object.Name(), object.Type(),
-		object.Definition())
+	return command.definitionCallback(command.objectDefinition(object))
+}
+
+// objectDefinition возвращает скрипт объекта БД для передачи в callback
+func (command *ScriptsFolderCommand) objectDefinition(object IDatabaseObject) output.ObjectDefinition {
+	definition := output.ObjectDefinition{
+		Catalog:    object.Catalog(),
+		Schema:     object.Schema(),
+		Name:       object.Name(),
+		Type:       object.Type(),
+		Owner:      object.Owner(),
+		Parent:     object.Parent(),
+		Server:     command.serverName(),
+		Definition: object.Definition(),
+	}
+
+	if command.objectDependencies && command.dependencyGraph != nil {
+		node := objectNode(object)
+
+		for _, dependency := range command.dependencyGraph.Dependencies([]graph.Node{node}, 1) {
+			if dependency != node {
+				definition.Dependencies = append(definition.Dependencies, output.ObjectReference{
+					Type:   dependency.Type,
+					Schema: dependency.Schema,
+					Name:   dependency.Name,
+				})
+			}
+		}
+	}
+
+	return definition
+}
+
+func (command *ScriptsFolderCommand) serverName() string {
+	if command.database == nil {
+		return ""
+	}
+
+	return command.database.Server
 }
 
 func (command *ScriptsFolderCommand) writeDefinition(ctx context.Context, object interface{}) (interface{}, error) {
 	obj := object.(IDatabaseObject)
 
+	for _, issue := range command.targetVersionIssues(obj) {
+		command.warn(fmt.Sprintf("%s: %s", obj.SchemaAndName(true), issue),
+			objectFields(obj, commands.PhaseRendering, 0))
+	}
+
+	body := string(obj.Definition())
+
+	var (
+		result interface{}
+		err    error
+	)
+
 	switch obj.Type() {
+	case output.Database:
+		result, err = command.writeDatabaseDefinition(ctx, obj)
 	case output.Schema:
-		return command.writeSchemaDefinition(ctx, obj)
+		result, err = command.writeSchemaDefinition(ctx, obj)
 	case output.Procedure:
-		return command.writeProcedureDefinition(ctx, obj)
+		result, err = command.writeProcedureDefinition(ctx, obj)
 	case output.Function:
-		return command.writeFunctionDefinition(ctx, obj)
+		result, err = command.writeFunctionDefinition(ctx, obj)
 	case output.View:
-		return command.writeViewDefinition(ctx, obj)
+		result, err = command.writeViewDefinition(ctx, obj)
 	case output.Trigger:
-		return command.writeTriggerDefinition(ctx, obj)
+		result, err = command.writeTriggerDefinition(ctx, obj)
 	case output.UserDefinedTableType, output.UserDefinedDataType:
-		return command.writeDomainDefinition(ctx, obj)
+		result, err = command.writeDomainDefinition(ctx, obj)
 	case output.Table:
-		return command.writeTableDefinition(ctx, obj)
+		result, err = command.writeTableDefinition(ctx, obj)
+	default:
+		return object, errorUnsupported
 	}
 
-	return object, nil
+	if err != nil {
+		return result, err
+	}
+
+	return command.applyTemplate(obj, body)
 }
 
 func (command *ScriptsFolderCommand) ReadMetadata(ctx context.Context) error {
+	command.metadataProgress(0)
+
 	collation, err := command.metaReader.DatabaseCollation(ctx)
 
 	if err != nil {
@@ -163,6 +561,16 @@ func (command *ScriptsFolderCommand) ReadMetadata(ctx context.Context) error {
 	}
 
 	command.databaseCollation = collation
+	command.metadataProgress(1)
+
+	database, err := command.metaReader.Database(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	command.database = database
+	command.metadataProgress(2)
 
 	permissions, err := command.metaReader.Permissions(ctx)
 
@@ -171,6 +579,7 @@ func (command *ScriptsFolderCommand) ReadMetadata(ctx context.Context) error {
 	}
 
 	command.permissions = permissions
+	command.metadataProgress(3)
 
 	userTypes, err := command.metaReader.UserDefinedTypes(ctx)
 
@@ -179,6 +588,7 @@ func (command *ScriptsFolderCommand) ReadMetadata(ctx context.Context) error {
 	}
 
 	command.userDefinedTypes = userTypes
+	command.metadataProgress(4)
 
 	columns, err := command.metaReader.ObjectColumns(ctx)
 
@@ -187,6 +597,7 @@ func (command *ScriptsFolderCommand) ReadMetadata(ctx context.Context) error {
 	}
 
 	command.columns = columns
+	command.metadataProgress(5)
 
 	indexes, err := command.metaReader.Indexes(ctx)
 
@@ -194,7 +605,9 @@ func (command *ScriptsFolderCommand) ReadMetadata(ctx context.Context) error {
 		return err
 	}
 
+	command.adaptIndexes(indexes)
 	command.indexes = indexes
+	command.metadataProgress(6)
 
 	foreignKeys, err := command.metaReader.ForeignKeys(ctx)
 
@@ -203,6 +616,7 @@ func (command *ScriptsFolderCommand) ReadMetadata(ctx context.Context) error {
 	}
 
 	command.foreignKeys = foreignKeys
+	command.metadataProgress(7)
 
 	tables, err := command.metaReader.Tables(ctx)
 
@@ -211,10 +625,37 @@ func (command *ScriptsFolderCommand) ReadMetadata(ctx context.Context) error {
 	}
 
 	command.tables = tables
+	command.metadataProgress(8)
+
+	if !command.needsDependencies() {
+		return nil
+	}
+
+	dependencies, err := command.metaReader.Dependencies(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	command.dependencies = dependencies
+	command.metadataProgress(9)
 
 	return nil
 }
 
+// metadataSteps количество запросов чтения метаданных без запроса зависимостей объектов БД
+const metadataSteps = 8
+
+func (command *ScriptsFolderCommand) metadataProgress(done int) {
+	total := metadataSteps
+
+	if command.needsDependencies() {
+		total++
+	}
+
+	command.progress(commands.ProgressEvent{Phase: commands.PhaseMetadata, Done: done, Total: total})
+}
+
 // SetIncludedObjects устанавливает фильтр, позволяющий выбирать только те объекты БД, которые должны быть
 // обработаны
 func (command *ScriptsFolderCommand) SetIncludedObjects(filter filter.IFilter) {
@@ -232,6 +673,36 @@ func (command *ScriptsFolderCommand) SetObjectDefinitionCallback(callback comman
 	command.definitionCallback = callback
 }
 
+// SetSkippedObjectCallback устанавливает callback для объектов БД, скрипты которых не созданы
+func (command *ScriptsFolderCommand) SetSkippedObjectCallback(callback commands.SkippedObjectCallback) {
+	command.skippedCallback = callback
+}
+
+// IncludeDependencies добавлять к выбранным объектам БД объекты, от которых они зависят
+func (command *ScriptsFolderCommand) IncludeDependencies(on bool) {
+	command.withDependencies = on
+}
+
+// ObjectDependencies передавать в скриптах объектов БД объекты, от которых они непосредственно зависят
+func (command *ScriptsFolderCommand) ObjectDependencies(on bool) {
+	command.objectDependencies = on
+}
+
+// IncludeDependents добавлять к выбранным объектам БД объекты, которые зависят от них
+func (command *ScriptsFolderCommand) IncludeDependents(on bool) {
+	command.withDependents = on
+}
+
+// SetModifiedSince создавать скрипты только объектов БД, измененных начиная с времени since по часам сервера
+func (command *ScriptsFolderCommand) SetModifiedSince(since time.Time) {
+	command.modifiedSince = since
+}
+
+// ServerTime возвращает время сервера БД на момент начала выполнения команды
+func (command *ScriptsFolderCommand) ServerTime() time.Time {
+	return command.serverTime
+}
+
 // StaticData опция выгрузки скриптов вставки данных
 func (command *ScriptsFolderCommand) StaticData(on bool) {
 	command.includeStaticData = on
@@ -247,6 +718,27 @@ func (command *ScriptsFolderCommand) SkipPermissions(on bool) {
 	command.skipPermissions = on
 }
 
+// SetProgressCallback устанавливает callback для отслеживания хода выполнения команды
+func (command *ScriptsFolderCommand) SetProgressCallback(callback commands.ProgressCallback) {
+	command.progressCallback = callback
+}
+
+func (command *ScriptsFolderCommand) progress(event commands.ProgressEvent) {
+	if command.progressCallback == nil {
+		return
+	}
+
+	command.progressMutex.Lock()
+	defer command.progressMutex.Unlock()
+
+	command.progressCallback(event)
+}
+
+// FailFast прерывать выполнение команды на первой ошибке создания или сохранения скрипта
+func (command *ScriptsFolderCommand) FailFast(on bool) {
+	command.failFast = on
+}
+
 // SetDatabaseObjectTypes устанавливает список типов объектов БД, которые необходимо выгрузить в скрипты
 func (command *ScriptsFolderCommand) SetDatabaseObjectTypes(types []output.DatabaseObjectType) {
 	t := make(map[output.DatabaseObjectType]bool)
@@ -258,22 +750,111 @@ func (command *ScriptsFolderCommand) SetDatabaseObjectTypes(types []output.Datab
 	command.types = t
 }
 
+// SetTargetVersion устанавливает целевую версию SQL Server, для которой создаются скрипты
+func (command *ScriptsFolderCommand) SetTargetVersion(version string) {
+	command.targetVersion = version
+}
+
+// SetScriptStyle устанавливает стиль скриптов создания объектов БД
+func (command *ScriptsFolderCommand) SetScriptStyle(style commands.ScriptStyle) {
+	command.scriptStyle = style
+}
+
+// SetTemplates устанавливает пользовательские шаблоны скриптов объектов БД
+func (command *ScriptsFolderCommand) SetTemplates(templates output.Templates) {
+	command.templates = templates
+}
+
+// SetParallel устанавливает количество объектов БД, скрипты которых создаются параллельно
+func (command *ScriptsFolderCommand) SetParallel(parallel int) {
+	if parallel < 1 {
+		parallel = 1
+	}
+
+	command.parallel = parallel
+}
+
+// errorEncrypted объект БД пропущен, так как его определение зашифровано
+var errorEncrypted = errors.New("definition is encrypted")
+
+// errorUnsupported объект БД пропущен, так как создание скриптов объектов этого типа не поддерживается
+var errorUnsupported = errors.New("object type is not supported")
+
+// Skipped возвращает объекты БД, скрипты которых не созданы, с причинами
+func (command *ScriptsFolderCommand) Skipped() []commands.SkippedObject {
+	command.resultMutex.Lock()
+	defer command.resultMutex.Unlock()
+
+	return command.skipped
+}
+
+// DatabaseName возвращает наименование БД
+func (command *ScriptsFolderCommand) DatabaseName() string {
+	if command.database == nil {
+		return ""
+	}
+
+	return command.database.Name
+}
+
+func (command *ScriptsFolderCommand) skip(object IDatabaseObject, phase commands.Phase, reason commands.SkipReason) {
+	command.engine.LogFields(log.DebugLevel, objectFields(object, phase, 0), object.SchemaAndName(true),
+		" skipped: ", reason)
+
+	if command.skippedCallback != nil {
+		definition := command.objectDefinition(object)
+		definition.Definition = nil
+
+		command.skippedCallback(definition, reason)
+	}
+
+	command.resultMutex.Lock()
+	defer command.resultMutex.Unlock()
+
+	command.skipped = append(command.skipped, commands.SkippedObject{
+		Object: object.SchemaAndName(true),
+		Type:   object.Type(),
+		Reason: reason,
+	})
+}
+
+// Warnings возвращает предупреждения, сформированные при выполнении команды
+func (command *ScriptsFolderCommand) Warnings() []string {
+	command.resultMutex.Lock()
+	defer command.resultMutex.Unlock()
+
+	return command.warnings
+}
+
+func (command *ScriptsFolderCommand) warn(message string, fields log.Fields) {
+	command.engine.LogFields(log.WarningLevel, fields, message)
+
+	command.resultMutex.Lock()
+	defer command.resultMutex.Unlock()
+
+	command.warnings = append(command.warnings, message)
+}
+
 // Included проверяет, должен ли объект object быть включен в обработку
-func (command *ScriptsFolderCommand) Included(object string) error {
+func (command *ScriptsFolderCommand) Included(object IDatabaseObject) error {
 	if command.include == nil {
 		return nil
 	}
 
-	return command.include.Match(object)
+	return command.include.Match(filterObject(object))
 }
 
 // Excluded проверяет, должен ли объект object быть исключен из обработки
-func (command *ScriptsFolderCommand) Excluded(object string) error {
+func (command *ScriptsFolderCommand) Excluded(object IDatabaseObject) error {
 	if command.exclude == nil {
 		return filter.ErrorNotMatched
 	}
 
-	return command.exclude.Match(object)
+	return command.exclude.Match(filterObject(object))
+}
+
+func filterObject(object IDatabaseObject) filter.Object {
+	return filter.Object{Type: object.Type(), Schema: object.Schema(), Name: object.Name()}
 }
 
 // ObjectTypeIncluded проверяет, должен ли тип объекта БД включен в обработку
@@ -293,26 +874,53 @@ func (command *ScriptsFolderCommand) DatabaseCollation() string {
 }
 
 func (command *ScriptsFolderCommand) databaseObjects(ctx context.Context) (chan rxgo.Item, error) {
-	stmt, err := command.engine.db.PrepareContext(ctx, selectObjects)
+	// таймаут ограничивает время выполнения запроса до получения первых строк результата, а не время чтения
+	// строк, которое зависит от скорости создания скриптов
+	queryCtx, cancel := context.WithCancel(ctx)
 
-	if err != nil {
-		return nil, err
+	// отмена запроса по таймауту должна отличаться от прерывания команды
+	var timedOut int32
+
+	if command.engine.queryTimeout > 0 {
+		timer := time.AfterFunc(command.engine.queryTimeout, func() {
+			atomic.StoreInt32(&timedOut, 1)
+			cancel()
+		})
+
+		defer timer.Stop()
 	}
 
-	defer stmt.Close()
+	queryError := func(err error) error {
+		if atomic.LoadInt32(&timedOut) == 1 && ctx.Err() == nil {
+			return fmt.Errorf("query timeout %s exceeded: %w", command.engine.queryTimeout, context.DeadlineExceeded)
+		}
+
+		return err
+	}
 
-	rows, err := stmt.QueryContext(ctx)
+	stmt, err := command.engine.db.PrepareContext(queryCtx, selectObjects)
 
 	if err != nil {
-		return nil, err
+		cancel()
+		return nil, queryError(err)
+	}
+
+	rows, err := stmt.QueryContext(queryCtx)
+
+	if err != nil {
+		stmt.Close()
+		cancel()
+
+		return nil, queryError(err)
 	}
 
 	out := make(chan rxgo.Item)
 
 	go func() {
 		defer close(out)
-		defer rows.Close()
+		defer cancel()
 		defer stmt.Close()
+		defer rows.Close()
 
 		var (
 			catalog              sql.NullString
@@ -324,13 +932,15 @@ func (command *ScriptsFolderCommand) databaseObjects(ctx context.Context) (chan
 			usesANSINulls        sql.NullBool
 			usesQuotedIdentifier sql.NullBool
 			description          sql.NullString
+			parent               sql.NullString
+			modifyDate           sql.NullTime
 		)
 
 		var objType string
 
 		for rows.Next() {
 			err = rows.Scan(&catalog, &schema, &name, &objectType, &definition, &owner, &usesANSINulls,
-				&usesQuotedIdentifier, &description)
+				&usesQuotedIdentifier, &description, &parent, &modifyDate)
 
 			if err == nil {
 				if objectType.Valid {
@@ -350,6 +960,8 @@ func (command *ScriptsFolderCommand) databaseObjects(ctx context.Context) (chan
 							definition:  definition,
 							owner:       owner,
 							description: description,
+							parent:      parent,
+							modifyDate:  modifyDate,
 						},
 						usesANSINulls:        usesANSINulls,
 						usesQuotedIdentifier: usesQuotedIdentifier,
@@ -363,14 +975,24 @@ func (command *ScriptsFolderCommand) databaseObjects(ctx context.Context) (chan
 						definition:  definition,
 						owner:       owner,
 						description: description,
+						parent:      parent,
+						modifyDate:  modifyDate,
 					}
 				}
 
-				out <- rxgo.Of(object)
+				if !rxgo.Of(object).SendContext(ctx, out) {
+					return
+				}
 			} else {
-				out <- rxgo.Error(err)
+				if !rxgo.Error(err).SendContext(ctx, out) {
+					return
+				}
 			}
 		}
+
+		if err = rows.Err(); err != nil {
+			rxgo.Error(err).SendContext(ctx, out)
+		}
 	}()
 
 	return out, nil
@@ -402,8 +1024,23 @@ objectDescriptions (object_id, description, class) as (
     where (props.class = 6)
 )
 select info.catalog, info.[schema], info.name, info.type, info.definition,
-       info.owner, info.uses_quoted_identifier, info.uses_ansi_nulls, info.description
+       info.owner, info.uses_quoted_identifier, info.uses_ansi_nulls, info.description, info.parent,
+       info.modify_date
 from (
+    select
+        [order] = 0,
+        [catalog] = db_name(),
+        [schema] = null,
+        [name] = db_name(),
+        [type] = N'DATABASE',
+        [definition] = null,
+        [owner] = null,
+        [uses_ansi_nulls] = null,
+        [uses_quoted_identifier] = null,
+        [description] = null,
+        [parent] = null,
+        [modify_date] = null
+    union
     select
         [order] = 1,
         [catalog] = db_name(),
@@ -414,7 +1051,9 @@ from (
         [owner] = users.name,
         [uses_ansi_nulls] = null,
         [uses_quoted_identifier] = null,
-        [description] = prop.description
+        [description] = prop.description,
+        [parent] = null,
+        [modify_date] = null
     from sys.schemas as schemas
         inner join sys.sysusers as users on (schemas.principal_id = users.uid) and (users.hasdbaccess != 0)
         left join objectDescriptions as prop on (schemas.schema_id = prop.object_id) and (prop.class = 3)
@@ -429,7 +1068,9 @@ from (
         [owner] = null,
         [uses_ansi_nulls] = null,
         [uses_quoted_identifier] = null,
-        [description] = prop.description
+        [description] = prop.description,
+        [parent] = null,
+        [modify_date] = null
     from sys.types as types
         left join objectDescriptions as prop on (types.user_type_id = prop.object_id) and (prop.class = 6)
     where (types.is_user_defined != cast(0 as bit)) and (types.is_table_type = cast(0 as bit))
@@ -464,12 +1105,15 @@ from (
         end,
 
         [definition] = object_definition(objects.object_id),
-        [owner] = null,
+        [owner] = user_name(isnull(objects.principal_id, object_schemas.principal_id)),
         [uses_ansi_nulls] = modules.uses_ansi_nulls,
         [uses_quoted_identifier] = modules.uses_quoted_identifier,
-        [description] = iif(objects.type = 'TT', prop_types.description, prop_objects.description)
+        [description] = iif(objects.type = 'TT', prop_types.description, prop_objects.description),
+        [parent] = object_name(nullif(objects.parent_object_id, 0)),
+        [modify_date] = objects.modify_date
 
     from sys.objects as objects
+        inner join sys.schemas as object_schemas on (objects.schema_id = object_schemas.schema_id)
         left join sys.sql_modules as modules on (objects.object_id = modules.object_id)
         left join tableTypes on (objects.object_id = tableTypes.object_id)
         left join objectDescriptions as prop_objects on (objects.object_id = prop_objects.object_id)
@@ -480,3 +1124,20 @@ from (
 ) as info
 order by info.catalog, info.[order], info.type, info.[schema], info.name
 `
+
+// objectFields возвращает поля записи лога об объекте object на этапе phase. Нулевая длительность duration в поля не
+// включается
+func objectFields(object IDatabaseObject, phase commands.Phase, duration time.Duration) log.Fields {
+	fields := log.Fields{
+		log.FieldObjectType: object.Type().String(),
+		log.FieldSchema:     object.Schema(),
+		log.FieldName:       object.Name(),
+		log.FieldPhase:      phase.String(),
+	}
+
+	if duration > 0 {
+		fields[log.FieldDuration] = duration.Seconds()
+	}
+
+	return fields
+}
@@ -0,0 +1,439 @@
+package sqlserver
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/reactivex/rxgo/v2"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/log"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+func testModules(count int) []IDatabaseObject {
+	objects := make([]IDatabaseObject, count)
+
+	for i := range objects {
+		name := fmt.Sprintf("GetOrders%05d", i)
+
+		object := &module{
+			databaseObject:       *testDatabaseObject("dbo", name, "PROCEDURE"),
+			usesANSINulls:        sql.NullBool{Bool: true, Valid: true},
+			usesQuotedIdentifier: sql.NullBool{Bool: true, Valid: true},
+		}
+
+		object.SetDefinition([]byte(fmt.Sprintf("CREATE PROCEDURE [dbo].[%s] AS SELECT 1 AS [one]", name)))
+
+		objects[i] = object
+	}
+
+	return objects
+}
+
+func testObjectsChannel(objects []IDatabaseObject) chan rxgo.Item {
+	items := make(chan rxgo.Item, len(objects))
+
+	for _, object := range objects {
+		items <- rxgo.Of(object)
+	}
+
+	close(items)
+
+	return items
+}
+
+func parallelCommand(parallel int, callback func(object output.ObjectDefinition) error) *ScriptsFolderCommand {
+	command := goldenCommand()
+
+	command.types = map[output.DatabaseObjectType]bool{output.Procedure: true}
+	command.definitionCallback = callback
+	command.SetParallel(parallel)
+
+	return command
+}
+
+func TestScriptsFolderCommand_process(t *testing.T) {
+	const count = 500
+
+	for _, parallel := range []int{1, 8} {
+		names := make([]string, 0, count)
+
+		command := parallelCommand(parallel, func(object output.ObjectDefinition) error {
+			names = append(names, object.Name)
+			return nil
+		})
+
+		if err := command.process(context.Background(), testObjectsChannel(testModules(count)), count); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(names) != count {
+			t.Fatalf("parallel %d: have %d objects, want %d", parallel, len(names), count)
+		}
+
+		for i, name := range names {
+			if want := fmt.Sprintf("GetOrders%05d", i); name != want {
+				t.Fatalf("parallel %d: object %d: have %s, want %s", parallel, i, name, want)
+			}
+		}
+	}
+}
+
+func TestScriptsFolderCommand_processErrors(t *testing.T) {
+	var cases = []struct {
+		parallel int
+		failFast bool
+		want     []string
+	}{
+		{
+			parallel: 1,
+			want:     []string{"[dbo].[GetOrders00003]", "[dbo].[GetOrders00010]", "[dbo].[GetOrders00042]"},
+		},
+		{
+			parallel: 8,
+			want:     []string{"[dbo].[GetOrders00003]", "[dbo].[GetOrders00010]", "[dbo].[GetOrders00042]"},
+		},
+		{
+			parallel: 1,
+			failFast: true,
+			want:     []string{"[dbo].[GetOrders00003]"},
+		},
+		{
+			parallel: 8,
+			failFast: true,
+			want:     []string{"[dbo].[GetOrders00003]"},
+		},
+	}
+
+	failed := map[string]bool{"GetOrders00003": true, "GetOrders00010": true, "GetOrders00042": true}
+
+	for _, test := range cases {
+		command := parallelCommand(test.parallel, func(object output.ObjectDefinition) error {
+			if failed[object.Name] {
+				return errors.New("write failed")
+			}
+
+			return nil
+		})
+
+		command.FailFast(test.failFast)
+
+		err := command.process(context.Background(), testObjectsChannel(testModules(100)), 100)
+
+		var errs commands.MultiError
+
+		if !errors.As(err, &errs) {
+			t.Fatalf("parallel %d, fail fast %v: have error %v, want MultiError", test.parallel, test.failFast, err)
+		}
+
+		if !reflect.DeepEqual(errs.Objects(), test.want) {
+			t.Errorf("parallel %d, fail fast %v: have %v, want %v", test.parallel, test.failFast, errs.Objects(),
+				test.want)
+		}
+	}
+}
+
+func TestScriptsFolderCommand_processCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	count := 0
+
+	command := parallelCommand(1, func(object output.ObjectDefinition) error {
+		if count++; count == 10 {
+			cancel()
+		}
+
+		return nil
+	})
+
+	err := command.process(ctx, testObjectsChannel(testModules(100)), 100)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("have error %v, want %v", err, context.Canceled)
+	}
+
+	if count == 100 {
+		t.Error("processing is not canceled")
+	}
+}
+
+func TestScriptsFolderCommand_processProgress(t *testing.T) {
+	const count = 50
+
+	for _, parallel := range []int{1, 8} {
+		events := make(map[commands.Phase][]commands.ProgressEvent)
+
+		command := parallelCommand(parallel, nil)
+		command.SetProgressCallback(func(event commands.ProgressEvent) {
+			events[event.Phase] = append(events[event.Phase], event)
+		})
+
+		if err := command.process(context.Background(), testObjectsChannel(testModules(count)), count); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, phase := range []commands.Phase{commands.PhaseRendering, commands.PhaseWriting} {
+			if len(events[phase]) != count {
+				t.Fatalf("parallel %d, %s: have %d events, want %d", parallel, phase, len(events[phase]), count)
+			}
+
+			done := make(map[int]bool)
+
+			for _, event := range events[phase] {
+				if event.Total != count {
+					t.Errorf("parallel %d, %s: have total %d, want %d", parallel, phase, event.Total, count)
+				}
+
+				done[event.Done] = true
+			}
+
+			if len(done) != count || !done[count] {
+				t.Errorf("parallel %d, %s: progress counters are not sequential", parallel, phase)
+			}
+		}
+	}
+}
+
+func TestScriptsFolderCommand_skipped(t *testing.T) {
+	objects := testModules(4)
+	objects[1].SetDefinition(nil)
+
+	objects = append(objects, testDatabaseObject("dbo", "Orders", "BASE TABLE"),
+		testDatabaseObject("dbo", "OrderNumbers", "SEQUENCE"))
+
+	for _, parallel := range []int{1, 4} {
+		written := 0
+
+		command := parallelCommand(parallel, func(object output.ObjectDefinition) error {
+			written++
+			return nil
+		})
+
+		var (
+			mutex   sync.Mutex
+			reasons = make(map[string]commands.SkipReason)
+		)
+
+		command.SetSkippedObjectCallback(func(object output.ObjectDefinition, reason commands.SkipReason) {
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			reasons[object.QualifiedName()] = reason
+		})
+
+		items, total := command.enumerate(context.Background(), testObjectsChannel(objects))
+
+		if err := command.process(context.Background(), items, total); err != nil {
+			t.Fatal(err)
+		}
+
+		if written != 3 {
+			t.Errorf("parallel %d: have %d scripts, want 3", parallel, written)
+		}
+
+		want := []commands.SkippedObject{
+			{Object: "[dbo].[Orders]", Type: output.Table, Reason: commands.SkipFiltered},
+			{Object: "[dbo].[OrderNumbers]", Type: output.UnknownObject, Reason: commands.SkipUnsupported},
+			{Object: "[dbo].[GetOrders00001]", Type: output.Procedure, Reason: commands.SkipEncrypted},
+		}
+
+		if have := command.Skipped(); !reflect.DeepEqual(have, want) {
+			t.Errorf("parallel %d: have skipped %v, want %v", parallel, have, want)
+		}
+
+		for _, skipped := range want {
+			if reason, ok := reasons[skipped.Object]; !ok || reason != skipped.Reason {
+				t.Errorf("parallel %d: skipped object callback failed for %s", parallel, skipped.Object)
+			}
+		}
+	}
+}
+
+func TestScriptsFolderCommand_logFields(t *testing.T) {
+	var buf bytes.Buffer
+
+	command := parallelCommand(4, func(object output.ObjectDefinition) error {
+		if object.Name == "GetOrders00002" {
+			return errors.New("write failed")
+		}
+
+		return nil
+	})
+
+	command.engine.SetLogger(log.New(log.WithLevel(log.DebugLevel), log.WithFormat(log.JSON), log.WithOutput(&buf)))
+
+	objects := append(testModules(3), testDatabaseObject("dbo", "Orders", "BASE TABLE"))
+	items, total := command.enumerate(context.Background(), testObjectsChannel(objects))
+
+	_ = command.process(context.Background(), items, total)
+
+	entries := make(map[string]map[string]interface{})
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var entry map[string]interface{}
+
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("%v: %s", err, line)
+		}
+
+		entries[fmt.Sprint(entry[log.FieldName])] = entry
+	}
+
+	var cases = []struct {
+		name  string
+		level string
+		typ   string
+		phase string
+	}{
+		{name: "GetOrders00000", level: "debug", typ: "procedure", phase: "writing"},
+		{name: "GetOrders00001", level: "debug", typ: "procedure", phase: "writing"},
+		{name: "GetOrders00002", level: "error", typ: "procedure", phase: "writing"},
+		{name: "Orders", level: "debug", typ: "table", phase: "object enumeration"},
+	}
+
+	for _, test := range cases {
+		entry, ok := entries[test.name]
+
+		if !ok {
+			t.Errorf("%s: the entry is not written:\n%s", test.name, buf.String())
+			continue
+		}
+
+		if entry["level"] != test.level || entry[log.FieldObjectType] != test.typ ||
+			entry[log.FieldSchema] != "dbo" || entry[log.FieldPhase] != test.phase {
+			t.Errorf("%s: unexpected entry %v", test.name, entry)
+		}
+
+		if _, ok := entry[log.FieldDuration]; !ok && test.phase == "writing" {
+			t.Errorf("%s: the entry has no duration: %v", test.name, entry)
+		}
+	}
+}
+
+func BenchmarkScriptsFolderCommand_process(b *testing.B) {
+	for _, parallel := range []int{1, 8} {
+		b.Run(fmt.Sprintf("parallel=%d", parallel), func(b *testing.B) {
+			command := parallelCommand(parallel, nil)
+
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				items := testObjectsChannel(testModules(1000))
+				b.StartTimer()
+
+				if err := command.process(context.Background(), items, 1000); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestScriptsFolderCommand_modifiedSince(t *testing.T) {
+	since := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	objects := testModules(3)
+	objects[0].(*module).modifyDate = sql.NullTime{Time: since.Add(-time.Hour), Valid: true}
+	objects[1].(*module).modifyDate = sql.NullTime{Time: since, Valid: true}
+	objects[2].(*module).modifyDate = sql.NullTime{Time: since.Add(time.Hour), Valid: true}
+
+	objects = append(objects, testDatabaseObject("dbo", "", "SCHEMA"))
+
+	names := make([]string, 0)
+
+	command := parallelCommand(1, func(object output.ObjectDefinition) error {
+		names = append(names, object.Schema+"."+object.Name)
+		return nil
+	})
+
+	command.types[output.Schema] = true
+	command.SetModifiedSince(since)
+
+	items, total := command.enumerate(context.Background(), testObjectsChannel(objects))
+
+	if err := command.process(context.Background(), items, total); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"dbo.GetOrders00001", "dbo.GetOrders00002", "dbo."}; !reflect.DeepEqual(names, want) {
+		t.Errorf("have scripts %v, want %v", names, want)
+	}
+
+	want := []commands.SkippedObject{
+		{Object: "[dbo].[GetOrders00000]", Type: output.Procedure, Reason: commands.SkipUnchanged},
+	}
+
+	if have := command.Skipped(); !reflect.DeepEqual(have, want) {
+		t.Errorf("have skipped %v, want %v", have, want)
+	}
+}
+
+// blockingDriver драйвер БД, подготовка запросов которого завершается только при отмене контекста
+type blockingDriver struct{}
+
+func (blockingDriver) Open(name string) (driver.Conn, error) {
+	return blockingConn{}, nil
+}
+
+type blockingConn struct{}
+
+func (blockingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (blockingConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (blockingConn) Close() error {
+	return nil
+}
+
+func (blockingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("not implemented")
+}
+
+func init() {
+	sql.Register("blocking", blockingDriver{})
+}
+
+func TestScriptsFolderCommand_databaseObjectsTimeout(t *testing.T) {
+	db, err := sql.Open("blocking", "")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer db.Close()
+
+	command := &ScriptsFolderCommand{engine: &Engine{db: db, queryTimeout: 10 * time.Millisecond}}
+
+	_, err = command.databaseObjects(context.Background())
+
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("have error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	command.engine.queryTimeout = time.Minute
+
+	// прерывание команды не должно выдаваться за таймаут запроса
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	if _, err = command.databaseObjects(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("have error %v, want %v", err, context.Canceled)
+	}
+}
@@ -4,11 +4,123 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"strings"
 )
 
 // ErrorInvalidConnectionObject ошибка "Объект соединения равен nil"
 var ErrorInvalidConnectionObject = errors.New("connection object cannot be nil")
 
+const (
+	// ServerVersion2016 основная версия SQL Server 2016
+	ServerVersion2016 = 13
+	// ServerVersion2017 основная версия SQL Server 2017
+	ServerVersion2017 = 14
+	// ServerVersion2019 основная версия SQL Server 2019
+	ServerVersion2019 = 15
+	// ServerVersion2022 основная версия SQL Server 2022
+	ServerVersion2022 = 16
+
+	// ServerVersionLatest последняя известная версия SQL Server. Облачные редакции (Azure SQL Database,
+	// Azure SQL Managed Instance) сообщают версию 12, но по возможностям соответствуют последней версии
+	ServerVersionLatest = ServerVersion2022
+)
+
+var serverVersionNames = map[int]string{
+	ServerVersion2016: "2016",
+	ServerVersion2017: "2017",
+	ServerVersion2019: "2019",
+	ServerVersion2022: "2022",
+}
+
+// ParseServerVersion возвращает основную версию SQL Server по ее наименованию (2016, 2017, 2019, 2022) или номеру
+// (13, 14, 15, 16)
+func ParseServerVersion(value string) (int, error) {
+	value = strings.Trim(value, " ")
+
+	for version, name := range serverVersionNames {
+		if value == name || value == fmt.Sprint(version) {
+			return version, nil
+		}
+	}
+
+	return 0, fmt.Errorf("unsupported SQL Server version %s", value)
+}
+
+// ServerVersionName возвращает наименование версии SQL Server
+func ServerVersionName(version int) string {
+	if name, ok := serverVersionNames[version]; ok {
+		return "SQL Server " + name
+	}
+
+	return fmt.Sprintf("SQL Server (version %d)", version)
+}
+
+// EngineEdition редакция ядра СУБД (SERVERPROPERTY('EngineEdition'))
+type EngineEdition int
+
+const (
+	// EngineEditionUnknown редакция не определена
+	EngineEditionUnknown EngineEdition = 0
+	// EngineEditionPersonal Personal/Desktop Engine
+	EngineEditionPersonal EngineEdition = 1
+	// EngineEditionStandard Standard, Web, Business Intelligence
+	EngineEditionStandard EngineEdition = 2
+	// EngineEditionEnterprise Enterprise, Developer, Evaluation
+	EngineEditionEnterprise EngineEdition = 3
+	// EngineEditionExpress Express
+	EngineEditionExpress EngineEdition = 4
+	// EngineEditionAzureSQLDatabase Azure SQL Database
+	EngineEditionAzureSQLDatabase EngineEdition = 5
+	// EngineEditionAzureSynapse Azure Synapse Analytics
+	EngineEditionAzureSynapse EngineEdition = 6
+	// EngineEditionAzureSQLManagedInstance Azure SQL Managed Instance
+	EngineEditionAzureSQLManagedInstance EngineEdition = 8
+	// EngineEditionAzureSQLEdge Azure SQL Edge
+	EngineEditionAzureSQLEdge EngineEdition = 9
+)
+
+// IsAzure проверяет, является ли редакция облачной редакцией Azure
+func (edition EngineEdition) IsAzure() bool {
+	switch edition {
+	case EngineEditionAzureSQLDatabase, EngineEditionAzureSynapse, EngineEditionAzureSQLManagedInstance,
+		EngineEditionAzureSQLEdge:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsAzureSQLDatabase проверяет, является ли редакция Azure SQL Database. В Azure SQL Database недоступны
+// файловые группы, файлы базы данных и FILESTREAM
+func (edition EngineEdition) IsAzureSQLDatabase() bool {
+	return edition == EngineEditionAzureSQLDatabase || edition == EngineEditionAzureSynapse
+}
+
+// String возвращает наименование редакции
+func (edition EngineEdition) String() string {
+	switch edition {
+	case EngineEditionPersonal:
+		return "Personal"
+	case EngineEditionStandard:
+		return "Standard"
+	case EngineEditionEnterprise:
+		return "Enterprise"
+	case EngineEditionExpress:
+		return "Express"
+	case EngineEditionAzureSQLDatabase:
+		return "Azure SQL Database"
+	case EngineEditionAzureSynapse:
+		return "Azure Synapse Analytics"
+	case EngineEditionAzureSQLManagedInstance:
+		return "Azure SQL Managed Instance"
+	case EngineEditionAzureSQLEdge:
+		return "Azure SQL Edge"
+	default:
+		return "Unknown"
+	}
+}
+
 // serverVersion возвращает версию SQL Server
 func serverVersion(db *sql.DB, ctx context.Context) (int, error) {
 	if db == nil {
@@ -34,6 +146,41 @@ func serverVersion(db *sql.DB, ctx context.Context) (int, error) {
 	return ver, nil
 }
 
+// serverEngineEdition возвращает редакцию ядра SQL Server
+func serverEngineEdition(db *sql.DB, ctx context.Context) (EngineEdition, error) {
+	if db == nil {
+		return EngineEditionUnknown, ErrorInvalidConnectionObject
+	}
+
+	stmt, err := db.PrepareContext(ctx, selectServerEngineEdition)
+
+	if err != nil {
+		return EngineEditionUnknown, err
+	}
+
+	defer stmt.Close()
+
+	var edition int
+
+	err = stmt.QueryRowContext(ctx).Scan(&edition)
+
+	if err != nil {
+		return EngineEditionUnknown, err
+	}
+
+	return EngineEdition(edition), nil
+}
+
+// effectiveServerVersion возвращает версию SQL Server, по которой выбираются варианты запросов метаданных.
+// Облачные редакции Azure сообщают версию 12, хотя поддерживают возможности последней версии SQL Server
+func effectiveServerVersion(serverVersion int, edition EngineEdition) int {
+	if edition.IsAzure() {
+		return ServerVersionLatest
+	}
+
+	return serverVersion
+}
+
 const selectServerVersion = `
 select isnull(
     try_cast(
@@ -44,3 +191,5 @@ select isnull(
     ),
 0) as version
 `
+
+const selectServerEngineEdition = `select isnull(cast(serverproperty('EngineEdition') as int), 0) as engine_edition`
@@ -0,0 +1,181 @@
+package sqlserver
+
+import (
+	"testing"
+)
+
+func TestEngineEdition_IsAzure(t *testing.T) {
+	var cases = []struct {
+		edition       EngineEdition
+		azure         bool
+		azureDatabase bool
+	}{
+		{
+			edition:       EngineEditionEnterprise,
+			azure:         false,
+			azureDatabase: false,
+		},
+		{
+			edition:       EngineEditionExpress,
+			azure:         false,
+			azureDatabase: false,
+		},
+		{
+			edition:       EngineEditionAzureSQLDatabase,
+			azure:         true,
+			azureDatabase: true,
+		},
+		{
+			edition:       EngineEditionAzureSQLManagedInstance,
+			azure:         true,
+			azureDatabase: false,
+		},
+		{
+			edition:       EngineEditionUnknown,
+			azure:         false,
+			azureDatabase: false,
+		},
+	}
+
+	for _, test := range cases {
+		if test.edition.IsAzure() != test.azure {
+			t.Errorf("%s.IsAzure() failed: want %v", test.edition, test.azure)
+		}
+
+		if test.edition.IsAzureSQLDatabase() != test.azureDatabase {
+			t.Errorf("%s.IsAzureSQLDatabase() failed: want %v", test.edition, test.azureDatabase)
+		}
+	}
+}
+
+func TestMetadataReader_selectTablesQuery(t *testing.T) {
+	var cases = []struct {
+		serverVersion int
+		engineEdition EngineEdition
+		want          string
+	}{
+		{
+			serverVersion: ServerVersion2016,
+			engineEdition: EngineEditionEnterprise,
+			want:          selectTables2016,
+		},
+		{
+			serverVersion: ServerVersion2017,
+			engineEdition: EngineEditionStandard,
+			want:          selectTables2017,
+		},
+		{
+			serverVersion: ServerVersion2019,
+			engineEdition: EngineEditionEnterprise,
+			want:          selectTables2019,
+		},
+		{
+			serverVersion: ServerVersion2022,
+			engineEdition: EngineEditionEnterprise,
+			want:          selectTables2019,
+		},
+		{
+			serverVersion: 12,
+			engineEdition: EngineEditionAzureSQLManagedInstance,
+			want:          selectTables2019,
+		},
+		{
+			serverVersion: 12,
+			engineEdition: EngineEditionAzureSQLDatabase,
+			want:          selectTablesAzure,
+		},
+		{
+			serverVersion: 12,
+			engineEdition: EngineEditionEnterprise,
+			want:          selectTables2016,
+		},
+	}
+
+	for _, test := range cases {
+		reader := &MetadataReader{serverVersion: test.serverVersion, engineEdition: test.engineEdition}
+
+		if reader.selectTablesQuery() != test.want {
+			t.Errorf("selectTablesQuery() failed for version %d (%s)", test.serverVersion, test.engineEdition)
+		}
+	}
+}
+
+func TestMetadataReader_selectIndexesQuery(t *testing.T) {
+	var cases = []struct {
+		serverVersion int
+		engineEdition EngineEdition
+		want          string
+	}{
+		{
+			serverVersion: ServerVersion2016,
+			engineEdition: EngineEditionEnterprise,
+			want:          selectIndexes2016,
+		},
+		{
+			serverVersion: ServerVersion2017,
+			engineEdition: EngineEditionEnterprise,
+			want:          selectIndexes2016,
+		},
+		{
+			serverVersion: ServerVersion2019,
+			engineEdition: EngineEditionEnterprise,
+			want:          selectIndexes2019,
+		},
+		{
+			serverVersion: 12,
+			engineEdition: EngineEditionAzureSQLDatabase,
+			want:          selectIndexes2019,
+		},
+	}
+
+	for _, test := range cases {
+		reader := &MetadataReader{serverVersion: test.serverVersion, engineEdition: test.engineEdition}
+
+		if reader.selectIndexesQuery() != test.want {
+			t.Errorf("selectIndexesQuery() failed for version %d (%s)", test.serverVersion, test.engineEdition)
+		}
+	}
+}
+
+func TestParseServerVersion(t *testing.T) {
+	var cases = []struct {
+		value     string
+		want      int
+		withError bool
+	}{
+		{
+			value:     "2016",
+			want:      ServerVersion2016,
+			withError: false,
+		},
+		{
+			value:     "2019",
+			want:      ServerVersion2019,
+			withError: false,
+		},
+		{
+			value:     "14",
+			want:      ServerVersion2017,
+			withError: false,
+		},
+		{
+			value:     "2014",
+			want:      0,
+			withError: true,
+		},
+		{
+			value:     "",
+			want:      0,
+			withError: true,
+		},
+	}
+
+	for _, test := range cases {
+		have, err := ParseServerVersion(test.value)
+		withError := err != nil
+
+		if have != test.want || withError != test.withError {
+			t.Errorf(`ParseServerVersion("%s") failed!`, test.value)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package sqlserver
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+// createOrAlterPattern выражение для поиска заголовка CREATE OR ALTER в определении SQL модуля
+var createOrAlterPattern = regexp.MustCompile(`(?is)^((?:\s|--[^\n]*\n|/\*.*?\*/)*)create\s+or\s+alter\b`)
+
+// createModulePattern выражение для поиска заголовка CREATE в определении SQL модуля
+var createModulePattern = regexp.MustCompile(
+	`(?is)^((?:\s|--[^\n]*\n|/\*.*?\*/)*)create(\s+(?:proc|procedure|function|view|trigger)\b)`)
+
+// styledStatement возвращает инструкцию создания объекта БД statement (без завершающего GO), оформленную в
+// соответствии со стилем скриптов, с завершающим GO
+func (command *ScriptsFolderCommand) styledStatement(object IDatabaseObject, statement string) string {
+	switch command.scriptStyle {
+	case commands.ScriptStyleIfNotExists:
+		return ifNotExistsStatement(object, statement)
+	case commands.ScriptStyleCreateOrAlter:
+		if isModule(object.Type()) {
+			// в целевой версии без CREATE OR ALTER SQL модули пересоздаются
+			if !command.createOrAlterSupported() {
+				return fmt.Sprintf("%s\nGO\n%s\nGO", dropStatement(object), statement)
+			}
+
+			if createOrAlterPattern.MatchString(statement) {
+				return statement + "\nGO"
+			}
+
+			if createModulePattern.MatchString(statement) {
+				return createModulePattern.ReplaceAllString(statement, "${1}CREATE OR ALTER${2}") + "\nGO"
+			}
+		}
+
+		return ifNotExistsStatement(object, statement)
+	case commands.ScriptStyleDropCreate:
+		drop := dropStatement(object)
+
+		if drop == "" {
+			return ifNotExistsStatement(object, statement)
+		}
+
+		return fmt.Sprintf("%s\nGO\n%s\nGO", drop, statement)
+	default:
+		return statement + "\nGO"
+	}
+}
+
+// isModule проверяет, является ли тип объекта БД SQL модулем
+func isModule(objectType output.DatabaseObjectType) bool {
+	switch objectType {
+	case output.Procedure, output.Function, output.View, output.Trigger:
+		return true
+	default:
+		return false
+	}
+}
+
+// ifNotExistsStatement возвращает инструкцию создания объекта БД, выполняемую только при отсутствии объекта в БД.
+// Инструкции, которые должны быть первыми в пакете (CREATE SCHEMA, CREATE VIEW etc), выполняются через EXEC
+func ifNotExistsStatement(object IDatabaseObject, statement string) string {
+	condition := notExistsCondition(object)
+
+	if condition == "" {
+		return statement + "\nGO"
+	}
+
+	switch object.Type() {
+	case output.Schema, output.Procedure, output.Function, output.View, output.Trigger:
+		return fmt.Sprintf("IF %s\n    EXEC(N'%s')\nGO", condition, quoted(statement))
+	default:
+		return fmt.Sprintf("IF %s\n%s\nGO", condition, statement)
+	}
+}
+
+// notExistsCondition возвращает условие отсутствия объекта БД
+func notExistsCondition(object IDatabaseObject) string {
+	name := quoted(object.SchemaAndName(true))
+
+	switch object.Type() {
+	case output.Database:
+		return fmt.Sprintf("DB_ID(N'%s') IS NULL", quoted(object.Name()))
+	case output.Schema:
+		return fmt.Sprintf("SCHEMA_ID(N'%s') IS NULL", quoted(object.Schema()))
+	case output.UserDefinedDataType, output.UserDefinedTableType:
+		return fmt.Sprintf("TYPE_ID(N'%s') IS NULL", name)
+	case output.Table:
+		return fmt.Sprintf("OBJECT_ID(N'%s', N'U') IS NULL", name)
+	case output.View:
+		return fmt.Sprintf("OBJECT_ID(N'%s', N'V') IS NULL", name)
+	case output.Procedure:
+		return fmt.Sprintf("OBJECT_ID(N'%s', N'P') IS NULL", name)
+	case output.Trigger:
+		return fmt.Sprintf("OBJECT_ID(N'%s', N'TR') IS NULL", name)
+	case output.Function:
+		return fmt.Sprintf("OBJECT_ID(N'%s') IS NULL", name)
+	default:
+		return ""
+	}
+}
+
+// dropStatement возвращает инструкцию удаления объекта БД. База данных не удаляется
+func dropStatement(object IDatabaseObject) string {
+	name := object.SchemaAndName(true)
+
+	switch object.Type() {
+	case output.UserDefinedDataType, output.UserDefinedTableType:
+		return fmt.Sprintf("DROP TYPE IF EXISTS %s", name)
+	case output.Table:
+		return fmt.Sprintf("DROP TABLE IF EXISTS %s", name)
+	case output.View:
+		return fmt.Sprintf("DROP VIEW IF EXISTS %s", name)
+	case output.Procedure:
+		return fmt.Sprintf("DROP PROCEDURE IF EXISTS %s", name)
+	case output.Function:
+		return fmt.Sprintf("DROP FUNCTION IF EXISTS %s", name)
+	case output.Trigger:
+		return fmt.Sprintf("DROP TRIGGER IF EXISTS %s", name)
+	default:
+		return ""
+	}
+}
+
+// quoted экранирует одинарные кавычки в строковом литерале T-SQL
+func quoted(value string) string {
+	return strings.ReplaceAll(value, "'", "''")
+}
+
+// descriptionStatement возвращает инструкцию установки описания (MS_Description) схемы schema или объекта схемы name
+// типа level1type (PROCEDURE, FUNCTION, VIEW). Для схемы level1type не указывается. В стилях повторно выполняемых
+// скриптов описание добавляется, если его нет, иначе - обновляется
+func (command *ScriptsFolderCommand) descriptionStatement(schema, level1type, name, description string) string {
+	args := fmt.Sprintf("@name = N'MS_Description', @value = N'%s', @level0type = N'SCHEMA', @level0name = N'%s'",
+		quoted(description), quoted(schema))
+	level1 := "NULL, NULL"
+
+	if level1type != "" {
+		args += fmt.Sprintf(", @level1type = N'%s', @level1name = N'%s'", level1type, quoted(name))
+		level1 = fmt.Sprintf("N'%s', N'%s'", level1type, quoted(name))
+	}
+
+	if command.scriptStyle == commands.ScriptStyleCreate {
+		return fmt.Sprintf("EXECUTE sp_addextendedproperty %s\nGO", args)
+	}
+
+	return fmt.Sprintf("IF NOT EXISTS (SELECT 1 FROM sys.fn_listextendedproperty(N'MS_Description', N'SCHEMA', "+
+		"N'%s', %s, NULL, NULL))\n    EXECUTE sp_addextendedproperty %s\nELSE\n    EXECUTE sp_updateextendedproperty %s\nGO",
+		quoted(schema), level1, args, args)
+}
@@ -0,0 +1,162 @@
+package sqlserver
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/vitpelekhaty/dbmill-cli/cmd/engine/commands"
+)
+
+func testDatabaseObject(schema, name, objectType string) *databaseObject {
+	return &databaseObject{
+		schema:     sql.NullString{String: schema, Valid: schema != ""},
+		name:       sql.NullString{String: name, Valid: name != ""},
+		objectType: sql.NullString{String: objectType, Valid: true},
+	}
+}
+
+func TestScriptsFolderCommand_styledStatement(t *testing.T) {
+	var cases = []struct {
+		object    IDatabaseObject
+		style     commands.ScriptStyle
+		statement string
+		want      string
+	}{
+		{
+			object:    testDatabaseObject("rpt", "", "SCHEMA"),
+			style:     commands.ScriptStyleCreate,
+			statement: "CREATE SCHEMA [rpt]",
+			want:      "CREATE SCHEMA [rpt]\nGO",
+		},
+		{
+			object:    testDatabaseObject("rpt", "", "SCHEMA"),
+			style:     commands.ScriptStyleIfNotExists,
+			statement: "CREATE SCHEMA [rpt] AUTHORIZATION [dbo]",
+			want:      "IF SCHEMA_ID(N'rpt') IS NULL\n    EXEC(N'CREATE SCHEMA [rpt] AUTHORIZATION [dbo]')\nGO",
+		},
+		{
+			object:    testDatabaseObject("rpt", "", "SCHEMA"),
+			style:     commands.ScriptStyleDropCreate,
+			statement: "CREATE SCHEMA [rpt]",
+			want:      "IF SCHEMA_ID(N'rpt') IS NULL\n    EXEC(N'CREATE SCHEMA [rpt]')\nGO",
+		},
+		{
+			object:    testDatabaseObject("dbo", "Phone", "DATA TYPE"),
+			style:     commands.ScriptStyleCreateOrAlter,
+			statement: "CREATE TYPE [dbo].[Phone] FROM [varchar](20)",
+			want:      "IF TYPE_ID(N'[dbo].[Phone]') IS NULL\nCREATE TYPE [dbo].[Phone] FROM [varchar](20)\nGO",
+		},
+		{
+			object:    testDatabaseObject("dbo", "Test", "PROCEDURE"),
+			style:     commands.ScriptStyleCreateOrAlter,
+			statement: "-- header\ncreate   procedure [dbo].[Test] as select 1",
+			want:      "-- header\nCREATE OR ALTER   procedure [dbo].[Test] as select 1\nGO",
+		},
+		{
+			object:    testDatabaseObject("dbo", "Test", "VIEW"),
+			style:     commands.ScriptStyleCreateOrAlter,
+			statement: "CREATE OR ALTER VIEW [dbo].[Test] AS SELECT 1 AS [one]",
+			want:      "CREATE OR ALTER VIEW [dbo].[Test] AS SELECT 1 AS [one]\nGO",
+		},
+		{
+			object:    testDatabaseObject("dbo", "Test", "VIEW"),
+			style:     commands.ScriptStyleIfNotExists,
+			statement: "CREATE VIEW [dbo].[Test] AS SELECT 'one' AS [one]",
+			want: "IF OBJECT_ID(N'[dbo].[Test]', N'V') IS NULL\n" +
+				"    EXEC(N'CREATE VIEW [dbo].[Test] AS SELECT ''one'' AS [one]')\nGO",
+		},
+		{
+			object:    testDatabaseObject("dbo", "Test", "FUNCTION"),
+			style:     commands.ScriptStyleDropCreate,
+			statement: "CREATE FUNCTION [dbo].[Test]() RETURNS INT AS BEGIN RETURN 1 END",
+			want: "DROP FUNCTION IF EXISTS [dbo].[Test]\nGO\n" +
+				"CREATE FUNCTION [dbo].[Test]() RETURNS INT AS BEGIN RETURN 1 END\nGO",
+		},
+		{
+			object:    testDatabaseObject("", "Test", "DATABASE"),
+			style:     commands.ScriptStyleDropCreate,
+			statement: "CREATE DATABASE [Test]",
+			want:      "IF DB_ID(N'Test') IS NULL\nCREATE DATABASE [Test]\nGO",
+		},
+	}
+
+	for _, test := range cases {
+		command := &ScriptsFolderCommand{scriptStyle: test.style}
+		have := command.styledStatement(test.object, test.statement)
+
+		if have != test.want {
+			t.Errorf("styledStatement(%s, %s) failed: have %q, want %q", test.object.SchemaAndName(true),
+				test.style, have, test.want)
+		}
+	}
+}
+
+func TestScriptsFolderCommand_styledStatement_targetVersion(t *testing.T) {
+	command := &ScriptsFolderCommand{
+		scriptStyle:         commands.ScriptStyleCreateOrAlter,
+		targetServerVersion: ServerVersion2016,
+	}
+
+	object := testDatabaseObject("dbo", "Test", "VIEW")
+	statement := command.adaptedModuleDefinition("CREATE OR ALTER VIEW [dbo].[Test] AS SELECT 1 AS [one]")
+
+	have := command.styledStatement(object, statement)
+	want := "DROP VIEW IF EXISTS [dbo].[Test]\nGO\nCREATE VIEW [dbo].[Test] AS SELECT 1 AS [one]\nGO"
+
+	if have != want {
+		t.Errorf("styledStatement failed: have %q, want %q", have, want)
+	}
+}
+
+func TestScriptsFolderCommand_descriptionStatement(t *testing.T) {
+	var cases = []struct {
+		style       commands.ScriptStyle
+		level1type  string
+		name        string
+		description string
+		want        string
+	}{
+		{
+			style:       commands.ScriptStyleCreate,
+			level1type:  "PROCEDURE",
+			name:        "Test",
+			description: "Test's procedure",
+			want: "EXECUTE sp_addextendedproperty @name = N'MS_Description', @value = N'Test''s procedure', " +
+				"@level0type = N'SCHEMA', @level0name = N'dbo', @level1type = N'PROCEDURE', @level1name = N'Test'\nGO",
+		},
+		{
+			style:       commands.ScriptStyleIfNotExists,
+			description: "Schema",
+			want: "IF NOT EXISTS (SELECT 1 FROM sys.fn_listextendedproperty(N'MS_Description', N'SCHEMA', N'dbo', " +
+				"NULL, NULL, NULL, NULL))\n" +
+				"    EXECUTE sp_addextendedproperty @name = N'MS_Description', @value = N'Schema', " +
+				"@level0type = N'SCHEMA', @level0name = N'dbo'\n" +
+				"ELSE\n" +
+				"    EXECUTE sp_updateextendedproperty @name = N'MS_Description', @value = N'Schema', " +
+				"@level0type = N'SCHEMA', @level0name = N'dbo'\nGO",
+		},
+		{
+			style:       commands.ScriptStyleCreateOrAlter,
+			level1type:  "VIEW",
+			name:        "Test",
+			description: "View",
+			want: "IF NOT EXISTS (SELECT 1 FROM sys.fn_listextendedproperty(N'MS_Description', N'SCHEMA', N'dbo', " +
+				"N'VIEW', N'Test', NULL, NULL))\n" +
+				"    EXECUTE sp_addextendedproperty @name = N'MS_Description', @value = N'View', " +
+				"@level0type = N'SCHEMA', @level0name = N'dbo', @level1type = N'VIEW', @level1name = N'Test'\n" +
+				"ELSE\n" +
+				"    EXECUTE sp_updateextendedproperty @name = N'MS_Description', @value = N'View', " +
+				"@level0type = N'SCHEMA', @level0name = N'dbo', @level1type = N'VIEW', @level1name = N'Test'\nGO",
+		},
+	}
+
+	for _, test := range cases {
+		command := &ScriptsFolderCommand{scriptStyle: test.style}
+		have := command.descriptionStatement("dbo", test.level1type, test.name, test.description)
+
+		if have != test.want {
+			t.Errorf("descriptionStatement(%s, %s) failed: have %q, want %q", test.style, test.level1type, have,
+				test.want)
+		}
+	}
+}
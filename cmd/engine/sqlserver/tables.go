@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
 )
@@ -191,6 +192,10 @@ func (command *ScriptsFolderCommand) writeTableDefinition(ctx context.Context, o
 		return obj, err
 	}
 
+	if strings.Trim(value, " ") != "" {
+		value = command.styledStatement(obj, strings.TrimSuffix(value, "\nGO"))
+	}
+
 	obj.SetDefinition([]byte(value))
 
 	return obj, nil
@@ -249,6 +254,58 @@ from (
         on (tables.history_table_id = history_tables.object_id)
 ) as tables
 order by tables.catalog, tables.[schema], tables.name
+`
+	selectTablesAzure = `
+select tables.catalog, tables.[schema], tables.name, tables.lob_data_space, tables.lob_data_space_type,
+    tables.is_default_data_space, tables.filestream_data_space, tables.lock_on_bulk_load, tables.uses_ansi_nulls,
+    tables.is_replicated, tables.has_replication_filter, tables.is_merge_published, tables.is_sync_tran_subscribed,
+    tables.has_unchecked_assembly_data, tables.text_in_row_limit, tables.large_value_types_out_of_row,
+    tables.is_tracked_by_cdc, tables.lock_escalation, tables.is_filetable, tables.durability,
+    tables.is_memory_optimized, tables.temporal_type, tables.history_table_schema, tables.history_table_name,
+    tables.is_remote_data_archive_enabled, tables.is_external, tables.history_retention_period,
+    tables.history_retention_period_unit, tables.is_node, tables.is_edge
+from (
+    select
+        [catalog] = db_name(),
+        [schema] = schema_name(objects.schema_id),
+        [name] = objects.name,
+
+        [lob_data_space] = cast(null as sysname) /* Azure SQL Database: no filegroups */,
+        [lob_data_space_type] = cast(null as nvarchar(60)) /* Azure SQL Database: no filegroups */,
+        [is_default_data_space] = cast(null as bit) /* Azure SQL Database: no filegroups */,
+        [filestream_data_space] = cast(null as sysname) /* Azure SQL Database: no FILESTREAM */,
+        [lock_on_bulk_load] = tables.lock_on_bulk_load,
+        [uses_ansi_nulls] = tables.uses_ansi_nulls,
+        [is_replicated] = tables.is_replicated,
+        [has_replication_filter] = tables.has_replication_filter,
+        [is_merge_published] = tables.is_merge_published,
+        [is_sync_tran_subscribed] = tables.is_sync_tran_subscribed,
+        [has_unchecked_assembly_data] = tables.has_unchecked_assembly_data,
+        [text_in_row_limit] = tables.text_in_row_limit,
+        [large_value_types_out_of_row] = tables.large_value_types_out_of_row,
+        [is_tracked_by_cdc] = tables.is_tracked_by_cdc,
+        [lock_escalation] = tables.lock_escalation_desc,
+        [is_filetable] = tables.is_filetable,
+        [durability] = tables.durability_desc,
+        [is_memory_optimized] = tables.is_memory_optimized,
+        [temporal_type] = tables.temporal_type_desc,
+        [history_table_id] = tables.history_table_id,
+        [history_table_schema] = schema_name(history_objects.schema_id),
+        [history_table_name] = history_objects.name,
+        [is_remote_data_archive_enabled] = tables.is_remote_data_archive_enabled,
+        [is_external] = tables.is_external,
+        [history_retention_period] = tables.history_retention_period,
+        [history_retention_period_unit] = tables.history_retention_period_unit_desc,
+        [is_node] = tables.is_node,
+        [is_edge] = tables.is_edge
+
+    from sys.tables as tables
+        inner join sys.objects as objects on (tables.object_id = objects.object_id)
+        left join sys.tables as history_tables
+            inner join sys.objects as history_objects on (history_tables.object_id = history_objects.object_id)
+        on (tables.history_table_id = history_tables.object_id)
+) as tables
+order by tables.catalog, tables.[schema], tables.name
 `
 	selectTables2017 = `
 select tables.catalog, tables.[schema], tables.name, tables.lob_data_space, tables.lob_data_space_type,
@@ -0,0 +1,131 @@
+package sqlserver
+
+import (
+	"fmt"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+// ScriptTemplateData данные объекта БД, передаваемые в пользовательский шаблон скрипта
+type ScriptTemplateData struct {
+	// Catalog наименование базы данных
+	Catalog string
+	// Schema схема объекта БД
+	Schema string
+	// Name наименование объекта БД
+	Name string
+	// SchemaAndName наименование объекта БД в формате [schema].[name]
+	SchemaAndName string
+	// Type тип объекта БД
+	Type string
+	// Owner владелец объекта БД
+	Owner string
+	// Description описание объекта БД
+	Description string
+	// Definition скрипт объекта БД, созданный по умолчанию
+	Definition string
+	// Body исходное определение SQL модуля
+	Body string
+	// ANSINulls SQL модуль создан с SET ANSI_NULLS ON
+	ANSINulls bool
+	// QuotedIdentifier SQL модуль создан с SET QUOTED_IDENTIFIER ON
+	QuotedIdentifier bool
+	// Columns поля таблицы/табличного типа, упорядоченные по идентификатору поля
+	Columns []*Column
+	// Indexes индексы таблицы/табличного типа, упорядоченные по наименованию
+	Indexes []*Index
+	// ForeignKeys внешние ключи таблицы, упорядоченные по наименованию
+	ForeignKeys []*ForeignKey
+	// Permissions разрешения на объект БД
+	Permissions []Permission
+	// Table параметры таблицы
+	Table *Table
+	// UserDefinedType параметры пользовательского типа
+	UserDefinedType *UserDefinedType
+	// Database параметры базы данных
+	Database *Database
+	// ScriptStyle стиль скриптов
+	ScriptStyle string
+}
+
+// templateData возвращает данные объекта БД object для пользовательского шаблона скрипта. В параметре body
+// передается исходное определение объекта БД
+func (command *ScriptsFolderCommand) templateData(object IDatabaseObject, body string) *ScriptTemplateData {
+	name := object.SchemaAndName(true)
+
+	data := &ScriptTemplateData{
+		Catalog:       object.Catalog(),
+		Schema:        object.Schema(),
+		Name:          object.Name(),
+		SchemaAndName: name,
+		Type:          object.Type().String(),
+		Owner:         object.Owner(),
+		Description:   object.Description(),
+		Definition:    string(object.Definition()),
+		ScriptStyle:   command.scriptStyle.String(),
+	}
+
+	if mod, ok := object.(ISQLModule); ok {
+		data.Body = body
+		data.ANSINulls = mod.ANSINullsValid() && mod.ANSINulls()
+		data.QuotedIdentifier = mod.QuotedIdentifierValid() && mod.QuotedIdentifier()
+	}
+
+	owner := OwnerTable
+
+	if object.Type() == output.UserDefinedTableType {
+		owner = OwnerUserDefinedTableDataType
+	}
+
+	switch object.Type() {
+	case output.Table, output.UserDefinedTableType:
+		data.Columns = command.columns[name].Slice()
+
+		for _, column := range data.Columns {
+			column.SetOptions(WithColumnOwner(owner), WithDefaultCollation(command.DatabaseCollation()))
+		}
+
+		data.Indexes = command.indexes[name].Slice()
+
+		for _, index := range data.Indexes {
+			index.SetOptions(WithIndexOwner(owner))
+		}
+	}
+
+	switch object.Type() {
+	case output.Table:
+		data.Table = command.tables[name]
+		data.ForeignKeys = command.foreignKeys[name].Slice()
+	case output.UserDefinedDataType, output.UserDefinedTableType:
+		data.UserDefinedType = command.userDefinedTypes[name]
+	case output.Database:
+		data.Database = command.database
+	}
+
+	if !command.skipPermissions {
+		data.Permissions = command.permissions[name].Permissions()
+	}
+
+	return data
+}
+
+// applyTemplate заменяет скрипт объекта БД object скриптом, созданным по пользовательскому шаблону, если шаблон
+// для типа объекта указан
+func (command *ScriptsFolderCommand) applyTemplate(object IDatabaseObject, body string) (IDatabaseObject, error) {
+	if len(command.templates) == 0 {
+		return object, nil
+	}
+
+	script, ok, err := command.templates.Execute(object.Type(), command.templateData(object, body))
+
+	if err != nil {
+		return object, fmt.Errorf("failed to execute the %s template for %s: %v", object.Type(),
+			object.SchemaAndName(true), err)
+	}
+
+	if ok {
+		object.SetDefinition(script)
+	}
+
+	return object, nil
+}
@@ -0,0 +1,80 @@
+package sqlserver
+
+import (
+	"testing"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+func TestScriptsFolderCommand_applyTemplate(t *testing.T) {
+	procedure, err := output.NewTemplate("procedure",
+		"-- {{ .SchemaAndName }}{{ range .Permissions }}\n-- {{ .State }} {{ .Permission }} TO {{ .User }}{{ end }}\n"+
+			"{{ .Definition }}")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table, err := output.NewTemplate("table",
+		"{{ .Name }}:{{ range .Columns }} {{ .Name }}{{ end }}")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	permissions := make(ObjectPermissions)
+	_ = permissions.Append("dbo", "Test", "EXECUTE", "GRANT", "reader")
+	_ = permissions.Append("dbo", "Test", "EXECUTE", "DENY", "guest")
+
+	columns := make(ObjectColumns)
+	_ = columns.Append("dbo", "Orders", &Column{ID: 2, Name: "Number"})
+	_ = columns.Append("dbo", "Orders", &Column{ID: 1, Name: "ID"})
+
+	command := &ScriptsFolderCommand{
+		engine:      &Engine{},
+		permissions: permissions,
+		columns:     columns,
+		templates: output.Templates{
+			output.Procedure: procedure,
+			output.Table:     table,
+		},
+	}
+
+	var cases = []struct {
+		object     *databaseObject
+		definition string
+		want       string
+	}{
+		{
+			object:     testDatabaseObject("dbo", "Test", "PROCEDURE"),
+			definition: "CREATE PROCEDURE [dbo].[Test] AS RETURN\nGO",
+			want: "-- [dbo].[Test]\n-- DENY EXECUTE TO guest\n-- GRANT EXECUTE TO reader\n" +
+				"CREATE PROCEDURE [dbo].[Test] AS RETURN\nGO",
+		},
+		{
+			object:     testDatabaseObject("dbo", "Orders", "BASE TABLE"),
+			definition: "",
+			want:       "Orders: ID Number",
+		},
+		{
+			object:     testDatabaseObject("dbo", "Phone", "DATA TYPE"),
+			definition: "CREATE TYPE [dbo].[Phone] FROM [varchar](20)\nGO",
+			want:       "CREATE TYPE [dbo].[Phone] FROM [varchar](20)\nGO",
+		},
+	}
+
+	for _, test := range cases {
+		test.object.SetDefinition([]byte(test.definition))
+
+		object, err := command.applyTemplate(test.object, "")
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if have := string(object.Definition()); have != test.want {
+			t.Errorf("applyTemplate(%s) failed: have %q, want %q", test.object.SchemaAndName(true), have,
+				test.want)
+		}
+	}
+}
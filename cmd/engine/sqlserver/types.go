@@ -195,7 +195,7 @@ func (command *ScriptsFolderCommand) writeDomainDefinition(ctx context.Context,
 
 func (command *ScriptsFolderCommand) writeDataTypeDefinition(ctx context.Context, object IDatabaseObject,
 	domain *UserDefinedType) (IDatabaseObject, error) {
-	const dataTypeDefinition = "CREATE TYPE %s FROM %s\nGO"
+	const dataTypeDefinition = "CREATE TYPE %s FROM %s"
 
 	var builder strings.Builder
 
@@ -208,7 +208,7 @@ func (command *ScriptsFolderCommand) writeDataTypeDefinition(ctx context.Context
 	}
 
 	definition := fmt.Sprintf(dataTypeDefinition, userTypeName, builder.String())
-	object.SetDefinition([]byte(definition))
+	object.SetDefinition([]byte(command.styledStatement(object, definition)))
 
 	return object, nil
 }
@@ -321,9 +321,7 @@ func (command *ScriptsFolderCommand) writeTableTypeDefinition(ctx context.Contex
 		builder.WriteString(fmt.Sprintf("\nWITH (%s)", strings.Join(flags, ", ")))
 	}
 
-	builder.WriteString("\nGO")
-
-	object.SetDefinition([]byte(builder.String()))
+	object.SetDefinition([]byte(command.styledStatement(object, builder.String())))
 
 	return object, nil
 }
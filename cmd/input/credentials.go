@@ -4,6 +4,7 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"strings"
 	"syscall"
 
 	"golang.org/x/crypto/ssh/terminal"
@@ -19,13 +20,18 @@ func Password() string {
 	return readPassword("Password: ")
 }
 
+// IsTerminal проверяет, что stdin - терминал, и пользователя можно запросить ввести имя пользователя и пароль
+func IsTerminal() bool {
+	return terminal.IsTerminal(int(syscall.Stdin))
+}
+
 func readLine(caption string) string {
 	fmt.Print(caption)
 
 	reader := bufio.NewReader(os.Stdin)
 
 	if text, err := reader.ReadString('\n'); err == nil {
-		return text
+		return strings.TrimRight(text, "\r\n")
 	}
 
 	return ""
@@ -0,0 +1,87 @@
+package input
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// PassFile возвращает источник учетных данных из файла path в формате pgpass: каждая строка файла имеет вид
+// host:port:database:username:password, первые четыре поля могут быть * (любое значение), символы : и \ в значениях
+// экранируются \, строки, начинающиеся с #, - комментарии. Используется первая строка, соответствующая серверу, порту,
+// БД и имени пользователя (если оно известно)
+func PassFile(path string) ISource {
+	return SourceFunc(func(_ context.Context, endpoint Endpoint) (string, string, error) {
+		f, err := os.Open(path)
+
+		if err != nil {
+			return "", "", err
+		}
+
+		defer f.Close()
+
+		scanner := bufio.NewScanner(f)
+		number := 0
+
+		for scanner.Scan() {
+			number++
+
+			line := strings.TrimRight(scanner.Text(), "\r")
+
+			if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+
+			fields := splitPassFileLine(line)
+
+			if len(fields) != 5 {
+				return "", "", fmt.Errorf("%s:%d: want 5 fields host:port:database:username:password, have %d",
+					path, number, len(fields))
+			}
+
+			if !passFileMatch(fields[0], endpoint.Host) || !passFileMatch(fields[1], endpoint.Port) ||
+				!passFileMatch(fields[2], endpoint.Database) {
+				continue
+			}
+
+			username := fields[3]
+
+			switch {
+			case endpoint.Username == "" && username != "*":
+				return username, fields[4], nil
+			case endpoint.Username != "" && passFileMatch(username, endpoint.Username):
+				return "", fields[4], nil
+			}
+		}
+
+		return "", "", scanner.Err()
+	})
+}
+
+func passFileMatch(pattern, value string) bool {
+	return pattern == "*" || strings.EqualFold(pattern, value)
+}
+
+// splitPassFileLine разбивает строку файла pgpass на поля с учетом экранирования символов : и \
+func splitPassFileLine(line string) []string {
+	fields := make([]string, 0, 5)
+
+	var field strings.Builder
+
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case c == '\\' && i+1 < len(line):
+			i++
+			field.WriteByte(line[i])
+		case c == ':':
+			fields = append(fields, field.String())
+			field.Reset()
+		default:
+			field.WriteByte(c)
+		}
+	}
+
+	return append(fields, field.String())
+}
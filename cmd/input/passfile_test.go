@@ -0,0 +1,87 @@
+package input
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPassFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbmill-passfile")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "passfile")
+
+	err = ioutil.WriteFile(path, []byte(`# dev
+localhost:1433:Orders:mill:orders-secret
+localhost:*:*:reader:reader-secret
+sql\:prod:1433:*:*:prod\:secret\\
+`), 0600)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var cases = []struct {
+		endpoint Endpoint
+		username string
+		password string
+	}{
+		{
+			endpoint: Endpoint{Host: "localhost", Port: "1433", Database: "Orders"},
+			username: "mill",
+			password: "orders-secret",
+		},
+		{
+			endpoint: Endpoint{Host: "LOCALHOST", Port: "14330", Database: "Sales", Username: "reader"},
+			password: "reader-secret",
+		},
+		{
+			endpoint: Endpoint{Host: "sql:prod", Port: "1433", Database: "Orders", Username: "sa"},
+			password: `prod:secret\`,
+		},
+		{
+			endpoint: Endpoint{Host: "sql:prod", Port: "1433", Database: "Orders"},
+		},
+		{
+			endpoint: Endpoint{Host: "localhost", Port: "1433", Database: "Sales", Username: "sa"},
+		},
+	}
+
+	for _, test := range cases {
+		username, password, err := PassFile(path).Credentials(context.Background(), test.endpoint)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if username != test.username || password != test.password {
+			t.Errorf("%v: have %q/%q, want %q/%q", test.endpoint, username, password, test.username, test.password)
+		}
+	}
+
+	if err = ioutil.WriteFile(path, []byte("localhost:1433:Orders\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = PassFile(path).Credentials(context.Background(), Endpoint{}); err == nil {
+		t.Error("malformed line: error expected")
+	}
+}
+
+func TestSplitPassFileLine(t *testing.T) {
+	have := splitPassFileLine(`a\:b:c\\:\d:`)
+	want := []string{"a:b", `c\`, "d", ""}
+
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have %q, want %q", have, want)
+	}
+}
@@ -0,0 +1,213 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const (
+	// UsernameEnv переменная окружения с именем пользователя БД
+	UsernameEnv = "DBMILL_USERNAME"
+	// PasswordEnv переменная окружения с паролем пользователя БД
+	PasswordEnv = "DBMILL_PASSWORD"
+)
+
+// ErrNoCredentials имя пользователя или пароль не получены ни из одного источника
+var ErrNoCredentials = errors.New("database credentials are not specified")
+
+// Endpoint сервер и БД, для подключения к которой нужны учетные данные
+type Endpoint struct {
+	// Host сервер БД
+	Host string
+	// Port порт сервера БД
+	Port string
+	// Database наименование БД
+	Database string
+	// Username имя пользователя БД, если оно уже известно
+	Username string
+	// HasPassword пароль уже получен из другого источника
+	HasPassword bool
+}
+
+// ISource интерфейс источника учетных данных
+type ISource interface {
+	// Credentials возвращает имя пользователя и пароль для подключения к endpoint. Источник может вернуть только одно
+	// из значений или ни одного, если учетных данных для endpoint в нем нет
+	Credentials(ctx context.Context, endpoint Endpoint) (username, password string, err error)
+}
+
+// SourceFunc функция, используемая как источник учетных данных
+type SourceFunc func(ctx context.Context, endpoint Endpoint) (username, password string, err error)
+
+// Credentials возвращает имя пользователя и пароль для подключения к endpoint
+func (f SourceFunc) Credentials(ctx context.Context, endpoint Endpoint) (string, string, error) {
+	return f(ctx, endpoint)
+}
+
+// Resolve дополняет имя пользователя endpoint.Username и пароль password значениями из источников sources, которые
+// опрашиваются по порядку, пока не будут получены оба значения. Если имя пользователя или пароль не получены, то
+// возвращается ошибка ErrNoCredentials
+func Resolve(ctx context.Context, endpoint Endpoint, password string, sources ...ISource) (string, string, error) {
+	username := endpoint.Username
+
+	for _, source := range sources {
+		if username != "" && password != "" {
+			break
+		}
+
+		endpoint.Username = username
+		endpoint.HasPassword = password != ""
+
+		u, p, err := source.Credentials(ctx, endpoint)
+
+		if err != nil {
+			return "", "", err
+		}
+
+		if username == "" {
+			username = u
+		}
+
+		if password == "" {
+			password = p
+		}
+	}
+
+	switch {
+	case username == "":
+		return "", "", fmt.Errorf("%w: username is missing", ErrNoCredentials)
+	case password == "":
+		return "", "", fmt.Errorf("%w: password is missing", ErrNoCredentials)
+	default:
+		return username, password, nil
+	}
+}
+
+// Environment возвращает источник учетных данных из переменных окружения DBMILL_USERNAME и DBMILL_PASSWORD. Функция
+// lookup возвращает значение переменной окружения (обычно os.LookupEnv)
+func Environment(lookup func(name string) (string, bool)) ISource {
+	return SourceFunc(func(context.Context, Endpoint) (string, string, error) {
+		username, _ := lookup(UsernameEnv)
+		password, _ := lookup(PasswordEnv)
+
+		return username, password, nil
+	})
+}
+
+// Источники пароля (PasswordFile, PasswordReader, PasswordCommand) не опрашиваются, если пароль уже получен
+
+// PasswordFile возвращает источник пароля из первой строки файла path
+func PasswordFile(path string) ISource {
+	return SourceFunc(func(_ context.Context, endpoint Endpoint) (string, string, error) {
+		if endpoint.HasPassword {
+			return "", "", nil
+		}
+
+		f, err := os.Open(path)
+
+		if err != nil {
+			return "", "", err
+		}
+
+		defer f.Close()
+
+		password, err := firstLine(f)
+
+		if err != nil {
+			return "", "", fmt.Errorf("%s: %w", path, err)
+		}
+
+		return "", password, nil
+	})
+}
+
+// PasswordReader возвращает источник пароля из первой строки reader (например, stdin, в который пароль передан
+// конвейером)
+func PasswordReader(reader io.Reader) ISource {
+	return SourceFunc(func(_ context.Context, endpoint Endpoint) (string, string, error) {
+		if endpoint.HasPassword {
+			return "", "", nil
+		}
+
+		password, err := firstLine(reader)
+		return "", password, err
+	})
+}
+
+// PasswordCommand возвращает источник пароля, которым является первая строка вывода внешней команды command. Команда
+// выполняется командной оболочкой (sh -c, в Windows - cmd /C), сервер, порт, БД и имя пользователя передаются ей в
+// переменных окружения DBMILL_HOST, DBMILL_PORT, DBMILL_DATABASE и DBMILL_USERNAME
+func PasswordCommand(command string) ISource {
+	return SourceFunc(func(ctx context.Context, endpoint Endpoint) (string, string, error) {
+		if endpoint.HasPassword {
+			return "", "", nil
+		}
+
+		shell, flag := "sh", "-c"
+
+		if runtime.GOOS == "windows" {
+			shell, flag = "cmd", "/C"
+		}
+
+		cmd := exec.CommandContext(ctx, shell, flag, command)
+
+		cmd.Env = append(os.Environ(),
+			"DBMILL_HOST="+endpoint.Host,
+			"DBMILL_PORT="+endpoint.Port,
+			"DBMILL_DATABASE="+endpoint.Database,
+			UsernameEnv+"="+endpoint.Username)
+
+		cmd.Stdin = os.Stdin
+		cmd.Stderr = os.Stderr
+
+		out, err := cmd.Output()
+
+		if err != nil {
+			return "", "", fmt.Errorf("password command failed: %w", err)
+		}
+
+		password, err := firstLine(bytes.NewReader(out))
+
+		return "", password, err
+	})
+}
+
+// Prompt возвращает источник учетных данных, запрашивающий у пользователя недостающие имя пользователя и пароль на
+// терминале. Если stdin - не терминал, то источник ничего не возвращает
+func Prompt() ISource {
+	return SourceFunc(func(_ context.Context, endpoint Endpoint) (string, string, error) {
+		if !IsTerminal() {
+			return "", "", nil
+		}
+
+		var username, password string
+
+		if endpoint.Username == "" {
+			username = Username()
+		}
+
+		if !endpoint.HasPassword {
+			password = Password()
+		}
+
+		return username, password, nil
+	})
+}
+
+func firstLine(reader io.Reader) (string, error) {
+	line, err := bufio.NewReader(reader).ReadString('\n')
+
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
@@ -0,0 +1,135 @@
+package input
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func staticSource(username, password string) ISource {
+	return SourceFunc(func(context.Context, Endpoint) (string, string, error) {
+		return username, password, nil
+	})
+}
+
+func TestResolve(t *testing.T) {
+	failed := SourceFunc(func(context.Context, Endpoint) (string, string, error) {
+		return "", "", errors.New("must not be called")
+	})
+
+	var cases = []struct {
+		username  string
+		password  string
+		sources   []ISource
+		want      [2]string
+		withError bool
+	}{
+		{username: "sa", password: "secret", sources: []ISource{failed}, want: [2]string{"sa", "secret"}},
+		{
+			username: "sa",
+			sources:  []ISource{staticSource("mill", ""), staticSource("mill", "secret"), failed},
+			want:     [2]string{"sa", "secret"},
+		},
+		{
+			sources: []ISource{staticSource("", "secret"), staticSource("mill", "other")},
+			want:    [2]string{"mill", "secret"},
+		},
+		{username: "sa", sources: []ISource{staticSource("", "")}, withError: true},
+		{password: "secret", withError: true},
+	}
+
+	for i, test := range cases {
+		username, password, err := Resolve(context.Background(), Endpoint{Username: test.username}, test.password,
+			test.sources...)
+
+		if (err != nil) != test.withError {
+			t.Errorf("case %d: unexpected error %v", i, err)
+			continue
+		}
+
+		if test.withError && !errors.Is(err, ErrNoCredentials) {
+			t.Errorf("case %d: have %v, want %v", i, err, ErrNoCredentials)
+		}
+
+		if !test.withError && (username != test.want[0] || password != test.want[1]) {
+			t.Errorf("case %d: have %s/%s, want %s/%s", i, username, password, test.want[0], test.want[1])
+		}
+	}
+}
+
+func TestPasswordSources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbmill-credentials")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "password")
+
+	if err = ioutil.WriteFile(path, []byte("p@ss:w0rd\r\nignored\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	env := map[string]string{UsernameEnv: "mill", PasswordEnv: "env-secret"}
+
+	var cases = []struct {
+		name     string
+		source   ISource
+		username string
+		password string
+	}{
+		{name: "file", source: PasswordFile(path), password: "p@ss:w0rd"},
+		{name: "reader", source: PasswordReader(strings.NewReader("piped\n")), password: "piped"},
+		{name: "reader without newline", source: PasswordReader(strings.NewReader("piped")), password: "piped"},
+		{
+			name: "environment",
+			source: Environment(func(name string) (string, bool) {
+				value, ok := env[name]
+				return value, ok
+			}),
+			username: "mill",
+			password: "env-secret",
+		},
+	}
+
+	if runtime.GOOS != "windows" {
+		cases = append(cases, struct {
+			name     string
+			source   ISource
+			username string
+			password string
+		}{
+			name:     "command",
+			source:   PasswordCommand(`printf '%s@%s\nignored\n' "$DBMILL_USERNAME" "$DBMILL_DATABASE"`),
+			password: "mill@Orders",
+		})
+	}
+
+	endpoint := Endpoint{Host: "localhost", Port: "1433", Database: "Orders", Username: "mill"}
+
+	for _, test := range cases {
+		username, password, err := test.source.Credentials(context.Background(), endpoint)
+
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+
+		if username != test.username || password != test.password {
+			t.Errorf("%s: have %q/%q, want %q/%q", test.name, username, password, test.username, test.password)
+		}
+	}
+
+	if runtime.GOOS != "windows" {
+		if _, _, err = PasswordCommand("exit 3").Credentials(context.Background(), endpoint); err == nil {
+			t.Error("failed password command: error expected")
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Filename наименование файла конфигурации проекта, который ищется в рабочем каталоге
+const Filename = "dbmill.yaml"
+
+// Config конфигурация проекта: значения флагов команд по умолчанию и именованные профили (dev, staging, prod и т.п.).
+// Ключи - наименования флагов без "--", значения - скаляры или списки скаляров для флагов-массивов
+type Config struct {
+	// Profile профиль, используемый, если профиль не указан явно
+	Profile string `yaml:"profile,omitempty"`
+	// Defaults значения флагов для всех профилей
+	Defaults Values `yaml:"defaults,omitempty"`
+	// Profiles значения флагов профилей. Заменяют значения Defaults
+	Profiles map[string]Values `yaml:"profiles,omitempty"`
+}
+
+// Values значения флагов команд
+type Values map[string]interface{}
+
+// ErrNotFound файл конфигурации проекта не найден
+var ErrNotFound = errors.New("project configuration file is not found")
+
+// Find возвращает путь к файлу конфигурации проекта в каталоге dir
+func Find(dir string) (string, error) {
+	path := filepath.Join(dir, Filename)
+
+	info, err := os.Stat(path)
+
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrNotFound
+		}
+
+		return "", err
+	}
+
+	if info.IsDir() {
+		return "", ErrNotFound
+	}
+
+	return path, nil
+}
+
+// Load читает конфигурацию проекта
+func Load(reader io.Reader) (*Config, error) {
+	data, err := ioutil.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var config Config
+
+	if err = yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, err
+	}
+
+	return &config, nil
+}
+
+// LoadFile читает конфигурацию проекта из файла path
+func LoadFile(path string) (*Config, error) {
+	f, err := os.Open(path)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.Close()
+
+	config, err := Load(f)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return config, nil
+}
+
+// Flags возвращает значения флагов профиля profile: значения Defaults, замененные значениями профиля. Если профиль
+// не указан, то используется профиль Profile, а если не указан и он - только Defaults. Ссылки на переменные
+// окружения в значениях не заменяются (см. Expand), чтобы не требовать переменных для флагов, указанных явно
+func (config *Config) Flags(profile string) (map[string][]string, error) {
+	if profile == "" {
+		profile = config.Profile
+	}
+
+	values := make(Values)
+
+	for name, value := range config.Defaults {
+		values[name] = value
+	}
+
+	if profile != "" {
+		profileValues, ok := config.Profiles[profile]
+
+		if !ok {
+			return nil, fmt.Errorf("profile %s is not found, available profiles: %v", profile, config.profiles())
+		}
+
+		for name, value := range profileValues {
+			values[name] = value
+		}
+	}
+
+	flags := make(map[string][]string, len(values))
+
+	for name, value := range values {
+		list, err := stringList(value)
+
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+
+		flags[name] = list
+	}
+
+	return flags, nil
+}
+
+func (config *Config) profiles() []string {
+	names := make([]string, 0, len(config.Profiles))
+
+	for name := range config.Profiles {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// stringList возвращает значение флага в виде списка строк
+func stringList(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case nil:
+		return []string{}, nil
+	case []interface{}:
+		list := make([]string, 0, len(v))
+
+		for _, item := range v {
+			s, err := scalar(item)
+
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, s)
+		}
+
+		return list, nil
+	default:
+		s, err := scalar(v)
+
+		if err != nil {
+			return nil, err
+		}
+
+		return []string{s}, nil
+	}
+}
+
+func scalar(value interface{}) (string, error) {
+	switch value.(type) {
+	case string, bool, int, int64, uint64, float64:
+		return fmt.Sprint(value), nil
+	default:
+		return "", fmt.Errorf("value %v must be a scalar or a list of scalars", value)
+	}
+}
+
+var reEnv = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?}`)
+
+// Expand заменяет в s ссылки ${NAME} значениями переменных окружения, а ссылки ${NAME:-default} - значениями
+// переменных окружения или значением default, если переменная не задана. Функция lookup возвращает значение
+// переменной окружения (обычно os.LookupEnv). Ссылка на незаданную переменную окружения без значения по умолчанию -
+// ошибка
+func Expand(s string, lookup func(name string) (string, bool)) (string, error) {
+	var err error
+
+	expanded := reEnv.ReplaceAllStringFunc(s, func(ref string) string {
+		match := reEnv.FindStringSubmatch(ref)
+
+		if value, ok := lookup(match[1]); ok {
+			return value
+		}
+
+		if match[2] != "" {
+			return match[3]
+		}
+
+		if err == nil {
+			err = fmt.Errorf("environment variable %s is not set", match[1])
+		}
+
+		return ref
+	})
+
+	return expanded, err
+}
@@ -0,0 +1,67 @@
+package erd
+
+// Attribute поле сущности (таблицы)
+type Attribute struct {
+	// Name наименование поля
+	Name string
+	// Type тип данных поля, например, nvarchar(50)
+	Type string
+	// Nullable поле допускает значение NULL
+	Nullable bool
+	// PrimaryKey поле входит в первичный ключ
+	PrimaryKey bool
+	// ForeignKey поле входит во внешний ключ
+	ForeignKey bool
+}
+
+// Entity сущность диаграммы - таблица БД
+type Entity struct {
+	// Schema схема таблицы
+	Schema string
+	// Name наименование таблицы
+	Name string
+	// Attributes поля таблицы в порядке их создания
+	Attributes []Attribute
+}
+
+// String возвращает наименование таблицы вместе со схемой: schema.name
+func (entity Entity) String() string {
+	return entity.Schema + "." + entity.Name
+}
+
+// Relationship связь сущностей - внешний ключ таблицы
+type Relationship struct {
+	// Name наименование внешнего ключа
+	Name string
+	// Child таблица внешнего ключа (Entity.String)
+	Child string
+	// Parent таблица, на которую ссылается внешний ключ (Entity.String)
+	Parent string
+	// Optional поля внешнего ключа допускают NULL: запись Child может не ссылаться на запись Parent
+	Optional bool
+	// Unique поля внешнего ключа уникальны: на запись Parent ссылается не более одной записи Child
+	Unique bool
+}
+
+// Diagram диаграмма "сущность-связь"
+type Diagram struct {
+	// Entities сущности диаграммы, упорядоченные по схеме и наименованию
+	Entities []Entity
+	// Relationships связи сущностей диаграммы, упорядоченные по таблице и наименованию внешнего ключа
+	Relationships []Relationship
+}
+
+// Schemas возвращает схемы сущностей диаграммы в порядке их появления
+func (diagram *Diagram) Schemas() []string {
+	schemas := make([]string, 0)
+	known := make(map[string]bool)
+
+	for _, entity := range diagram.Entities {
+		if !known[entity.Schema] {
+			known[entity.Schema] = true
+			schemas = append(schemas, entity.Schema)
+		}
+	}
+
+	return schemas
+}
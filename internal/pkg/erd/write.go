@@ -0,0 +1,349 @@
+package erd
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+	"unicode"
+)
+
+// Format формат записи диаграммы
+type Format byte
+
+const (
+	// FormatPlantUML диаграмма сущностей PlantUML в нотации Information Engineering
+	FormatPlantUML Format = iota
+	// FormatMermaid диаграмма Mermaid erDiagram
+	FormatMermaid
+	// FormatDOT граф Graphviz DOT
+	FormatDOT
+)
+
+// String возвращает наименование формата записи диаграммы
+func (format Format) String() string {
+	switch format {
+	case FormatPlantUML:
+		return "plantuml"
+	case FormatMermaid:
+		return "mermaid"
+	case FormatDOT:
+		return "dot"
+	default:
+		return "unknown"
+	}
+}
+
+// Write записывает диаграмму в writer в формате format
+func (diagram *Diagram) Write(writer io.Writer, format Format) error {
+	switch format {
+	case FormatPlantUML:
+		return diagram.writePlantUML(writer)
+	case FormatMermaid:
+		return diagram.writeMermaid(writer)
+	case FormatDOT:
+		return diagram.writeDOT(writer)
+	default:
+		return fmt.Errorf("unknown diagram format %d", format)
+	}
+}
+
+// identifier возвращает идентификатор сущности для PlantUML и Mermaid: наименование, в котором символы, кроме букв,
+// цифр и _, заменены на _
+func identifier(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			return r
+		}
+
+		return '_'
+	}, name)
+}
+
+// identifiers возвращает идентификаторы сущностей диаграммы для PlantUML и Mermaid по наименованию сущности
+// (Entity.String). Наименования разных сущностей могут давать один идентификатор (например, a.b_c и a_b.c), поэтому
+// к уже занятому идентификатору добавляется суффикс _2, _3 и т.д.
+func (diagram *Diagram) identifiers() map[string]string {
+	ids := make(map[string]string, len(diagram.Entities))
+	taken := make(map[string]bool, len(diagram.Entities))
+
+	for _, entity := range diagram.Entities {
+		name := entity.String()
+
+		if _, ok := ids[name]; ok {
+			continue
+		}
+
+		base := identifier(name)
+		id := base
+
+		for suffix := 2; taken[id]; suffix++ {
+			id = fmt.Sprintf("%s_%d", base, suffix)
+		}
+
+		ids[name] = id
+		taken[id] = true
+	}
+
+	return ids
+}
+
+// entityIdentifier возвращает идентификатор сущности name из ids или, если сущности нет на диаграмме, идентификатор,
+// полученный из ее наименования
+func entityIdentifier(ids map[string]string, name string) string {
+	if id, ok := ids[name]; ok {
+		return id
+	}
+
+	return identifier(name)
+}
+
+// parentCardinality возвращает обозначение количества записей Parent, на которые ссылается запись Child: ровно одна
+// или ни одной или одна
+func (relationship Relationship) parentCardinality() string {
+	if relationship.Optional {
+		return "|o"
+	}
+
+	return "||"
+}
+
+// childCardinality возвращает обозначение количества записей Child, которые ссылаются на запись Parent: ни одной или
+// несколько или ни одной или одна
+func (relationship Relationship) childCardinality() string {
+	if relationship.Unique {
+		return "o|"
+	}
+
+	return "o{"
+}
+
+// keys возвращает обозначения ключей, в которые входит поле
+func (attribute Attribute) keys() []string {
+	keys := make([]string, 0, 2)
+
+	if attribute.PrimaryKey {
+		keys = append(keys, "PK")
+	}
+
+	if attribute.ForeignKey {
+		keys = append(keys, "FK")
+	}
+
+	return keys
+}
+
+func (diagram *Diagram) writePlantUML(writer io.Writer) error {
+	w := bufio.NewWriter(writer)
+
+	ids := diagram.identifiers()
+
+	fmt.Fprintln(w, "@startuml")
+	fmt.Fprintln(w, "hide circle")
+	fmt.Fprintln(w, "skinparam linetype ortho")
+
+	for _, schema := range diagram.Schemas() {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "package \"%s\" {\n", schema)
+
+		for _, entity := range diagram.Entities {
+			if entity.Schema != schema {
+				continue
+			}
+
+			fmt.Fprintf(w, "    entity \"%s\" as %s {\n", entity, ids[entity.String()])
+
+			// поля первичного ключа записываются первыми и отделяются от остальных полей
+			primaryKey := 0
+
+			for _, attribute := range entity.Attributes {
+				if attribute.PrimaryKey {
+					primaryKey++
+					writePlantUMLAttribute(w, attribute)
+				}
+			}
+
+			if primaryKey > 0 && primaryKey < len(entity.Attributes) {
+				fmt.Fprintln(w, "        --")
+			}
+
+			for _, attribute := range entity.Attributes {
+				if !attribute.PrimaryKey {
+					writePlantUMLAttribute(w, attribute)
+				}
+			}
+
+			fmt.Fprintln(w, "    }")
+		}
+
+		fmt.Fprintln(w, "}")
+	}
+
+	if len(diagram.Relationships) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	for _, relationship := range diagram.Relationships {
+		fmt.Fprintf(w, "%s %s--%s %s : %s\n", entityIdentifier(ids, relationship.Parent),
+			relationship.parentCardinality(), relationship.childCardinality(),
+			entityIdentifier(ids, relationship.Child), relationship.Name)
+	}
+
+	fmt.Fprintln(w, "@enduml")
+
+	return w.Flush()
+}
+
+// writePlantUMLAttribute записывает поле сущности PlantUML. Обязательные поля отмечаются *
+func writePlantUMLAttribute(w io.Writer, attribute Attribute) {
+	mandatory := ""
+
+	if !attribute.Nullable {
+		mandatory = "* "
+	}
+
+	stereotypes := ""
+
+	for _, key := range attribute.keys() {
+		stereotypes += " <<" + key + ">>"
+	}
+
+	fmt.Fprintf(w, "        %s%s : %s%s\n", mandatory, attribute.Name, attribute.Type, stereotypes)
+}
+
+func (diagram *Diagram) writeMermaid(writer io.Writer) error {
+	w := bufio.NewWriter(writer)
+
+	ids := diagram.identifiers()
+
+	fmt.Fprintln(w, "erDiagram")
+
+	for _, entity := range diagram.Entities {
+		fmt.Fprintf(w, "    %s[\"%s\"] {\n", ids[entity.String()], entity)
+
+		for _, attribute := range entity.Attributes {
+			line := mermaidType(attribute.Type) + " " + identifier(attribute.Name)
+
+			if keys := attribute.keys(); len(keys) > 0 {
+				line += " " + strings.Join(keys, ", ")
+			}
+
+			if attribute.Nullable {
+				line += ` "null"`
+			}
+
+			fmt.Fprintf(w, "        %s\n", line)
+		}
+
+		fmt.Fprintln(w, "    }")
+	}
+
+	for _, relationship := range diagram.Relationships {
+		fmt.Fprintf(w, "    %s %s--%s %s : \"%s\"\n", entityIdentifier(ids, relationship.Parent),
+			relationship.parentCardinality(), relationship.childCardinality(),
+			entityIdentifier(ids, relationship.Child), relationship.Name)
+	}
+
+	return w.Flush()
+}
+
+// mermaidType возвращает тип поля для Mermaid: слово из букв, цифр и символов _-(), например, decimal(18_2)
+func mermaidType(typeName string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return -1
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("_-()", r):
+			return r
+		default:
+			return '_'
+		}
+	}, typeName)
+}
+
+func (diagram *Diagram) writeDOT(writer io.Writer) error {
+	w := bufio.NewWriter(writer)
+
+	fmt.Fprintln(w, "digraph erd {")
+	fmt.Fprintln(w, "    rankdir=LR;")
+	fmt.Fprintln(w, "    node [shape=plaintext];")
+
+	for _, schema := range diagram.Schemas() {
+		fmt.Fprintln(w)
+		fmt.Fprintf(w, "    subgraph %s {\n", dotQuote("cluster_"+schema))
+		fmt.Fprintf(w, "        label=%s;\n", dotQuote(schema))
+
+		for _, entity := range diagram.Entities {
+			if entity.Schema == schema {
+				fmt.Fprintf(w, "        %s [label=<%s>];\n", dotQuote(entity.String()), entity.htmlTable())
+			}
+		}
+
+		fmt.Fprintln(w, "    }")
+	}
+
+	if len(diagram.Relationships) > 0 {
+		fmt.Fprintln(w)
+	}
+
+	for _, relationship := range diagram.Relationships {
+		// ребро направлено от таблицы внешнего ключа к таблице, на которую он ссылается
+		head := "teetee"
+
+		if relationship.Optional {
+			head = "teeodot"
+		}
+
+		tail := "crowodot"
+
+		if relationship.Unique {
+			tail = "teeodot"
+		}
+
+		fmt.Fprintf(w, "    %s -> %s [label=%s, dir=both, arrowhead=%s, arrowtail=%s];\n",
+			dotQuote(relationship.Child), dotQuote(relationship.Parent), dotQuote(relationship.Name), head, tail)
+	}
+
+	fmt.Fprintln(w, "}")
+
+	return w.Flush()
+}
+
+// htmlTable возвращает HTML-таблицу сущности для метки вершины DOT
+func (entity Entity) htmlTable() string {
+	var builder strings.Builder
+
+	builder.WriteString(`<table border="0" cellborder="1" cellspacing="0">`)
+	builder.WriteString(`<tr><td bgcolor="lightgrey"><b>` + html.EscapeString(entity.String()) + `</b></td></tr>`)
+
+	for _, attribute := range entity.Attributes {
+		text := html.EscapeString(attribute.Name + " " + attribute.Type)
+
+		if !attribute.Nullable {
+			text += " NOT NULL"
+		}
+
+		if keys := attribute.keys(); len(keys) > 0 {
+			text += " " + strings.Join(keys, ", ")
+		}
+
+		if attribute.PrimaryKey {
+			text = "<u>" + text + "</u>"
+		}
+
+		builder.WriteString(`<tr><td align="left">` + text + `</td></tr>`)
+	}
+
+	builder.WriteString(`</table>`)
+
+	return builder.String()
+}
+
+// dotQuote возвращает строку s в кавычках DOT
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+
+	return `"` + s + `"`
+}
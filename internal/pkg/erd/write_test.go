@@ -0,0 +1,229 @@
+package erd
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func testDiagram() *Diagram {
+	return &Diagram{
+		Entities: []Entity{
+			{Schema: "dbo", Name: "Customers", Attributes: []Attribute{
+				{Name: "ID", Type: "int", PrimaryKey: true},
+				{Name: "Name", Type: "nvarchar(100)"},
+			}},
+			{Schema: "dbo", Name: "Orders", Attributes: []Attribute{
+				{Name: "Number", Type: "nvarchar(20)"},
+				{Name: "ID", Type: "int", PrimaryKey: true},
+				{Name: "CustomerID", Type: "int", ForeignKey: true},
+				{Name: "Total", Type: "decimal(18, 2)", Nullable: true},
+			}},
+			{Schema: "sales", Name: "Invoices", Attributes: []Attribute{
+				{Name: "OrderID", Type: "int", PrimaryKey: true, ForeignKey: true},
+				{Name: "ManagerID", Type: "int", Nullable: true, ForeignKey: true},
+			}},
+		},
+		Relationships: []Relationship{
+			{Name: "FK_Orders_Customers", Child: "dbo.Orders", Parent: "dbo.Customers"},
+			{Name: "FK_Invoices_Customers", Child: "sales.Invoices", Parent: "dbo.Customers", Optional: true},
+			{Name: "FK_Invoices_Orders", Child: "sales.Invoices", Parent: "dbo.Orders", Unique: true},
+		},
+	}
+}
+
+func TestDiagram_Write(t *testing.T) {
+	var cases = []struct {
+		format Format
+		want   string
+	}{
+		{
+			format: FormatPlantUML,
+			want: `@startuml
+hide circle
+skinparam linetype ortho
+
+package "dbo" {
+    entity "dbo.Customers" as dbo_Customers {
+        * ID : int <<PK>>
+        --
+        * Name : nvarchar(100)
+    }
+    entity "dbo.Orders" as dbo_Orders {
+        * ID : int <<PK>>
+        --
+        * Number : nvarchar(20)
+        * CustomerID : int <<FK>>
+        Total : decimal(18, 2)
+    }
+}
+
+package "sales" {
+    entity "sales.Invoices" as sales_Invoices {
+        * OrderID : int <<PK>> <<FK>>
+        --
+        ManagerID : int <<FK>>
+    }
+}
+
+dbo_Customers ||--o{ dbo_Orders : FK_Orders_Customers
+dbo_Customers |o--o{ sales_Invoices : FK_Invoices_Customers
+dbo_Orders ||--o| sales_Invoices : FK_Invoices_Orders
+@enduml
+`,
+		},
+		{
+			format: FormatMermaid,
+			want: `erDiagram
+    dbo_Customers["dbo.Customers"] {
+        int ID PK
+        nvarchar(100) Name
+    }
+    dbo_Orders["dbo.Orders"] {
+        nvarchar(20) Number
+        int ID PK
+        int CustomerID FK
+        decimal(18_2) Total "null"
+    }
+    sales_Invoices["sales.Invoices"] {
+        int OrderID PK, FK
+        int ManagerID FK "null"
+    }
+    dbo_Customers ||--o{ dbo_Orders : "FK_Orders_Customers"
+    dbo_Customers |o--o{ sales_Invoices : "FK_Invoices_Customers"
+    dbo_Orders ||--o| sales_Invoices : "FK_Invoices_Orders"
+`,
+		},
+		{
+			format: FormatDOT,
+			want: `digraph erd {
+    rankdir=LR;
+    node [shape=plaintext];
+
+    subgraph "cluster_dbo" {
+        label="dbo";
+        "dbo.Customers" [label=<<table border="0" cellborder="1" cellspacing="0"><tr><td bgcolor="lightgrey">` +
+				`<b>dbo.Customers</b></td></tr><tr><td align="left"><u>ID int NOT NULL PK</u></td></tr>` +
+				`<tr><td align="left">Name nvarchar(100) NOT NULL</td></tr></table>>];
+        "dbo.Orders" [label=<<table border="0" cellborder="1" cellspacing="0"><tr><td bgcolor="lightgrey">` +
+				`<b>dbo.Orders</b></td></tr><tr><td align="left">Number nvarchar(20) NOT NULL</td></tr>` +
+				`<tr><td align="left"><u>ID int NOT NULL PK</u></td></tr>` +
+				`<tr><td align="left">CustomerID int NOT NULL FK</td></tr>` +
+				`<tr><td align="left">Total decimal(18, 2)</td></tr></table>>];
+    }
+
+    subgraph "cluster_sales" {
+        label="sales";
+        "sales.Invoices" [label=<<table border="0" cellborder="1" cellspacing="0"><tr><td bgcolor="lightgrey">` +
+				`<b>sales.Invoices</b></td></tr><tr><td align="left"><u>OrderID int NOT NULL PK, FK</u></td></tr>` +
+				`<tr><td align="left">ManagerID int FK</td></tr></table>>];
+    }
+
+    "dbo.Orders" -> "dbo.Customers" [label="FK_Orders_Customers", dir=both, arrowhead=teetee, arrowtail=crowodot];
+    "sales.Invoices" -> "dbo.Customers" [label="FK_Invoices_Customers", dir=both, arrowhead=teeodot, ` +
+				`arrowtail=crowodot];
+    "sales.Invoices" -> "dbo.Orders" [label="FK_Invoices_Orders", dir=both, arrowhead=teetee, arrowtail=teeodot];
+}
+`,
+		},
+	}
+
+	for _, test := range cases {
+		var buf bytes.Buffer
+
+		if err := testDiagram().Write(&buf, test.format); err != nil {
+			t.Fatal(err)
+		}
+
+		if have := buf.String(); have != test.want {
+			t.Errorf("%s: have\n%s\nwant\n%s", test.format, have, test.want)
+		}
+	}
+}
+
+func TestDiagram_WriteIdentifierCollision(t *testing.T) {
+	diagram := &Diagram{
+		Entities: []Entity{
+			{Schema: "a", Name: "b_c", Attributes: []Attribute{{Name: "ID", Type: "int", PrimaryKey: true}}},
+			{Schema: "a_b", Name: "c", Attributes: []Attribute{{Name: "ID", Type: "int", PrimaryKey: true}}},
+		},
+		Relationships: []Relationship{
+			{Name: "FK_c_b_c", Child: "a_b.c", Parent: "a.b_c"},
+		},
+	}
+
+	var cases = []struct {
+		format Format
+		want   string
+	}{
+		{
+			format: FormatPlantUML,
+			want: `@startuml
+hide circle
+skinparam linetype ortho
+
+package "a" {
+    entity "a.b_c" as a_b_c {
+        * ID : int <<PK>>
+    }
+}
+
+package "a_b" {
+    entity "a_b.c" as a_b_c_2 {
+        * ID : int <<PK>>
+    }
+}
+
+a_b_c ||--o{ a_b_c_2 : FK_c_b_c
+@enduml
+`,
+		},
+		{
+			format: FormatMermaid,
+			want: `erDiagram
+    a_b_c["a.b_c"] {
+        int ID PK
+    }
+    a_b_c_2["a_b.c"] {
+        int ID PK
+    }
+    a_b_c ||--o{ a_b_c_2 : "FK_c_b_c"
+`,
+		},
+	}
+
+	for _, test := range cases {
+		var buf bytes.Buffer
+
+		if err := diagram.Write(&buf, test.format); err != nil {
+			t.Fatal(err)
+		}
+
+		if have := buf.String(); have != test.want {
+			t.Errorf("%s: have\n%s\nwant\n%s", test.format, have, test.want)
+		}
+	}
+}
+
+func TestDiagram_identifiers(t *testing.T) {
+	diagram := &Diagram{
+		Entities: []Entity{
+			{Schema: "a", Name: "b_2"},
+			{Schema: "a", Name: "b"},
+			{Schema: "a_b", Name: "2"},
+			{Schema: "a-b", Name: "2"},
+			{Schema: "a", Name: "b"},
+		},
+	}
+
+	want := map[string]string{
+		"a.b_2": "a_b_2",
+		"a.b":   "a_b",
+		"a_b.2": "a_b_2_2",
+		"a-b.2": "a_b_2_3",
+	}
+
+	if have := diagram.identifiers(); !reflect.DeepEqual(have, want) {
+		t.Errorf("have %v, want %v", have, want)
+	}
+}
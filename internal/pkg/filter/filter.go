@@ -1,9 +1,14 @@
 package filter
 
 import (
+	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"regexp"
+	"strings"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
 )
 
 // ErrorNotMatched ошибка "Значение не соответствует фильтру"
@@ -11,59 +16,272 @@ var ErrorNotMatched = errors.New("not matched")
 
 // IFilter интерфейс фильтра объектов
 type IFilter interface {
-	// Match проверяет соответствие значения value фильтру
-	Match(value string) error
+	// Match проверяет соответствие объекта БД object фильтру
+	Match(object Object) error
+}
+
+// Object объект БД, проверяемый фильтром
+type Object struct {
+	// Type тип объекта БД
+	Type output.DatabaseObjectType
+	// Schema схема объекта БД
+	Schema string
+	// Name наименование объекта БД
+	Name string
+}
+
+// QualifiedName возвращает наименование объекта БД вместе со схемой в квадратных скобках: [schema].[name]. С ним
+// сравниваются регулярные выражения
+func (object Object) QualifiedName() string {
+	switch {
+	case object.Schema != "" && object.Name != "":
+		return "[" + object.Schema + "].[" + object.Name + "]"
+	case object.Schema != "":
+		return "[" + object.Schema + "]"
+	case object.Name != "":
+		return "[" + object.Name + "]"
+	default:
+		return ""
+	}
 }
 
-// Filter фильтр объектов базы данных
+// Filter фильтр объектов базы данных. Каждое выражение фильтра - правило вида
+//
+//	[!][type:]pattern
+//
+// Правило с типом объекта БД (procedure:, table: и т.п., *: - любой тип) содержит шаблон schema.name или name, в
+// котором * обозначает любую последовательность символов, а ? - любой символ. Наименования можно заключать в
+// квадратные скобки. Правило без типа - регулярное выражение для наименования объекта в виде [schema].[name], как и
+// в предыдущих версиях. Правило с ! исключает соответствующие ему объекты. Объект соответствует фильтру, если он
+// соответствует хотя бы одному правилу без ! (или таких правил нет) и не соответствует ни одному правилу с !
+//
+// В списке исключений (NewExclusion) объект соответствует фильтру, только если он соответствует хотя бы одному правилу
+// без ! и не соответствует ни одному правилу с !: правила с ! - исключения из списка исключений, поэтому список
+// только из правил с ! не исключает ни одного объекта
 type Filter struct {
-	expressions []string
+	rules []*rule
+	// exclusion фильтр - список исключений
+	exclusion bool
 }
 
-// New возвращает новый экземпляр фильтра объектов
+// New возвращает новый экземпляр фильтра объектов. Пустые выражения пропускаются
 func New(expressions []string) (*Filter, error) {
+	rules := make([]*rule, 0, len(expressions))
+
 	for _, exp := range expressions {
-		if _, err := regexp.Compile(exp); err != nil {
+		if strings.TrimSpace(exp) == "" {
+			continue
+		}
+
+		r, err := parseRule(exp)
+
+		if err != nil {
 			return nil, fmt.Errorf("%s: %v", exp, err)
 		}
+
+		rules = append(rules, r)
 	}
 
 	return &Filter{
-		expressions: expressions,
+		rules: rules,
 	}, nil
 }
 
-// Match проверяет соответствие значения value фильтру
-func (filter *Filter) Match(value string) error {
-	if len(filter.expressions) == 0 {
-		return nil
+// NewExclusion возвращает новый экземпляр фильтра - списка исключений. Пустые выражения пропускаются
+func NewExclusion(expressions []string) (*Filter, error) {
+	filter, err := New(expressions)
+
+	if err != nil {
+		return nil, err
+	}
+
+	filter.exclusion = true
+
+	return filter, nil
+}
+
+// Empty проверяет, что в фильтре нет правил
+func (filter *Filter) Empty() bool {
+	return len(filter.rules) == 0
+}
+
+// Read читает выражения фильтра из reader, по одному в строке. Пробельные символы в начале и в конце строки
+// отбрасываются, пустые строки и комментарии - строки, которые начинаются с #, - пропускаются
+func Read(reader io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Split(bufio.ScanLines)
+
+	expressions := make([]string, 0)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		expressions = append(expressions, line)
 	}
 
-	for _, exp := range filter.expressions {
-		if err := filter.match(exp, value); err == nil {
-			return nil
-		} else {
-			if err != ErrorNotMatched {
-				return err
+	return expressions, scanner.Err()
+}
+
+// Match проверяет соответствие объекта БД object фильтру
+func (filter *Filter) Match(object Object) error {
+	var positive, matched bool
+
+	for _, r := range filter.rules {
+		if r.negated {
+			if r.match(object) {
+				return ErrorNotMatched
 			}
+
+			continue
 		}
+
+		positive = true
+		matched = matched || r.match(object)
+	}
+
+	if matched || (!positive && !filter.exclusion) {
+		return nil
 	}
 
 	return ErrorNotMatched
 }
 
-func (filter *Filter) match(pattern, value string) error {
-	re, err := regexp.Compile(pattern)
+// rule правило фильтра
+type rule struct {
+	negated bool
+	// anyType правило применяется к объектам любого типа
+	anyType bool
+	objType output.DatabaseObjectType
+	// re регулярное выражение правила без типа объекта БД
+	re *regexp.Regexp
+	// schema шаблон схемы объекта БД. nil - любая схема
+	schema *regexp.Regexp
+	// name шаблон наименования объекта БД
+	name *regexp.Regexp
+}
+
+var reQualifier = regexp.MustCompile(`^(\*|[A-Za-z]+):`)
+
+func parseRule(exp string) (*rule, error) {
+	r := &rule{}
+
+	if strings.HasPrefix(exp, "!") {
+		r.negated = true
+		exp = exp[1:]
+	}
+
+	qualifier := reQualifier.FindStringSubmatch(exp)
+
+	if qualifier == nil {
+		re, err := regexp.Compile(exp)
+
+		if err != nil {
+			return nil, err
+		}
+
+		r.anyType = true
+		r.re = re
+
+		return r, nil
+	}
+
+	if qualifier[1] == "*" {
+		r.anyType = true
+	} else {
+		objType, err := output.ParseDatabaseObjectType(qualifier[1])
+
+		if err != nil {
+			return nil, err
+		}
+
+		r.objType = objType
+	}
+
+	schema, name, err := splitPattern(exp[len(qualifier[0]):])
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	ok := re.MatchString(value)
+	if schema != "" {
+		r.schema = globRegexp(schema)
+	}
 
-	if ok {
-		return nil
+	r.name = globRegexp(name)
+
+	return r, nil
+}
+
+func (r *rule) match(object Object) bool {
+	if !r.anyType && object.Type != r.objType {
+		return false
 	}
 
-	return ErrorNotMatched
+	if r.re != nil {
+		return r.re.MatchString(object.QualifiedName())
+	}
+
+	name := object.Name
+
+	// у схем и других объектов без наименования с шаблоном сравнивается схема
+	if name == "" {
+		name = object.Schema
+	}
+
+	if r.schema == nil {
+		return r.name.MatchString(name)
+	}
+
+	return r.schema.MatchString(object.Schema) && r.name.MatchString(object.Name)
+}
+
+// splitPattern разделяет шаблон schema.name на шаблоны схемы и наименования. Точка внутри квадратных скобок не
+// разделяет шаблон
+func splitPattern(pattern string) (string, string, error) {
+	if pattern == "" {
+		return "", "", errors.New("pattern is empty")
+	}
+
+	var bracket bool
+
+	for i, c := range pattern {
+		switch {
+		case c == '[':
+			bracket = true
+		case c == ']':
+			bracket = false
+		case c == '.' && !bracket:
+			schema, name := unbracket(pattern[:i]), unbracket(pattern[i+1:])
+
+			if schema == "" || name == "" {
+				return "", "", errors.New("schema or name pattern is empty")
+			}
+
+			return schema, name, nil
+		}
+	}
+
+	return "", unbracket(pattern), nil
+}
+
+func unbracket(s string) string {
+	if len(s) > 1 && strings.HasPrefix(s, "[") && strings.HasSuffix(s, "]") {
+		return s[1 : len(s)-1]
+	}
+
+	return s
+}
+
+// globRegexp возвращает регулярное выражение шаблона с символами * и ?. Шаблоны не чувствительны к регистру, как и
+// наименования объектов в БД с регистронезависимой сортировкой
+func globRegexp(glob string) *regexp.Regexp {
+	exp := regexp.QuoteMeta(glob)
+	exp = strings.ReplaceAll(exp, `\*`, ".*")
+	exp = strings.ReplaceAll(exp, `\?`, ".")
+
+	return regexp.MustCompile("(?i)^" + exp + "$")
 }
@@ -1,29 +1,33 @@
 package filter
 
 import (
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
 )
 
-var testArray = []string{"(dbo|tmp)\\.Proc1", "dbo\\.Proc2", "^dbo\\..{1,}Asute.{1,}"}
+var testArray = []string{`\[(dbo|tmp)\]\.\[Proc1\]`, `\[dbo\]\.\[Proc2\]`, `^\[dbo\]\..{1,}Asute.{1,}`}
 
 var testCases = []struct {
-	have string
+	have Object
 	want bool
 }{
 	{
-		have: "tmp.Proc1",
+		have: Object{Type: output.Procedure, Schema: "tmp", Name: "Proc1"},
 		want: true,
 	},
 	{
-		have: "dbo.Get_Asute_Devices",
+		have: Object{Type: output.Procedure, Schema: "dbo", Name: "Get_Asute_Devices"},
 		want: true,
 	},
 	{
-		have: "dbo.Proc2",
+		have: Object{Type: output.Procedure, Schema: "dbo", Name: "Proc2"},
 		want: true,
 	},
 	{
-		have: "grf.Get_Devices",
+		have: Object{Type: output.Procedure, Schema: "grf", Name: "Get_Devices"},
 		want: false,
 	},
 }
@@ -87,3 +91,206 @@ func TestMatchWithEmptyArray(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestMatchRules(t *testing.T) {
+	var (
+		usp       = Object{Type: output.Procedure, Schema: "rpt", Name: "usp_Sales"}
+		uspTmp    = Object{Type: output.Procedure, Schema: "rpt", Name: "usp_tmp_Sales"}
+		view      = Object{Type: output.View, Schema: "rpt", Name: "Sales"}
+		dboProc   = Object{Type: output.Procedure, Schema: "dbo", Name: "GetOrders"}
+		dboTable  = Object{Type: output.Table, Schema: "dbo", Name: "Orders"}
+		rptSchema = Object{Type: output.Schema, Schema: "rpt"}
+		dotted    = Object{Type: output.Table, Schema: "dbo", Name: "Orders.Archive"}
+	)
+
+	var cases = []struct {
+		rules []string
+		match []Object
+		miss  []Object
+	}{
+		{
+			rules: []string{"procedure:rpt.*", "!procedure:rpt.usp_tmp*"},
+			match: []Object{usp},
+			miss:  []Object{uspTmp, view, dboProc, dboTable},
+		},
+		{
+			rules: []string{"*:rpt.*"},
+			match: []Object{usp, uspTmp, view, rptSchema},
+			miss:  []Object{dboProc, dboTable},
+		},
+		{
+			rules: []string{"schema:rpt", "table:Orders"},
+			match: []Object{rptSchema, dboTable},
+			miss:  []Object{usp, dboProc},
+		},
+		{
+			rules: []string{"!*:dbo.*"},
+			match: []Object{usp, view, rptSchema},
+			miss:  []Object{dboProc, dboTable},
+		},
+		{
+			rules: []string{"PROCEDURE:[DBO].[get?rders]"},
+			match: []Object{dboProc},
+			miss:  []Object{dboTable, usp},
+		},
+		{
+			rules: []string{"table:[dbo].[Orders.Archive]"},
+			match: []Object{dotted},
+			miss:  []Object{dboTable},
+		},
+		{
+			rules: []string{`^\[rpt\]`, `!Sales\]$`},
+			match: []Object{rptSchema},
+			miss:  []Object{usp, uspTmp, view, dboProc},
+		},
+		{
+			rules: []string{"", "  ", "dataType:dbo.*"},
+			match: []Object{{Type: output.UserDefinedDataType, Schema: "dbo", Name: "Phone"}},
+			miss:  []Object{dboTable},
+		},
+	}
+
+	for _, test := range cases {
+		f, err := New(test.rules)
+
+		if err != nil {
+			t.Fatalf("%v: %v", test.rules, err)
+		}
+
+		for _, object := range test.match {
+			if err := f.Match(object); err != nil {
+				t.Errorf("%v: %s %s is not matched", test.rules, object.Type, object.QualifiedName())
+			}
+		}
+
+		for _, object := range test.miss {
+			if err := f.Match(object); err != ErrorNotMatched {
+				t.Errorf("%v: %s %s is matched", test.rules, object.Type, object.QualifiedName())
+			}
+		}
+	}
+}
+
+func TestMatchExclusion(t *testing.T) {
+	var (
+		usp    = Object{Type: output.Procedure, Schema: "rpt", Name: "usp_Sales"}
+		uspTmp = Object{Type: output.Procedure, Schema: "rpt", Name: "usp_tmp_Sales"}
+		table  = Object{Type: output.Table, Schema: "dbo", Name: "Orders"}
+	)
+
+	var cases = []struct {
+		rules []string
+		match []Object
+		miss  []Object
+	}{
+		{
+			rules: []string{"procedure:rpt.*", "!procedure:rpt.usp_tmp*"},
+			match: []Object{usp},
+			miss:  []Object{uspTmp, table},
+		},
+		{
+			rules: []string{"!procedure:rpt.usp_tmp*"},
+			miss:  []Object{usp, uspTmp, table},
+		},
+		{
+			rules: []string{"", "  "},
+			miss:  []Object{usp, uspTmp, table},
+		},
+	}
+
+	for _, test := range cases {
+		f, err := NewExclusion(test.rules)
+
+		if err != nil {
+			t.Fatalf("%v: %v", test.rules, err)
+		}
+
+		for _, object := range test.match {
+			if err := f.Match(object); err != nil {
+				t.Errorf("%v: %s %s is not matched", test.rules, object.Type, object.QualifiedName())
+			}
+		}
+
+		for _, object := range test.miss {
+			if err := f.Match(object); err != ErrorNotMatched {
+				t.Errorf("%v: %s %s is matched", test.rules, object.Type, object.QualifiedName())
+			}
+		}
+	}
+}
+
+func TestEmpty(t *testing.T) {
+	cases := []struct {
+		rules []string
+		want  bool
+	}{
+		{rules: nil, want: true},
+		{rules: []string{"", "  "}, want: true},
+		{rules: []string{"!table:dbo.*"}, want: false},
+	}
+
+	for _, test := range cases {
+		f, err := New(test.rules)
+
+		if err != nil {
+			t.Fatalf("%v: %v", test.rules, err)
+		}
+
+		if have := f.Empty(); have != test.want {
+			t.Errorf("%v: have %v, want %v", test.rules, have, test.want)
+		}
+	}
+}
+
+func TestNewInvalidRules(t *testing.T) {
+	for _, rule := range []string{"proc:rpt.*", "procedure:", "procedure:rpt.", "!table:.Orders", "(dbo"} {
+		if _, err := New([]string{rule}); err == nil {
+			t.Errorf("New(%q): the error is expected", rule)
+		}
+	}
+}
+
+func TestRead(t *testing.T) {
+	const list = "# procedures of the rpt schema\r\nprocedure:rpt.*\r\n\r\n  # temporary procedures\n" +
+		"!procedure:rpt.usp_tmp*\n   \n\\[dbo\\]\\.\\[Orders\\]\n"
+
+	have, err := Read(strings.NewReader(list))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"procedure:rpt.*", "!procedure:rpt.usp_tmp*", `\[dbo\]\.\[Orders\]`}
+
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("have %q, want %q", have, want)
+	}
+}
+
+func TestReadTrailingWhitespace(t *testing.T) {
+	const list = "procedure:rpt.*  \t\r\n\t!procedure:rpt.usp_tmp* \n"
+
+	expressions, err := Read(strings.NewReader(list))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"procedure:rpt.*", "!procedure:rpt.usp_tmp*"}; !reflect.DeepEqual(expressions, want) {
+		t.Errorf("have %q, want %q", expressions, want)
+	}
+
+	f, err := New(expressions)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = f.Match(Object{Type: output.Procedure, Schema: "rpt", Name: "usp_Sales"}); err != nil {
+		t.Errorf("[rpt].[usp_Sales] is not matched: %v", err)
+	}
+
+	if err = f.Match(Object{Type: output.Procedure, Schema: "rpt", Name: "usp_tmp_Sales"}); err != ErrorNotMatched {
+		t.Errorf("[rpt].[usp_tmp_Sales] is matched")
+	}
+}
@@ -0,0 +1,236 @@
+package graph
+
+import (
+	"sort"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+// Node вершина графа зависимостей - объект БД
+type Node struct {
+	// Type тип объекта БД
+	Type output.DatabaseObjectType
+	// Schema схема объекта БД
+	Schema string
+	// Name наименование объекта БД
+	Name string
+}
+
+// String возвращает наименование объекта БД вместе со схемой в квадратных скобках: [schema].[name]
+func (node Node) String() string {
+	switch {
+	case node.Schema != "" && node.Name != "":
+		return "[" + node.Schema + "].[" + node.Name + "]"
+	case node.Schema != "":
+		return "[" + node.Schema + "]"
+	default:
+		return "[" + node.Name + "]"
+	}
+}
+
+func (node Node) less(other Node) bool {
+	if node.Schema != other.Schema {
+		return node.Schema < other.Schema
+	}
+
+	if node.Name != other.Name {
+		return node.Name < other.Name
+	}
+
+	return node.Type < other.Type
+}
+
+// Kind вид зависимости
+type Kind string
+
+const (
+	// KindReference модуль (процедура, функция, представление, триггер) ссылается на объект
+	KindReference Kind = "reference"
+	// KindColumn вычисляемое поле таблицы использует объект (например, функцию)
+	KindColumn Kind = "column"
+	// KindConstraint ограничение CHECK или DEFAULT таблицы использует объект
+	KindConstraint Kind = "constraint"
+	// KindForeignKey внешний ключ таблицы ссылается на таблицу
+	KindForeignKey Kind = "foreign key"
+	// KindType поле или параметр объекта имеет пользовательский тип
+	KindType Kind = "type"
+	// KindTrigger триггер создан для таблицы или представления
+	KindTrigger Kind = "trigger"
+)
+
+// Edge ребро графа зависимостей: объект From зависит от объекта To
+type Edge struct {
+	// From зависимый объект
+	From Node
+	// To объект, от которого зависит From
+	To Node
+	// Kind вид зависимости
+	Kind Kind
+}
+
+// Graph граф зависимостей объектов БД
+type Graph struct {
+	nodes map[Node]bool
+	edges map[Edge]bool
+	// out зависимости объектов
+	out map[Node]map[Node]bool
+	// in объекты, зависящие от объектов
+	in map[Node]map[Node]bool
+}
+
+// New возвращает пустой граф зависимостей
+func New() *Graph {
+	return &Graph{
+		nodes: make(map[Node]bool),
+		edges: make(map[Edge]bool),
+		out:   make(map[Node]map[Node]bool),
+		in:    make(map[Node]map[Node]bool),
+	}
+}
+
+// AddNode добавляет в граф объект node
+func (graph *Graph) AddNode(node Node) {
+	graph.nodes[node] = true
+}
+
+// AddEdge добавляет в граф зависимость объекта from от объекта to. Зависимость объекта от самого себя не
+// добавляется
+func (graph *Graph) AddEdge(from, to Node, kind Kind) {
+	graph.AddNode(from)
+	graph.AddNode(to)
+
+	if from == to {
+		return
+	}
+
+	graph.edges[Edge{From: from, To: to, Kind: kind}] = true
+
+	if graph.out[from] == nil {
+		graph.out[from] = make(map[Node]bool)
+	}
+
+	graph.out[from][to] = true
+
+	if graph.in[to] == nil {
+		graph.in[to] = make(map[Node]bool)
+	}
+
+	graph.in[to][from] = true
+}
+
+// Has проверяет наличие объекта node в графе
+func (graph *Graph) Has(node Node) bool {
+	return graph.nodes[node]
+}
+
+// Nodes возвращает объекты графа, упорядоченные по схеме, наименованию и типу
+func (graph *Graph) Nodes() []Node {
+	nodes := make([]Node, 0, len(graph.nodes))
+
+	for node := range graph.nodes {
+		nodes = append(nodes, node)
+	}
+
+	sortNodes(nodes)
+
+	return nodes
+}
+
+// Edges возвращает зависимости графа, упорядоченные по зависимому объекту, объекту, от которого он зависит, и виду
+// зависимости
+func (graph *Graph) Edges() []Edge {
+	edges := make([]Edge, 0, len(graph.edges))
+
+	for edge := range graph.edges {
+		edges = append(edges, edge)
+	}
+
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From != edges[j].From {
+			return edges[i].From.less(edges[j].From)
+		}
+
+		if edges[i].To != edges[j].To {
+			return edges[i].To.less(edges[j].To)
+		}
+
+		return edges[i].Kind < edges[j].Kind
+	})
+
+	return edges
+}
+
+// Subgraph возвращает граф из объектов nodes, которые есть в графе, и зависимостей между ними
+func (graph *Graph) Subgraph(nodes []Node) *Graph {
+	sub := New()
+
+	for _, node := range nodes {
+		if graph.nodes[node] {
+			sub.AddNode(node)
+		}
+	}
+
+	for edge := range graph.edges {
+		if sub.nodes[edge.From] && sub.nodes[edge.To] {
+			sub.AddEdge(edge.From, edge.To, edge.Kind)
+		}
+	}
+
+	return sub
+}
+
+// Dependencies возвращает объекты roots вместе с объектами, от которых они зависят прямо или косвенно, не далее depth
+// зависимостей от roots. depth <= 0 - без ограничения
+func (graph *Graph) Dependencies(roots []Node, depth int) []Node {
+	return graph.walk(roots, depth, graph.out)
+}
+
+// Dependents возвращает объекты roots вместе с объектами, которые зависят от них прямо или косвенно, не далее depth
+// зависимостей от roots. depth <= 0 - без ограничения
+func (graph *Graph) Dependents(roots []Node, depth int) []Node {
+	return graph.walk(roots, depth, graph.in)
+}
+
+// walk обходит граф в ширину от объектов roots по ребрам adjacency
+func (graph *Graph) walk(roots []Node, depth int, adjacency map[Node]map[Node]bool) []Node {
+	visited := make(map[Node]bool, len(roots))
+	level := make([]Node, 0, len(roots))
+
+	for _, root := range roots {
+		if graph.nodes[root] && !visited[root] {
+			visited[root] = true
+			level = append(level, root)
+		}
+	}
+
+	for distance := 1; len(level) > 0 && (depth <= 0 || distance <= depth); distance++ {
+		next := make([]Node, 0)
+
+		for _, node := range level {
+			for adjacent := range adjacency[node] {
+				if !visited[adjacent] {
+					visited[adjacent] = true
+					next = append(next, adjacent)
+				}
+			}
+		}
+
+		level = next
+	}
+
+	nodes := make([]Node, 0, len(visited))
+
+	for node := range visited {
+		nodes = append(nodes, node)
+	}
+
+	sortNodes(nodes)
+
+	return nodes
+}
+
+func sortNodes(nodes []Node) {
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].less(nodes[j])
+	})
+}
@@ -0,0 +1,135 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+var (
+	orders     = Node{Type: output.Table, Schema: "dbo", Name: "Orders"}
+	customers  = Node{Type: output.Table, Schema: "dbo", Name: "Customers"}
+	phone      = Node{Type: output.UserDefinedDataType, Schema: "dbo", Name: "Phone"}
+	orderTotal = Node{Type: output.Function, Schema: "dbo", Name: "OrderTotal"}
+	orderList  = Node{Type: output.View, Schema: "rpt", Name: "OrderList"}
+	getOrders  = Node{Type: output.Procedure, Schema: "rpt", Name: "GetOrders"}
+	unrelated  = Node{Type: output.Table, Schema: "dbo", Name: "Log"}
+)
+
+func testGraph() *Graph {
+	g := New()
+
+	g.AddNode(unrelated)
+	g.AddEdge(orders, customers, KindForeignKey)
+	g.AddEdge(orders, orders, KindForeignKey)
+	g.AddEdge(customers, phone, KindType)
+	g.AddEdge(orders, orderTotal, KindColumn)
+	g.AddEdge(orderTotal, orders, KindReference)
+	g.AddEdge(orderList, orders, KindReference)
+	g.AddEdge(getOrders, orderList, KindReference)
+	g.AddEdge(getOrders, orderList, KindReference)
+
+	return g
+}
+
+func TestGraph_Dependencies(t *testing.T) {
+	var cases = []struct {
+		roots []Node
+		depth int
+		want  []Node
+	}{
+		{
+			roots: []Node{getOrders},
+			want:  []Node{customers, orderTotal, orders, phone, getOrders, orderList},
+		},
+		{
+			roots: []Node{getOrders},
+			depth: 2,
+			want:  []Node{orders, getOrders, orderList},
+		},
+		{
+			roots: []Node{orderTotal},
+			want:  []Node{customers, orderTotal, orders, phone},
+		},
+		{
+			roots: []Node{unrelated, {Type: output.View, Schema: "dbo", Name: "Missing"}},
+			want:  []Node{unrelated},
+		},
+	}
+
+	g := testGraph()
+
+	for _, test := range cases {
+		if have := g.Dependencies(test.roots, test.depth); !reflect.DeepEqual(have, test.want) {
+			t.Errorf("Dependencies(%v, %d): have %v, want %v", test.roots, test.depth, have, test.want)
+		}
+	}
+}
+
+func TestGraph_Dependents(t *testing.T) {
+	var cases = []struct {
+		roots []Node
+		depth int
+		want  []Node
+	}{
+		{
+			roots: []Node{phone},
+			want:  []Node{customers, orderTotal, orders, phone, getOrders, orderList},
+		},
+		{
+			roots: []Node{phone},
+			depth: 1,
+			want:  []Node{customers, phone},
+		},
+		{
+			roots: []Node{getOrders},
+			want:  []Node{getOrders},
+		},
+	}
+
+	g := testGraph()
+
+	for _, test := range cases {
+		if have := g.Dependents(test.roots, test.depth); !reflect.DeepEqual(have, test.want) {
+			t.Errorf("Dependents(%v, %d): have %v, want %v", test.roots, test.depth, have, test.want)
+		}
+	}
+}
+
+func TestGraph_Edges(t *testing.T) {
+	want := []Edge{
+		{From: customers, To: phone, Kind: KindType},
+		{From: orderTotal, To: orders, Kind: KindReference},
+		{From: orders, To: customers, Kind: KindForeignKey},
+		{From: orders, To: orderTotal, Kind: KindColumn},
+		{From: getOrders, To: orderList, Kind: KindReference},
+		{From: orderList, To: orders, Kind: KindReference},
+	}
+
+	if have := testGraph().Edges(); !reflect.DeepEqual(have, want) {
+		t.Errorf("have %v, want %v", have, want)
+	}
+
+	if have := len(testGraph().Nodes()); have != 7 {
+		t.Errorf("have %d nodes, want 7", have)
+	}
+}
+
+func TestGraph_Subgraph(t *testing.T) {
+	missing := Node{Type: output.View, Schema: "dbo", Name: "Missing"}
+	sub := testGraph().Subgraph([]Node{orders, customers, orderList, missing})
+
+	want := []Edge{
+		{From: orders, To: customers, Kind: KindForeignKey},
+		{From: orderList, To: orders, Kind: KindReference},
+	}
+
+	if have := sub.Edges(); !reflect.DeepEqual(have, want) {
+		t.Errorf("have %v, want %v", have, want)
+	}
+
+	if have := sub.Nodes(); !reflect.DeepEqual(have, []Node{customers, orders, orderList}) {
+		t.Errorf("have nodes %v, want %v", have, []Node{customers, orders, orderList})
+	}
+}
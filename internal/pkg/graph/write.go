@@ -0,0 +1,151 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format формат записи графа зависимостей
+type Format byte
+
+const (
+	// FormatDOT формат Graphviz DOT
+	FormatDOT Format = iota
+	// FormatMermaid диаграмма Mermaid flowchart
+	FormatMermaid
+	// FormatJSON список объектов и зависимостей в формате JSON
+	FormatJSON
+)
+
+// String возвращает наименование формата записи графа
+func (format Format) String() string {
+	switch format {
+	case FormatDOT:
+		return "dot"
+	case FormatMermaid:
+		return "mermaid"
+	case FormatJSON:
+		return "json"
+	default:
+		return "unknown"
+	}
+}
+
+// ID возвращает идентификатор объекта в записи графа: тип и наименование объекта БД, например, table:[dbo].[Orders].
+// Табличный тип и таблица могут называться одинаково, поэтому тип входит в идентификатор
+func (node Node) ID() string {
+	return node.Type.String() + ":" + node.String()
+}
+
+// Write записывает граф в writer в формате format. Объекты и зависимости записываются упорядоченными, чтобы запись
+// одного и того же графа не менялась
+func (graph *Graph) Write(writer io.Writer, format Format) error {
+	switch format {
+	case FormatDOT:
+		return graph.writeDOT(writer)
+	case FormatMermaid:
+		return graph.writeMermaid(writer)
+	case FormatJSON:
+		return graph.writeJSON(writer)
+	default:
+		return fmt.Errorf("unknown graph format %d", format)
+	}
+}
+
+func (graph *Graph) writeDOT(writer io.Writer) error {
+	w := bufio.NewWriter(writer)
+
+	fmt.Fprintln(w, "digraph dependencies {")
+	fmt.Fprintln(w, "    rankdir=LR;")
+	fmt.Fprintln(w, "    node [shape=box];")
+
+	for _, node := range graph.Nodes() {
+		fmt.Fprintf(w, "    %s [label=%s];\n", dotQuote(node.ID()), dotQuote(node.String()+"\n"+node.Type.String()))
+	}
+
+	for _, edge := range graph.Edges() {
+		fmt.Fprintf(w, "    %s -> %s [label=%s];\n", dotQuote(edge.From.ID()), dotQuote(edge.To.ID()),
+			dotQuote(string(edge.Kind)))
+	}
+
+	fmt.Fprintln(w, "}")
+
+	return w.Flush()
+}
+
+// dotQuote возвращает строку s в кавычках DOT
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+
+	return `"` + s + `"`
+}
+
+func (graph *Graph) writeMermaid(writer io.Writer) error {
+	w := bufio.NewWriter(writer)
+
+	fmt.Fprintln(w, "flowchart LR")
+
+	// идентификаторы вершин Mermaid не могут содержать скобки и пробелы, поэтому вершины нумеруются
+	ids := make(map[Node]string, len(graph.nodes))
+
+	for i, node := range graph.Nodes() {
+		ids[node] = fmt.Sprintf("n%d", i)
+
+		fmt.Fprintf(w, "    %s[\"%s<br/>%s\"]\n", ids[node], mermaidEscape(node.String()), node.Type.String())
+	}
+
+	for _, edge := range graph.Edges() {
+		fmt.Fprintf(w, "    %s -->|%s| %s\n", ids[edge.From], edge.Kind, ids[edge.To])
+	}
+
+	return w.Flush()
+}
+
+// mermaidEscape заменяет символы, которые нельзя использовать в тексте вершины Mermaid, их кодами
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+type jsonNode struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+}
+
+type jsonEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind Kind   `json:"kind"`
+}
+
+type jsonGraph struct {
+	Nodes []jsonNode `json:"nodes"`
+	Edges []jsonEdge `json:"edges"`
+}
+
+func (graph *Graph) writeJSON(writer io.Writer) error {
+	data := jsonGraph{
+		Nodes: make([]jsonNode, 0, len(graph.nodes)),
+		Edges: make([]jsonEdge, 0, len(graph.edges)),
+	}
+
+	for _, node := range graph.Nodes() {
+		data.Nodes = append(data.Nodes, jsonNode{ID: node.ID(), Type: node.Type.String(), Schema: node.Schema,
+			Name: node.Name})
+	}
+
+	for _, edge := range graph.Edges() {
+		data.Edges = append(data.Edges, jsonEdge{From: edge.From.ID(), To: edge.To.ID(), Kind: edge.Kind})
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(data)
+}
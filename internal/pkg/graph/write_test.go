@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+func TestGraph_Write(t *testing.T) {
+	g := New()
+
+	g.AddEdge(orders, customers, KindForeignKey)
+	g.AddEdge(orderList, orders, KindReference)
+	g.AddNode(Node{Type: output.Table, Schema: "dbo", Name: `Say "Hi"`})
+
+	var cases = []struct {
+		format Format
+		want   string
+	}{
+		{
+			format: FormatDOT,
+			want: `digraph dependencies {
+    rankdir=LR;
+    node [shape=box];
+    "table:[dbo].[Customers]" [label="[dbo].[Customers]\ntable"];
+    "table:[dbo].[Orders]" [label="[dbo].[Orders]\ntable"];
+    "table:[dbo].[Say \"Hi\"]" [label="[dbo].[Say \"Hi\"]\ntable"];
+    "view:[rpt].[OrderList]" [label="[rpt].[OrderList]\nview"];
+    "table:[dbo].[Orders]" -> "table:[dbo].[Customers]" [label="foreign key"];
+    "view:[rpt].[OrderList]" -> "table:[dbo].[Orders]" [label="reference"];
+}
+`,
+		},
+		{
+			format: FormatMermaid,
+			want: `flowchart LR
+    n0["[dbo].[Customers]<br/>table"]
+    n1["[dbo].[Orders]<br/>table"]
+    n2["[dbo].[Say #quot;Hi#quot;]<br/>table"]
+    n3["[rpt].[OrderList]<br/>view"]
+    n1 -->|foreign key| n0
+    n3 -->|reference| n1
+`,
+		},
+		{
+			format: FormatJSON,
+			want: `{
+  "nodes": [
+    {
+      "id": "table:[dbo].[Customers]",
+      "type": "table",
+      "schema": "dbo",
+      "name": "Customers"
+    },
+    {
+      "id": "table:[dbo].[Orders]",
+      "type": "table",
+      "schema": "dbo",
+      "name": "Orders"
+    },
+    {
+      "id": "table:[dbo].[Say \"Hi\"]",
+      "type": "table",
+      "schema": "dbo",
+      "name": "Say \"Hi\""
+    },
+    {
+      "id": "view:[rpt].[OrderList]",
+      "type": "view",
+      "schema": "rpt",
+      "name": "OrderList"
+    }
+  ],
+  "edges": [
+    {
+      "from": "table:[dbo].[Orders]",
+      "to": "table:[dbo].[Customers]",
+      "kind": "foreign key"
+    },
+    {
+      "from": "view:[rpt].[OrderList]",
+      "to": "table:[dbo].[Orders]",
+      "kind": "reference"
+    }
+  ]
+}
+`,
+		},
+	}
+
+	for _, test := range cases {
+		var buf bytes.Buffer
+
+		if err := g.Write(&buf, test.format); err != nil {
+			t.Fatal(err)
+		}
+
+		if have := buf.String(); have != test.want {
+			t.Errorf("%s: have\n%s\nwant\n%s", test.format, have, test.want)
+		}
+	}
+}
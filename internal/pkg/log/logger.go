@@ -1,6 +1,7 @@
 package log
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/sirupsen/logrus"
@@ -35,12 +36,33 @@ type ILogger interface {
 	Print(level Level, args ...interface{})
 	// Printf записывает в лог форматированное сообщение
 	Printf(level Level, format string, args ...interface{})
+	// WithFields возвращает логгер, добавляющий к сообщениям поля fields
+	WithFields(fields Fields) ILogger
 }
 
-// Logger логгер
+// Fields структурированные поля сообщения лога
+type Fields map[string]interface{}
+
+// Наименования полей сообщений лога о работе с объектами БД
+const (
+	// FieldObjectType тип объекта БД
+	FieldObjectType = "object_type"
+	// FieldSchema схема объекта БД
+	FieldSchema = "schema"
+	// FieldName наименование объекта БД
+	FieldName = "name"
+	// FieldPhase этап выполнения команды
+	FieldPhase = "phase"
+	// FieldDuration длительность этапа или обработки объекта в секундах
+	FieldDuration = "duration"
+)
+
+// Logger логгер. Секреты в сообщениях маскируются (см. Redactor)
 type Logger struct {
-	output io.Writer
-	logger *logrus.Logger
+	output   io.Writer
+	logger   *logrus.Logger
+	redactor *Redactor
+	fields   Fields
 }
 
 // Option тип параметра логгера
@@ -49,8 +71,9 @@ type Option func(logger *Logger)
 // New возвращает экземпляр логгера
 func New(options ...Option) *Logger {
 	logger := &Logger{
-		output: nil,
-		logger: logrus.New(),
+		output:   nil,
+		logger:   logrus.New(),
+		redactor: secrets,
 	}
 
 	for _, option := range options {
@@ -98,28 +121,45 @@ func WithOutput(output io.Writer) Option {
 	}
 }
 
+// WithRedactor устанавливает маскирование секретов в сообщениях. По умолчанию маскируются секреты приложения
+// (см. AddSecret)
+func WithRedactor(redactor *Redactor) Option {
+	return func(logger *Logger) {
+		logger.redactor = redactor
+	}
+}
+
+// WithFields возвращает логгер, добавляющий к сообщениям поля fields вместе с полями логгера
+func (logger *Logger) WithFields(fields Fields) ILogger {
+	merged := make(Fields, len(logger.fields)+len(fields))
+
+	for name, value := range logger.fields {
+		merged[name] = value
+	}
+
+	for name, value := range fields {
+		merged[name] = value
+	}
+
+	return &Logger{
+		output:   logger.output,
+		logger:   logger.logger,
+		redactor: logger.redactor,
+		fields:   merged,
+	}
+}
+
 // Print записывает в лог сообщение
 func (logger *Logger) Print(level Level, args ...interface{}) {
 	if logger.output == nil {
 		return
 	}
 
-	switch level {
-	case TraceLevel:
-		logger.logger.Trace(args...)
-	case DebugLevel:
-		logger.logger.Debug(args...)
-	case InfoLevel:
-		logger.logger.Info(args...)
-	case WarningLevel:
-		logger.logger.Warn(args...)
-	case ErrorLevel:
-		logger.logger.Error(args...)
-	case FatalLevel:
-		logger.logger.Fatal(args...)
-	case PanicLevel:
-		logger.logger.Panic(args...)
+	if l, ok := levelMap[level]; !ok || !logger.logger.IsLevelEnabled(l) {
+		return
 	}
+
+	logger.print(level, logger.redactor.Redact(fmt.Sprint(args...)))
 }
 
 // Printf записывает в лог форматированное сообщение
@@ -128,22 +168,56 @@ func (logger *Logger) Printf(level Level, format string, args ...interface{}) {
 		return
 	}
 
+	if l, ok := levelMap[level]; !ok || !logger.logger.IsLevelEnabled(l) {
+		return
+	}
+
+	logger.print(level, logger.redactor.Redact(fmt.Sprintf(format, args...)))
+}
+
+func (logger *Logger) print(level Level, message string) {
+	entry := logrus.NewEntry(logger.logger)
+
+	if len(logger.fields) > 0 {
+		entry = entry.WithFields(logger.redactFields())
+	}
+
 	switch level {
 	case TraceLevel:
-		logger.logger.Tracef(format, args...)
+		entry.Trace(message)
 	case DebugLevel:
-		logger.logger.Debugf(format, args...)
+		entry.Debug(message)
 	case InfoLevel:
-		logger.logger.Infof(format, args...)
+		entry.Info(message)
 	case WarningLevel:
-		logger.logger.Warnf(format, args...)
+		entry.Warn(message)
 	case ErrorLevel:
-		logger.logger.Errorf(format, args...)
+		entry.Error(message)
 	case FatalLevel:
-		logger.logger.Fatalf(format, args...)
+		entry.Fatal(message)
 	case PanicLevel:
-		logger.logger.Panicf(format, args...)
+		entry.Panic(message)
+	}
+}
+
+// redactFields возвращает поля логгера с замаскированными секретами в строковых значениях и ошибках
+func (logger *Logger) redactFields() logrus.Fields {
+	fields := make(logrus.Fields, len(logger.fields))
+
+	for name, value := range logger.fields {
+		switch v := value.(type) {
+		case string:
+			fields[name] = logger.redactor.Redact(v)
+		case error:
+			fields[name] = logger.redactor.Redact(v.Error())
+		case fmt.Stringer:
+			fields[name] = logger.redactor.Redact(v.String())
+		default:
+			fields[name] = v
+		}
 	}
+
+	return fields
 }
 
 var levelMap = map[Level]logrus.Level{
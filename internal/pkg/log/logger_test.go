@@ -0,0 +1,134 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLogger_redaction(t *testing.T) {
+	const password = `Sup3r"se\CReT`
+	const token = "eyJhbGciOiJIUzI1NiJ9.access"
+
+	connection := "sqlserver://sa:" + password + "@localhost?database=Orders&access_token=" + token
+
+	levels := []Level{TraceLevel, DebugLevel, InfoLevel, WarningLevel, ErrorLevel, FatalLevel, PanicLevel}
+
+	for _, format := range []Format{Text, JSON} {
+		for _, level := range levels {
+			var buf bytes.Buffer
+
+			logger := New(WithLevel(TraceLevel), WithFormat(format), WithOutput(&buf),
+				WithRedactor(NewRedactor(password, token)))
+			logger.logger.ExitFunc = func(int) {}
+
+			write := func(print func()) {
+				defer func() {
+					_ = recover()
+				}()
+
+				print()
+			}
+
+			write(func() { logger.Print(level, "connecting to ", connection) })
+			write(func() { logger.Printf(level, "login failed for %s with password %s", "sa", password) })
+			write(func() { logger.Printf(level, "token %q", token) })
+
+			out := buf.String()
+
+			if strings.Count(out, "\n") != 3 {
+				t.Errorf("format %d, level %d: have %d entries, want 3:\n%s", format, level,
+					strings.Count(out, "\n"), out)
+			}
+
+			for _, secret := range []string{password, `Sup3r\"se\\CReT`, token, "Sup3r", "CReT"} {
+				if strings.Contains(out, secret) {
+					t.Errorf("format %d, level %d: the log contains the secret %q:\n%s", format, level, secret, out)
+				}
+			}
+		}
+	}
+}
+
+func TestLogger_level(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := New(WithLevel(WarningLevel), WithOutput(&buf))
+
+	logger.Print(InfoLevel, "metadata reading...")
+	logger.Printf(DebugLevel, "%s skipped", "[dbo].[Orders]")
+
+	if buf.Len() != 0 {
+		t.Errorf("entries below the log level are written: %s", buf.String())
+	}
+
+	logger.Print(WarningLevel, "[dbo].[Orders] cannot be scripted")
+
+	if !strings.Contains(buf.String(), "[dbo].[Orders] cannot be scripted") {
+		t.Errorf("the warning is not written: %s", buf.String())
+	}
+}
+
+func TestLogger_WithFields(t *testing.T) {
+	const password = "Sup3rSecret"
+
+	var buf bytes.Buffer
+
+	logger := New(WithLevel(DebugLevel), WithFormat(JSON), WithOutput(&buf),
+		WithRedactor(NewRedactor(password)))
+
+	objectLogger := logger.WithFields(Fields{
+		FieldObjectType: "table",
+		FieldSchema:     "dbo",
+		FieldName:       "Orders",
+	})
+
+	objectLogger.WithFields(Fields{
+		FieldPhase:    "writing",
+		FieldDuration: 1.5,
+		"error":       errors.New("login failed with password " + password),
+	}).Print(DebugLevel, "[dbo].[Orders]")
+
+	logger.Print(DebugLevel, "done")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+
+	if len(lines) != 2 {
+		t.Fatalf("have %d entries, want 2:\n%s", len(lines), buf.String())
+	}
+
+	var entry map[string]interface{}
+
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]interface{}{
+		"msg":           "[dbo].[Orders]",
+		"level":         "debug",
+		FieldObjectType: "table",
+		FieldSchema:     "dbo",
+		FieldName:       "Orders",
+		FieldPhase:      "writing",
+		FieldDuration:   1.5,
+		"error":         "login failed with password " + Mask,
+	}
+
+	for name, value := range want {
+		if entry[name] != value {
+			t.Errorf("field %s: have %v, want %v", name, entry[name], value)
+		}
+	}
+
+	var parentEntry map[string]interface{}
+
+	if err := json.Unmarshal([]byte(lines[1]), &parentEntry); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := parentEntry[FieldSchema]; ok {
+		t.Errorf("fields of the derived logger are written by the parent logger: %s", lines[1])
+	}
+}
@@ -0,0 +1,68 @@
+package output
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// AtomicFile файл, который появляется по указанному пути только после успешного завершения записи. До этого данные
+// записываются во временный файл в том же каталоге, поэтому прерванная запись не оставляет усеченных файлов
+type AtomicFile struct {
+	*os.File
+
+	path string
+}
+
+// CreateAtomic создает временный файл для записи файла path
+func CreateAtomic(path string) (*AtomicFile, error) {
+	file, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &AtomicFile{File: file, path: path}, nil
+}
+
+// Commit закрывает временный файл и переименовывает его в файл path, заменяя существующий файл
+func (file *AtomicFile) Commit() error {
+	if err := file.Chmod(0664); err != nil {
+		file.Abort()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	if err := os.Rename(file.Name(), file.path); err != nil {
+		os.Remove(file.Name())
+		return err
+	}
+
+	return nil
+}
+
+// Abort закрывает и удаляет временный файл. Файл path не изменяется
+func (file *AtomicFile) Abort() error {
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// WriteFileAtomic записывает data в файл path через временный файл
+func WriteFileAtomic(path string, data []byte) error {
+	file, err := CreateAtomic(path)
+
+	if err != nil {
+		return err
+	}
+
+	if _, err = file.Write(data); err != nil {
+		file.Abort()
+		return err
+	}
+
+	return file.Commit()
+}
@@ -0,0 +1,66 @@
+package output
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAtomicFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbmill-atomic")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "dbo.GetOrders.sql")
+
+	if err = WriteFileAtomic(path, []byte("CREATE PROCEDURE")); err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := CreateAtomic(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = file.Write([]byte("CREATE OR ALTER")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = file.Abort(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "CREATE PROCEDURE" {
+		t.Errorf("AtomicFile.Abort failed: file content is %q, want %q", data, "CREATE PROCEDURE")
+	}
+
+	if err = WriteFileAtomic(path, []byte("CREATE OR ALTER PROCEDURE")); err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ = ioutil.ReadFile(path); string(data) != "CREATE OR ALTER PROCEDURE" {
+		t.Errorf("WriteFileAtomic failed: file content is %q, want %q", data, "CREATE OR ALTER PROCEDURE")
+	}
+
+	files, err := ioutil.ReadDir(dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 1 {
+		t.Errorf("AtomicFile failed: temporary files left in the directory: %d files", len(files))
+	}
+}
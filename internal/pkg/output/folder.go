@@ -0,0 +1,273 @@
+package output
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// FileStatus результат записи файла скрипта
+type FileStatus byte
+
+const (
+	// FileUnchanged содержимое файла не изменилось, файл не перезаписывался
+	FileUnchanged FileStatus = iota
+	// FileCreated файл создан
+	FileCreated
+	// FileUpdated файл перезаписан
+	FileUpdated
+	// FileDeleted файл удален
+	FileDeleted
+)
+
+// String возвращает строковое представление результата записи файла
+func (status FileStatus) String() string {
+	switch status {
+	case FileCreated:
+		return "created"
+	case FileUpdated:
+		return "updated"
+	case FileDeleted:
+		return "deleted"
+	default:
+		return "unchanged"
+	}
+}
+
+// Summary сводка изменений в каталоге скриптов
+type Summary struct {
+	// Created количество созданных файлов
+	Created int
+	// Updated количество перезаписанных файлов
+	Updated int
+	// Deleted количество удаленных файлов
+	Deleted int
+	// Unchanged количество файлов, содержимое которых не изменилось
+	Unchanged int
+}
+
+// String возвращает строковое представление сводки
+func (summary Summary) String() string {
+	return fmt.Sprintf("created: %d, updated: %d, deleted: %d, unchanged: %d", summary.Created, summary.Updated,
+		summary.Deleted, summary.Unchanged)
+}
+
+func (summary *Summary) add(status FileStatus) {
+	switch status {
+	case FileCreated:
+		summary.Created++
+	case FileUpdated:
+		summary.Updated++
+	case FileDeleted:
+		summary.Deleted++
+	default:
+		summary.Unchanged++
+	}
+}
+
+// Folder каталог скриптов. Перезаписывает только файлы с изменившимся содержимым и запоминает записанные файлы,
+// чтобы можно было удалить устаревшие скрипты
+type Folder struct {
+	path    string
+	mutex   sync.Mutex
+	written map[string]bool
+	files   []FolderFile
+	summary Summary
+}
+
+// FolderFile файл каталога скриптов, записанный, оставленный без изменений или удаленный при создании скриптов
+type FolderFile struct {
+	// Path путь к файлу относительно каталога скриптов
+	Path string
+	// Status результат записи файла
+	Status FileStatus
+	// SHA256 хэш содержимого файла (для удаленных файлов - пустая строка)
+	SHA256 string
+}
+
+// NewFolder конструктор Folder
+func NewFolder(path string) *Folder {
+	return &Folder{
+		path:    path,
+		written: make(map[string]bool),
+	}
+}
+
+// Write записывает скрипт data в файл filename подкаталога subdirectory. Если файл существует и его содержимое
+// совпадает с data, то файл не перезаписывается
+func (folder *Folder) Write(subdirectory, filename string, data []byte) (FileStatus, error) {
+	dir := filepath.Join(folder.path, subdirectory)
+	path := filepath.Join(dir, filename)
+
+	folder.mutex.Lock()
+	folder.written[filepath.Clean(path)] = true
+	folder.mutex.Unlock()
+
+	status, err := writeFile(dir, path, data)
+
+	if err != nil {
+		return status, err
+	}
+
+	hash := sha256.Sum256(data)
+
+	folder.mutex.Lock()
+	defer folder.mutex.Unlock()
+
+	folder.summary.add(status)
+	folder.files = append(folder.files, FolderFile{
+		Path:   folder.relative(path),
+		Status: status,
+		SHA256: hex.EncodeToString(hash[:]),
+	})
+
+	return status, nil
+}
+
+// Keep отмечает файл filename подкаталога subdirectory как относящийся к объекту БД, чтобы он не был удален Prune.
+// Используется для объектов БД, скрипты которых не созданы, но объекты существуют (например, зашифрованных)
+func (folder *Folder) Keep(subdirectory, filename string) {
+	folder.mutex.Lock()
+	defer folder.mutex.Unlock()
+
+	folder.written[filepath.Clean(filepath.Join(folder.path, subdirectory, filename))] = true
+}
+
+// writeFile записывает data в файл path каталога dir, если содержимое файла отличается от data
+func writeFile(dir, path string, data []byte) (FileStatus, error) {
+	status := FileCreated
+
+	current, err := ioutil.ReadFile(path)
+
+	switch {
+	case err == nil:
+		if bytes.Equal(current, data) {
+			return FileUnchanged, nil
+		}
+
+		status = FileUpdated
+	case !os.IsNotExist(err):
+		return status, err
+	}
+
+	if err = os.MkdirAll(dir, os.ModePerm); err != nil {
+		return status, err
+	}
+
+	if err = WriteFileAtomic(path, data); err != nil {
+		return status, err
+	}
+
+	return status, nil
+}
+
+// Prune удаляет из подкаталогов, указанных в описании структуры rules для типов объектов types, файлы, которые
+// соответствуют маске имени файла, но не были записаны. Возвращает список удаленных файлов
+func (folder *Folder) Prune(rules IScriptsFolderOutput, types []DatabaseObjectType) ([]string, error) {
+	folder.mutex.Lock()
+	defer folder.mutex.Unlock()
+
+	deleted := make([]string, 0)
+
+	for _, objectType := range types {
+		subdirectory, mask, ok := rules.Rules(objectType)
+
+		if !ok {
+			continue
+		}
+
+		matches, err := filepath.Glob(filepath.Join(folder.path, maskPattern(subdirectory), maskPattern(mask)))
+
+		if err != nil {
+			return deleted, err
+		}
+
+		sort.Strings(matches)
+
+		for _, path := range matches {
+			path = filepath.Clean(path)
+
+			if folder.written[path] {
+				continue
+			}
+
+			if info, err := os.Stat(path); err != nil || info.IsDir() {
+				continue
+			}
+
+			if err = os.Remove(path); err != nil {
+				return deleted, err
+			}
+
+			folder.written[path] = true
+			folder.summary.add(FileDeleted)
+			folder.files = append(folder.files, FolderFile{Path: folder.relative(path), Status: FileDeleted})
+
+			deleted = append(deleted, path)
+		}
+	}
+
+	return deleted, nil
+}
+
+// Summary возвращает сводку изменений в каталоге скриптов
+func (folder *Folder) Summary() Summary {
+	folder.mutex.Lock()
+	defer folder.mutex.Unlock()
+
+	return folder.summary
+}
+
+// Files возвращает файлы, записанные, оставленные без изменений или удаленные при создании скриптов, упорядоченные по
+// пути
+func (folder *Folder) Files() []FolderFile {
+	folder.mutex.Lock()
+	defer folder.mutex.Unlock()
+
+	files := make([]FolderFile, len(folder.files))
+	copy(files, folder.files)
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Path < files[j].Path
+	})
+
+	return files
+}
+
+func (folder *Folder) relative(path string) string {
+	if relative, err := filepath.Rel(folder.path, path); err == nil {
+		return filepath.ToSlash(relative)
+	}
+
+	return filepath.ToSlash(path)
+}
+
+// maskPattern возвращает шаблон filepath.Match, соответствующий маске имени файла mask. Элементы подстановки
+// заменяются на *, остальные символы экранируются
+func maskPattern(mask string) string {
+	literals := placeholderPattern.Split(mask, -1)
+
+	for index, literal := range literals {
+		var builder strings.Builder
+
+		for _, r := range literal {
+			switch r {
+			case '*', '?', '[', ']', '\\':
+				builder.WriteRune('\\')
+			}
+
+			builder.WriteRune(r)
+		}
+
+		literals[index] = builder.String()
+	}
+
+	return strings.Join(literals, "*")
+}
@@ -0,0 +1,154 @@
+package output
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFolder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbmill-folder")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	rules, err := NewScriptsFolderOutput(strings.NewReader(`
+procedure:
+  subdirectory: Procedures
+  mask: $schema$.$object$.sql
+`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"dbo.Unchanged.sql": "CREATE PROCEDURE [dbo].[Unchanged] AS RETURN\nGO",
+		"dbo.Updated.sql":   "CREATE PROCEDURE [dbo].[Updated] AS RETURN\nGO",
+		"dbo.Dropped.sql":   "CREATE PROCEDURE [dbo].[Dropped] AS RETURN\nGO",
+		"dbo.Encrypted.sql": "CREATE PROCEDURE [dbo].[Encrypted] AS RETURN\nGO",
+		"readme.txt":        "not a script",
+	}
+
+	if err = os.MkdirAll(filepath.Join(dir, "Procedures"), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range files {
+		if err = ioutil.WriteFile(filepath.Join(dir, "Procedures", name), []byte(content), 0664); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	folder := NewFolder(dir)
+
+	var cases = []struct {
+		filename string
+		data     string
+		want     FileStatus
+	}{
+		{
+			filename: "dbo.Unchanged.sql",
+			data:     files["dbo.Unchanged.sql"],
+			want:     FileUnchanged,
+		},
+		{
+			filename: "dbo.Updated.sql",
+			data:     "CREATE PROCEDURE [dbo].[Updated] AS SELECT 1 AS [one]\nGO",
+			want:     FileUpdated,
+		},
+		{
+			filename: "dbo.Created.sql",
+			data:     "CREATE PROCEDURE [dbo].[Created] AS RETURN\nGO",
+			want:     FileCreated,
+		},
+	}
+
+	for _, test := range cases {
+		status, err := folder.Write("Procedures", test.filename, []byte(test.data))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if status != test.want {
+			t.Errorf("Write(%s) failed: have %s, want %s", test.filename, status, test.want)
+		}
+
+		data, err := ioutil.ReadFile(filepath.Join(dir, "Procedures", test.filename))
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != test.data {
+			t.Errorf("Write(%s) failed: have %q, want %q", test.filename, data, test.data)
+		}
+	}
+
+	// скрипт существующего объекта, который не удалось создать, не удаляется
+	folder.Keep("Procedures", "dbo.Encrypted.sql")
+
+	deleted, err := folder.Prune(rules, rules.DatabaseObjects())
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(deleted) != 1 || filepath.Base(deleted[0]) != "dbo.Dropped.sql" {
+		t.Errorf("Prune failed: %v", deleted)
+	}
+
+	if _, err = os.Stat(filepath.Join(dir, "Procedures", "readme.txt")); err != nil {
+		t.Errorf("Prune must not delete files that do not match the mask: %v", err)
+	}
+
+	if _, err = os.Stat(filepath.Join(dir, "Procedures", "dbo.Encrypted.sql")); err != nil {
+		t.Errorf("Prune must not delete kept files: %v", err)
+	}
+
+	want := Summary{Created: 1, Updated: 1, Deleted: 1, Unchanged: 1}
+
+	if summary := folder.Summary(); summary != want {
+		t.Errorf("Summary failed: have %v, want %v", summary, want)
+	}
+
+	wantFiles := []string{
+		"Procedures/dbo.Created.sql created",
+		"Procedures/dbo.Dropped.sql deleted",
+		"Procedures/dbo.Unchanged.sql unchanged",
+		"Procedures/dbo.Updated.sql updated",
+	}
+
+	for index, file := range folder.Files() {
+		if have := file.Path + " " + file.Status.String(); index >= len(wantFiles) || have != wantFiles[index] {
+			t.Errorf("Files failed: file %d: have %s", index, have)
+		}
+
+		if (file.Status == FileDeleted) != (file.SHA256 == "") {
+			t.Errorf("Files failed: %s: unexpected hash %q", file.Path, file.SHA256)
+		}
+	}
+}
+
+func TestMaskPattern(t *testing.T) {
+	var cases = []struct {
+		mask string
+		want string
+	}{
+		{mask: "$schema$.$object$.sql", want: "*.*.sql"},
+		{mask: "$schema$.$object$.Data.sql", want: "*.*.Data.sql"},
+		{mask: "[$object$].sql", want: `\[*\].sql`},
+	}
+
+	for _, test := range cases {
+		if have := maskPattern(test.mask); have != test.want {
+			t.Errorf("maskPattern(%s) failed: have %s, want %s", test.mask, have, test.want)
+		}
+	}
+}
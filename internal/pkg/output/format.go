@@ -0,0 +1,156 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	// EncodingUTF8 кодировка UTF-8 (по умолчанию)
+	EncodingUTF8 = "utf-8"
+	// EncodingUTF16LE кодировка UTF-16 little-endian
+	EncodingUTF16LE = "utf-16le"
+	// EncodingUTF16BE кодировка UTF-16 big-endian
+	EncodingUTF16BE = "utf-16be"
+)
+
+const (
+	// LineEndingKeep окончания строк не изменяются (по умолчанию)
+	LineEndingKeep = ""
+	// LineEndingLF окончания строк \n
+	LineEndingLF = "lf"
+	// LineEndingCRLF окончания строк \r\n
+	LineEndingCRLF = "crlf"
+)
+
+// FileFormat параметры записи файлов скриптов
+type FileFormat struct {
+	// Encoding кодировка: utf-8 (по умолчанию), utf-16le, utf-16be
+	Encoding string `yaml:"encoding,omitempty"`
+	// BOM записывать в начало файла маркер последовательности байтов
+	BOM *bool `yaml:"bom,omitempty"`
+	// LineEnding окончания строк: lf, crlf. Если не указано, то окончания строк не изменяются
+	LineEnding string `yaml:"line-ending,omitempty"`
+	// TrailingNewline завершать файл переводом строки (true) или удалять переводы строк в конце файла (false).
+	// Если не указано, то конец файла не изменяется
+	TrailingNewline *bool `yaml:"trailing-newline,omitempty"`
+	// Escape схема экранирования наименований объектов БД в именах файлов: percent (по умолчанию), none
+	Escape string `yaml:"escape,omitempty"`
+}
+
+// merge возвращает параметры записи, в которых неуказанные значения заменены значениями defaults
+func (format FileFormat) merge(defaults FileFormat) FileFormat {
+	if format.Encoding == "" {
+		format.Encoding = defaults.Encoding
+	}
+
+	if format.BOM == nil {
+		format.BOM = defaults.BOM
+	}
+
+	if format.LineEnding == "" {
+		format.LineEnding = defaults.LineEnding
+	}
+
+	if format.TrailingNewline == nil {
+		format.TrailingNewline = defaults.TrailingNewline
+	}
+
+	if format.Escape == "" {
+		format.Escape = defaults.Escape
+	}
+
+	return format
+}
+
+// Validate проверяет параметры записи файлов скриптов
+func (format FileFormat) Validate() error {
+	switch strings.ToLower(format.Encoding) {
+	case "", EncodingUTF8, EncodingUTF16LE, EncodingUTF16BE:
+	default:
+		return fmt.Errorf("unsupported encoding %s", format.Encoding)
+	}
+
+	switch strings.ToLower(format.LineEnding) {
+	case LineEndingKeep, LineEndingLF, LineEndingCRLF:
+	default:
+		return fmt.Errorf("unsupported line ending %s", format.LineEnding)
+	}
+
+	switch strings.ToLower(format.Escape) {
+	case "", EscapePercent, EscapeNone:
+	default:
+		return fmt.Errorf("unsupported escape scheme %s", format.Escape)
+	}
+
+	return nil
+}
+
+// Encode возвращает текст скрипта data в формате записи файла
+func (format FileFormat) Encode(data []byte) []byte {
+	return format.encode(format.Text(data), format.BOM != nil && *format.BOM)
+}
+
+// Text возвращает текст скрипта data с обработанными окончаниями строк и концом файла (без перекодирования)
+func (format FileFormat) Text(data []byte) []byte {
+	if format.TrailingNewline != nil {
+		data = bytes.TrimRight(data, "\r\n")
+
+		if *format.TrailingNewline {
+			data = append(data, '\n')
+		}
+	}
+
+	switch strings.ToLower(format.LineEnding) {
+	case LineEndingLF:
+		data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	case LineEndingCRLF:
+		data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+		data = bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))
+	}
+
+	return data
+}
+
+// encode перекодирует текст data из UTF-8 в кодировку записи файла. Если bom = true, то в начало текста добавляется
+// маркер последовательности байтов
+func (format FileFormat) encode(data []byte, bom bool) []byte {
+	var order binary.ByteOrder
+
+	switch strings.ToLower(format.Encoding) {
+	case EncodingUTF16LE:
+		order = binary.LittleEndian
+	case EncodingUTF16BE:
+		order = binary.BigEndian
+	default:
+		if bom {
+			return append([]byte{0xEF, 0xBB, 0xBF}, data...)
+		}
+
+		return data
+	}
+
+	units := utf16.Encode([]rune(string(data)))
+	out := make([]byte, 0, (len(units)+1)*2)
+
+	if bom {
+		out = appendUint16(out, order, 0xFEFF)
+	}
+
+	for _, unit := range units {
+		out = appendUint16(out, order, unit)
+	}
+
+	return out
+}
+
+func appendUint16(data []byte, order binary.ByteOrder, value uint16) []byte {
+	var buffer [2]byte
+
+	order.PutUint16(buffer[:], value)
+
+	return append(data, buffer[:]...)
+}
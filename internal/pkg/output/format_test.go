@@ -0,0 +1,104 @@
+package output
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func boolPtr(value bool) *bool {
+	return &value
+}
+
+func TestFileFormat_Encode(t *testing.T) {
+	var cases = []struct {
+		format FileFormat
+		data   string
+		want   []byte
+	}{
+		{
+			format: FileFormat{},
+			data:   "GO\n",
+			want:   []byte("GO\n"),
+		},
+		{
+			format: FileFormat{LineEnding: LineEndingCRLF, TrailingNewline: boolPtr(true)},
+			data:   "SELECT 1\nGO",
+			want:   []byte("SELECT 1\r\nGO\r\n"),
+		},
+		{
+			format: FileFormat{LineEnding: LineEndingLF, TrailingNewline: boolPtr(false)},
+			data:   "SELECT 1\r\nGO\r\n\r\n",
+			want:   []byte("SELECT 1\nGO"),
+		},
+		{
+			format: FileFormat{Encoding: EncodingUTF8, BOM: boolPtr(true)},
+			data:   "GO",
+			want:   []byte{0xEF, 0xBB, 0xBF, 'G', 'O'},
+		},
+		{
+			format: FileFormat{Encoding: EncodingUTF16LE, BOM: boolPtr(true)},
+			data:   "GÖ",
+			want:   []byte{0xFF, 0xFE, 'G', 0x00, 0xD6, 0x00},
+		},
+		{
+			format: FileFormat{Encoding: EncodingUTF16BE},
+			data:   "Я",
+			want:   []byte{0x04, 0x2F},
+		},
+	}
+
+	for _, test := range cases {
+		if have := test.format.Encode([]byte(test.data)); !bytes.Equal(have, test.want) {
+			t.Errorf("Encode(%q) failed: have %v, want %v", test.data, have, test.want)
+		}
+	}
+}
+
+func TestScriptsFolderOutput_Format(t *testing.T) {
+	rules, err := NewScriptsFolderOutput(strings.NewReader(`
+defaults:
+  encoding: utf-16le
+  bom: true
+  line-ending: crlf
+procedure:
+  subdirectory: Procedures
+  mask: $schema$.$object$.sql
+  encoding: utf-8
+  bom: false
+view:
+  subdirectory: Views
+  mask: $schema$.$object$.sql
+`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if objects := rules.DatabaseObjects(); len(objects) != 2 {
+		t.Fatalf("DatabaseObjects failed: %v", objects)
+	}
+
+	procedure := rules.Format(Procedure)
+
+	if procedure.Encoding != EncodingUTF8 || *procedure.BOM || procedure.LineEnding != LineEndingCRLF {
+		t.Errorf("Format(procedure) failed: %+v", procedure)
+	}
+
+	view := rules.Format(View)
+
+	if view.Encoding != EncodingUTF16LE || !*view.BOM || view.LineEnding != LineEndingCRLF {
+		t.Errorf("Format(view) failed: %+v", view)
+	}
+
+	_, err = NewScriptsFolderOutput(strings.NewReader(`
+view:
+  subdirectory: Views
+  mask: $schema$.$object$.sql
+  encoding: koi8-r
+`))
+
+	if err == nil {
+		t.Error("NewScriptsFolderOutput must fail on an unsupported encoding")
+	}
+}
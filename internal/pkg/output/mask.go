@@ -0,0 +1,145 @@
+package output
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EscapePercent экранирование недопустимых в именах файлов символов в формате %XX (по умолчанию). Экранирование
+	// обратимо, исходное наименование объекта БД восстанавливается функцией UnescapeName
+	EscapePercent = "percent"
+	// EscapeNone наименования объектов БД подставляются в имена файлов без изменений
+	EscapeNone = "none"
+)
+
+// placeholderPattern элемент подстановки маски имени файла: $name$ или $name:modifier$
+var placeholderPattern = regexp.MustCompile(`\$([a-zA-Z]+)(?::([a-zA-Z]+))?\$`)
+
+// reservedNames имена устройств, недопустимые в качестве имен файлов в Windows
+var reservedNames = map[string]bool{
+	"CON": true, "PRN": true, "AUX": true, "NUL": true,
+	"COM1": true, "COM2": true, "COM3": true, "COM4": true, "COM5": true, "COM6": true, "COM7": true, "COM8": true,
+	"COM9": true,
+	"LPT1": true, "LPT2": true, "LPT3": true, "LPT4": true, "LPT5": true, "LPT6": true, "LPT7": true, "LPT8": true,
+	"LPT9": true,
+}
+
+// ExpandMask заменяет элементы подстановки маски mask значениями объекта БД object. Подставляемые значения
+// экранируются по схеме escape.
+//
+// Элементы подстановки: $database$, $schema$, $object$, $type$, $parent$ (наименование родительского объекта
+// триггера), $owner$, $server$ и $date$ (текущая дата в формате YYYY-MM-DD). Модификаторы :lower и :upper
+// переводят значение в нижний или верхний регистр, например, $object:lower$
+func ExpandMask(mask string, object ObjectDefinition, escape string) (string, error) {
+	var err error
+
+	expanded := placeholderPattern.ReplaceAllStringFunc(mask, func(placeholder string) string {
+		match := placeholderPattern.FindStringSubmatch(placeholder)
+
+		var value string
+
+		switch strings.ToLower(match[1]) {
+		case "database":
+			value = object.Catalog
+		case "schema":
+			value = object.Schema
+		case "object":
+			value = object.Name
+		case "type":
+			value = object.Type.String()
+		case "parent":
+			value = object.Parent
+		case "owner":
+			value = object.Owner
+		case "server":
+			value = object.Server
+		case "date":
+			value = time.Now().Format("2006-01-02")
+		default:
+			if err == nil {
+				err = fmt.Errorf("unknown placeholder %s", placeholder)
+			}
+
+			return placeholder
+		}
+
+		switch strings.ToLower(match[2]) {
+		case "":
+		case "lower":
+			value = strings.ToLower(value)
+		case "upper":
+			value = strings.ToUpper(value)
+		default:
+			if err == nil {
+				err = fmt.Errorf("unknown placeholder modifier %s", placeholder)
+			}
+		}
+
+		return EscapeName(value, escape)
+	})
+
+	return expanded, err
+}
+
+// EscapeName экранирует в наименовании name символы, недопустимые в именах файлов, по схеме escape
+func EscapeName(name, escape string) string {
+	if strings.ToLower(escape) == EscapeNone {
+		return name
+	}
+
+	var builder strings.Builder
+
+	runes := []rune(name)
+	last := len(runes) - 1
+
+	base := strings.ToUpper(name)
+
+	if index := strings.Index(base, "."); index >= 0 {
+		base = base[:index]
+	}
+
+	for index, r := range runes {
+		escaped := r < 0x20 || strings.ContainsRune(`<>:"/\|?*%`, r) ||
+			(index == last && (r == '.' || r == ' ')) ||
+			(index == 0 && reservedNames[base])
+
+		if escaped && r < 0x80 {
+			builder.WriteString(fmt.Sprintf("%%%02X", r))
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+
+	return builder.String()
+}
+
+// UnescapeName восстанавливает наименование объекта БД, экранированное функцией EscapeName по схеме percent
+func UnescapeName(name string) (string, error) {
+	var builder strings.Builder
+
+	for index := 0; index < len(name); index++ {
+		if name[index] != '%' {
+			builder.WriteByte(name[index])
+			continue
+		}
+
+		if index+2 >= len(name) {
+			return name, fmt.Errorf("invalid escape sequence in %s", name)
+		}
+
+		code, err := strconv.ParseUint(name[index+1:index+3], 16, 8)
+
+		if err != nil {
+			return name, fmt.Errorf("invalid escape sequence in %s", name)
+		}
+
+		builder.WriteByte(byte(code))
+		index += 2
+	}
+
+	return builder.String(), nil
+}
@@ -0,0 +1,109 @@
+package output
+
+import (
+	"testing"
+	"time"
+)
+
+func TestExpandMask(t *testing.T) {
+	object := ObjectDefinition{
+		Catalog: "Sales",
+		Schema:  "dbo",
+		Name:    "trg_Orders/Insert",
+		Type:    Trigger,
+		Owner:   "dbo",
+		Parent:  "Orders",
+		Server:  "SQL01\\PROD",
+	}
+
+	var cases = []struct {
+		mask      string
+		escape    string
+		want      string
+		withError bool
+	}{
+		{
+			mask:   "$schema$.$object$.sql",
+			escape: "",
+			want:   "dbo.trg_Orders%2FInsert.sql",
+		},
+		{
+			mask:   "$parent:upper$.$object:lower$.sql",
+			escape: EscapePercent,
+			want:   "ORDERS.trg_orders%2Finsert.sql",
+		},
+		{
+			mask:   "$server$/$database$/$owner$.$type$.sql",
+			escape: EscapePercent,
+			want:   "SQL01%5CPROD/Sales/dbo.trigger.sql",
+		},
+		{
+			mask:   "$date$.sql",
+			escape: EscapePercent,
+			want:   time.Now().Format("2006-01-02") + ".sql",
+		},
+		{
+			mask:   "$object$.sql",
+			escape: EscapeNone,
+			want:   "trg_Orders/Insert.sql",
+		},
+		{
+			mask:      "$table$.sql",
+			escape:    EscapePercent,
+			withError: true,
+		},
+		{
+			mask:      "$object:title$.sql",
+			escape:    EscapePercent,
+			withError: true,
+		},
+	}
+
+	for _, test := range cases {
+		have, err := ExpandMask(test.mask, object, test.escape)
+
+		if (err != nil) != test.withError {
+			t.Errorf("ExpandMask(%s) failed: %v", test.mask, err)
+			continue
+		}
+
+		if !test.withError && have != test.want {
+			t.Errorf("ExpandMask(%s) failed: have %s, want %s", test.mask, have, test.want)
+		}
+	}
+}
+
+func TestEscapeName(t *testing.T) {
+	var cases = []struct {
+		name string
+		want string
+	}{
+		{name: "Orders", want: "Orders"},
+		{name: "Заказы", want: "Заказы"},
+		{name: "a/b\\c:d*e?f\"g<h>i|j", want: "a%2Fb%5Cc%3Ad%2Ae%3Ff%22g%3Ch%3Ei%7Cj"},
+		{name: "100%", want: "100%25"},
+		{name: "name.", want: "name%2E"},
+		{name: "name ", want: "name%20"},
+		{name: "con", want: "%63on"},
+		{name: "NUL.backup", want: "%4EUL.backup"},
+		{name: "CONTRACTS", want: "CONTRACTS"},
+	}
+
+	for _, test := range cases {
+		have := EscapeName(test.name, EscapePercent)
+
+		if have != test.want {
+			t.Errorf("EscapeName(%q) failed: have %q, want %q", test.name, have, test.want)
+		}
+
+		name, err := UnescapeName(have)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if name != test.name {
+			t.Errorf("UnescapeName(%q) failed: have %q, want %q", have, name, test.name)
+		}
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"sort"
+	"strings"
 
 	"gopkg.in/yaml.v2"
 )
@@ -14,8 +16,16 @@ type ScriptsFolderOutputRule struct {
 	SubDirectory string `yaml:"subdirectory"`
 	// FilenameMask маска имени файла
 	FilenameMask string `yaml:"mask"`
+	// Template путь к файлу шаблона скрипта (text/template). Если не указан, то скрипт создается по умолчанию
+	Template string `yaml:"template,omitempty"`
+	// FileFormat параметры записи файлов скриптов. Неуказанные параметры берутся из раздела defaults
+	FileFormat `yaml:",inline"`
 }
 
+// defaultsKey ключ раздела описания структуры каталога скриптов, в котором указываются параметры записи файлов
+// скриптов для всех типов объектов БД
+const defaultsKey = "defaults"
+
 // IScriptsFolderOutput интерфес описания структуры каталога скриптов
 type IScriptsFolderOutput interface {
 	// Rules возвращает целевой каталог и маску имени файла для указанного типа объекта itemType.
@@ -23,11 +33,18 @@ type IScriptsFolderOutput interface {
 	Rules(objectType DatabaseObjectType) (subdirectory, mask string, ok bool)
 	// DatabaseObjects возвращает список указанных в конфигурации вывода объектов базы данных
 	DatabaseObjects() []DatabaseObjectType
+	// Template возвращает путь к файлу шаблона скрипта для указанного типа объекта objectType.
+	// Если шаблон не указан, то в параметре ok возвращается false, в противном случае - true
+	Template(objectType DatabaseObjectType) (path string, ok bool)
+	// Format возвращает параметры записи файлов скриптов для указанного типа объекта objectType. Для типов, не
+	// указанных в конфигурации вывода, возвращаются параметры из раздела defaults
+	Format(objectType DatabaseObjectType) FileFormat
 }
 
 // ScriptsFolderOutput описание структуры каталога скриптов
 type ScriptsFolderOutput struct {
-	rules map[DatabaseObjectType]ScriptsFolderOutputRule
+	rules    map[DatabaseObjectType]ScriptsFolderOutputRule
+	defaults FileFormat
 }
 
 // DefaultScriptsFolderOutput структура каталога скриптов по умолчанию
@@ -47,13 +64,20 @@ func NewScriptsFolderOutput(in io.Reader) (*ScriptsFolderOutput, error) {
 		return nil, err
 	}
 
+	defaults := ss[defaultsKey].FileFormat
+	delete(ss, defaultsKey)
+
+	if err = defaults.Validate(); err != nil {
+		return nil, fmt.Errorf("%s: %v", defaultsKey, err)
+	}
+
 	si, err := mapping(ss)
 
 	if err != nil {
 		return nil, err
 	}
 
-	return &ScriptsFolderOutput{rules: si}, nil
+	return &ScriptsFolderOutput{rules: si, defaults: defaults}, nil
 }
 
 // DatabaseObjects возвращает список указанных в конфигурации вывода объектов базы данных
@@ -67,6 +91,10 @@ func (output *ScriptsFolderOutput) DatabaseObjects() []DatabaseObjectType {
 		index++
 	}
 
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i] < objects[j]
+	})
+
 	return objects
 }
 
@@ -83,6 +111,27 @@ func (output *ScriptsFolderOutput) Rules(item DatabaseObjectType) (subdirectory,
 	return
 }
 
+// Template возвращает путь к файлу шаблона скрипта для указанного типа объекта objectType.
+// Если шаблон не указан, то в параметре ok возвращается false, в противном случае - true
+func (output *ScriptsFolderOutput) Template(objectType DatabaseObjectType) (path string, ok bool) {
+	if rule, exists := output.rules[objectType]; exists {
+		path = strings.Trim(rule.Template, " ")
+		ok = path != ""
+	}
+
+	return
+}
+
+// Format возвращает параметры записи файлов скриптов для указанного типа объекта objectType. Для типов, не
+// указанных в конфигурации вывода, возвращаются параметры из раздела defaults
+func (output *ScriptsFolderOutput) Format(objectType DatabaseObjectType) FileFormat {
+	if rule, ok := output.rules[objectType]; ok {
+		return rule.FileFormat.merge(output.defaults)
+	}
+
+	return output.defaults
+}
+
 func parse(data []byte) (map[string]ScriptsFolderOutputRule, error) {
 	var s map[string]ScriptsFolderOutputRule
 
@@ -104,6 +153,10 @@ func mapping(rules map[string]ScriptsFolderOutputRule) (map[DatabaseObjectType]S
 
 	for key, value := range rules {
 		if k, ok := databaseObjectTypeMappingReverse[key]; ok {
+			if err := value.FileFormat.Validate(); err != nil {
+				return si, fmt.Errorf("%s: %v", key, err)
+			}
+
 			si[k] = value
 		} else {
 			return si, fmt.Errorf("unknown object type %s", key)
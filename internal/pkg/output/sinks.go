@@ -0,0 +1,595 @@
+package output
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"container/heap"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ObjectDefinition скрипт объекта БД
+type ObjectDefinition struct {
+	// Catalog наименование базы данных
+	Catalog string
+	// Schema схема объекта БД
+	Schema string
+	// Name наименование объекта БД
+	Name string
+	// Type тип объекта БД
+	Type DatabaseObjectType
+	// Owner владелец объекта БД
+	Owner string
+	// Parent наименование родительского объекта БД (таблицы или представления триггера)
+	Parent string
+	// Server наименование сервера
+	Server string
+	// Definition скрипт объекта БД
+	Definition []byte
+	// Dependencies объекты БД, от которых непосредственно зависит объект (заполняется, только если зависимости
+	// запрошены у команды)
+	Dependencies []ObjectReference
+}
+
+// ObjectReference ссылка на объект БД
+type ObjectReference struct {
+	// Type тип объекта БД
+	Type DatabaseObjectType
+	// Schema схема объекта БД
+	Schema string
+	// Name наименование объекта БД
+	Name string
+}
+
+// Reference возвращает ссылку на объект БД
+func (object ObjectDefinition) Reference() ObjectReference {
+	return ObjectReference{Type: object.Type, Schema: object.Schema, Name: object.Name}
+}
+
+// QualifiedName возвращает наименование объекта БД вида [schema].[name] (для схем и БД - [schema] и [catalog])
+func (object ObjectDefinition) QualifiedName() string {
+	switch {
+	case object.Type == Database:
+		return Bracketed(object.Catalog)
+	case object.Name == "":
+		return Bracketed(object.Schema)
+	default:
+		return Bracketed(object.Schema) + "." + Bracketed(object.Name)
+	}
+}
+
+// Bracketed возвращает идентификатор name в квадратных скобках. Закрывающие скобки в идентификаторе удваиваются
+func Bracketed(name string) string {
+	return "[" + strings.ReplaceAll(name, "]", "]]") + "]"
+}
+
+// SinkType тип приемника скриптов
+type SinkType byte
+
+const (
+	// SinkFolder каталог скриптов (по умолчанию)
+	SinkFolder SinkType = iota
+	// SinkScript единый скрипт развертывания
+	SinkScript
+	// SinkTar tar архив
+	SinkTar
+	// SinkZip zip архив
+	SinkZip
+	// SinkStdout вывод скриптов в stdout
+	SinkStdout
+)
+
+// String возвращает строковое представление типа приемника скриптов
+func (sinkType SinkType) String() string {
+	switch sinkType {
+	case SinkScript:
+		return "script"
+	case SinkTar:
+		return "tar"
+	case SinkZip:
+		return "zip"
+	case SinkStdout:
+		return "stdout"
+	default:
+		return "folder"
+	}
+}
+
+// ISink интерфейс приемника скриптов объектов БД
+type ISink interface {
+	// Write записывает скрипт объекта БД
+	Write(object ObjectDefinition) error
+	// Close завершает запись скриптов
+	Close() error
+}
+
+// ScriptFilename возвращает подкаталог и имя файла скрипта объекта БД object в соответствии с описанием структуры
+// каталога скриптов rules
+func ScriptFilename(rules IScriptsFolderOutput, object ObjectDefinition) (subdirectory, filename string, err error) {
+	subdirectory, mask, ok := rules.Rules(object.Type)
+
+	if !ok {
+		return "", "", fmt.Errorf("no ouput rules for %v", object.Type)
+	}
+
+	escape := rules.Format(object.Type).Escape
+
+	if subdirectory, err = ExpandMask(subdirectory, object, escape); err != nil {
+		return "", "", err
+	}
+
+	if filename, err = ExpandMask(mask, object, escape); err != nil {
+		return "", "", err
+	}
+
+	return subdirectory, filename, nil
+}
+
+// FolderSink приемник скриптов, записывающий скрипты в каталог
+type FolderSink struct {
+	folder *Folder
+	rules  IScriptsFolderOutput
+}
+
+// NewFolderSink конструктор FolderSink
+func NewFolderSink(folder *Folder, rules IScriptsFolderOutput) *FolderSink {
+	return &FolderSink{folder: folder, rules: rules}
+}
+
+// Write записывает скрипт объекта БД в файл. Файл перезаписывается, только если его содержимое изменилось
+func (sink *FolderSink) Write(object ObjectDefinition) error {
+	subdirectory, filename, err := ScriptFilename(sink.rules, object)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = sink.folder.Write(subdirectory, filename, sink.rules.Format(object.Type).Encode(object.Definition))
+
+	return err
+}
+
+// Close завершает запись скриптов
+func (sink *FolderSink) Close() error {
+	return nil
+}
+
+// WriteError ошибка записи скрипта объекта БД
+type WriteError struct {
+	// Object объект БД, скрипт которого не записан
+	Object ObjectDefinition
+	// Err причина ошибки
+	Err error
+}
+
+// Error возвращает текст ошибки
+func (e *WriteError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Object.QualifiedName(), e.Err)
+}
+
+// Unwrap возвращает причину ошибки
+func (e *WriteError) Unwrap() error {
+	return e.Err
+}
+
+// AsyncSink приемник скриптов, передающий скрипты в приемник sink из нескольких горутин. Используется для
+// параллельной записи скриптов в каталог, где порядок записи файлов не важен
+type AsyncSink struct {
+	sink        ISink
+	objects     chan ObjectDefinition
+	wg          sync.WaitGroup
+	once        sync.Once
+	mutex       sync.Mutex
+	writeErrors []*WriteError
+	closeErr    error
+}
+
+// NewAsyncSink конструктор AsyncSink. workers - количество горутин, записывающих скрипты
+func NewAsyncSink(sink ISink, workers int) *AsyncSink {
+	if workers < 1 {
+		workers = 1
+	}
+
+	async := &AsyncSink{
+		sink:    sink,
+		objects: make(chan ObjectDefinition, workers),
+	}
+
+	async.wg.Add(workers)
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer async.wg.Done()
+
+			for object := range async.objects {
+				if err := async.sink.Write(object); err != nil {
+					async.mutex.Lock()
+					async.writeErrors = append(async.writeErrors, &WriteError{Object: object, Err: err})
+					async.mutex.Unlock()
+				}
+			}
+		}()
+	}
+
+	return async
+}
+
+// Write передает скрипт объекта БД на запись. Ошибки записи возвращаются методом Errors после закрытия приемника
+func (sink *AsyncSink) Write(object ObjectDefinition) error {
+	sink.objects <- object
+	return nil
+}
+
+// Close дожидается записи всех переданных скриптов и закрывает приемник sink. Возвращает ошибку закрытия
+// приемника sink или первую ошибку записи
+func (sink *AsyncSink) Close() error {
+	sink.once.Do(func() {
+		close(sink.objects)
+		sink.wg.Wait()
+
+		sink.closeErr = sink.sink.Close()
+
+		sort.SliceStable(sink.writeErrors, func(i, j int) bool {
+			return sink.writeErrors[i].Object.QualifiedName() < sink.writeErrors[j].Object.QualifiedName()
+		})
+	})
+
+	if sink.closeErr != nil {
+		return sink.closeErr
+	}
+
+	if errs := sink.Errors(); len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+// Errors возвращает ошибки записи скриптов. После закрытия приемника ошибки упорядочены по наименованию объекта БД
+func (sink *AsyncSink) Errors() []*WriteError {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	return sink.writeErrors
+}
+
+// scriptOrder порядок типов объектов БД в едином скрипте развертывания
+var scriptOrder = map[DatabaseObjectType]int{
+	Database:             0,
+	Schema:               1,
+	UserDefinedDataType:  2,
+	UserDefinedTableType: 3,
+	Table:                4,
+	StaticData:           5,
+	Function:             6,
+	View:                 7,
+	Procedure:            8,
+	Trigger:              9,
+}
+
+// ScriptSink приемник скриптов, объединяющий скрипты всех объектов БД в единый скрипт развертывания. Скрипт объекта
+// БД записывается после скриптов объектов, от которых он зависит (ObjectDefinition.Dependencies), а при отсутствии
+// зависимостей между объектами скрипты упорядочиваются по типу объекта БД (база данных, схемы, пользовательские типы,
+// таблицы, функции, представления, процедуры, триггеры) и внутри типа - по схеме и наименованию
+type ScriptSink struct {
+	writer  io.Writer
+	format  FileFormat
+	mutex   sync.Mutex
+	objects []ObjectDefinition
+}
+
+// NewScriptSink конструктор ScriptSink. Единый скрипт записывается в формате format
+func NewScriptSink(writer io.Writer, format FileFormat) *ScriptSink {
+	return &ScriptSink{writer: writer, format: format}
+}
+
+// Write добавляет скрипт объекта БД в единый скрипт
+func (sink *ScriptSink) Write(object ObjectDefinition) error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	sink.objects = append(sink.objects, object)
+
+	return nil
+}
+
+// Close записывает единый скрипт
+func (sink *ScriptSink) Close() error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	var buffer bytes.Buffer
+
+	for _, object := range deploymentOrder(sink.objects) {
+		if err := writeDefinition(&buffer, object.Definition); err != nil {
+			return err
+		}
+	}
+
+	_, err := sink.writer.Write(sink.format.Encode(buffer.Bytes()))
+
+	return err
+}
+
+// deploymentOrder возвращает скрипты объектов БД objects в порядке развертывания: объект следует за объектами, от
+// которых он зависит. Из объектов, готовых к развертыванию, первым выбирается объект с меньшим порядком типа, схемой и
+// наименованием. Циклические зависимости разрываются тем же правилом. Зависимости от объектов, которых нет в objects,
+// не учитываются
+func deploymentOrder(objects []ObjectDefinition) []ObjectDefinition {
+	sorted := make([]ObjectDefinition, len(objects))
+	copy(sorted, objects)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Type != sorted[j].Type {
+			return typeOrder(sorted[i].Type) < typeOrder(sorted[j].Type)
+		}
+
+		if sorted[i].Schema != sorted[j].Schema {
+			return sorted[i].Schema < sorted[j].Schema
+		}
+
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	// после сортировки приоритет объекта - его индекс в sorted
+	index := make(map[ObjectReference]int, len(sorted))
+
+	for i, object := range sorted {
+		index[object.Reference()] = i
+	}
+
+	pending := make([]int, len(sorted))
+	dependents := make([][]int, len(sorted))
+
+	for i, object := range sorted {
+		seen := make(map[int]bool, len(object.Dependencies))
+
+		for _, dependency := range object.Dependencies {
+			if j, ok := index[dependency]; ok && j != i && !seen[j] {
+				seen[j] = true
+				pending[i]++
+				dependents[j] = append(dependents[j], i)
+			}
+		}
+	}
+
+	ready := &intHeap{}
+
+	for i := range sorted {
+		if pending[i] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+
+	ordered := make([]ObjectDefinition, 0, len(sorted))
+	emitted := make([]bool, len(sorted))
+	next := 0
+
+	for len(ordered) < len(sorted) {
+		var i int
+
+		if ready.Len() > 0 {
+			i = heap.Pop(ready).(int)
+		} else {
+			// цикл зависимостей: развертывается первый по порядку из оставшихся объектов
+			for emitted[next] {
+				next++
+			}
+
+			i = next
+		}
+
+		if emitted[i] {
+			continue
+		}
+
+		emitted[i] = true
+		ordered = append(ordered, sorted[i])
+
+		for _, j := range dependents[i] {
+			if pending[j]--; pending[j] == 0 && !emitted[j] {
+				heap.Push(ready, j)
+			}
+		}
+	}
+
+	return ordered
+}
+
+// intHeap куча индексов объектов БД, упорядоченная по возрастанию
+type intHeap []int
+
+func (h intHeap) Len() int            { return len(h) }
+func (h intHeap) Less(i, j int) bool  { return h[i] < h[j] }
+func (h intHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *intHeap) Push(x interface{}) { *h = append(*h, x.(int)) }
+
+func (h *intHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+
+	return x
+}
+
+func typeOrder(objectType DatabaseObjectType) int {
+	if order, ok := scriptOrder[objectType]; ok {
+		return order
+	}
+
+	return len(scriptOrder)
+}
+
+// StreamSink приемник скриптов, записывающий скрипты в поток (например, stdout) по мере их создания
+type StreamSink struct {
+	writer  io.Writer
+	format  FileFormat
+	mutex   sync.Mutex
+	started bool
+}
+
+// NewStreamSink конструктор StreamSink. Скрипты записываются в кодировке и с окончаниями строк, указанными в format.
+// Маркер последовательности байтов записывается только перед первым скриптом
+func NewStreamSink(writer io.Writer, format FileFormat) *StreamSink {
+	format.TrailingNewline = nil
+
+	return &StreamSink{writer: writer, format: format}
+}
+
+// Write записывает скрипт объекта БД в поток
+func (sink *StreamSink) Write(object ObjectDefinition) error {
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	var buffer bytes.Buffer
+
+	if err := writeDefinition(&buffer, object.Definition); err != nil || buffer.Len() == 0 {
+		return err
+	}
+
+	bom := !sink.started && sink.format.BOM != nil && *sink.format.BOM
+	sink.started = true
+
+	_, err := sink.writer.Write(sink.format.encode(sink.format.Text(buffer.Bytes()), bom))
+
+	return err
+}
+
+// Close завершает запись скриптов
+func (sink *StreamSink) Close() error {
+	return nil
+}
+
+func writeDefinition(writer io.Writer, definition []byte) error {
+	if len(definition) == 0 {
+		return nil
+	}
+
+	if _, err := writer.Write(definition); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(writer, "\n\n")
+
+	return err
+}
+
+// archiveModTime время изменения файлов в архивах скриптов. Время постоянное, чтобы архивы одинаковых скриптов
+// совпадали побайтно и их можно было сравнивать
+var archiveModTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// TarSink приемник скриптов, записывающий скрипты в tar архив. Структура каталогов архива соответствует описанию
+// структуры каталога скриптов
+type TarSink struct {
+	writer *tar.Writer
+	rules  IScriptsFolderOutput
+	mutex  sync.Mutex
+}
+
+// NewTarSink конструктор TarSink
+func NewTarSink(writer io.Writer, rules IScriptsFolderOutput) *TarSink {
+	return &TarSink{
+		writer: tar.NewWriter(writer),
+		rules:  rules,
+	}
+}
+
+// Write записывает скрипт объекта БД в архив
+func (sink *TarSink) Write(object ObjectDefinition) error {
+	name, err := archiveName(sink.rules, object)
+
+	if err != nil {
+		return err
+	}
+
+	data := sink.rules.Format(object.Type).Encode(object.Definition)
+
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	header := &tar.Header{
+		Name:     name,
+		Mode:     0664,
+		Size:     int64(len(data)),
+		ModTime:  archiveModTime,
+		Typeflag: tar.TypeReg,
+	}
+
+	if err = sink.writer.WriteHeader(header); err != nil {
+		return err
+	}
+
+	_, err = sink.writer.Write(data)
+
+	return err
+}
+
+// Close завершает запись архива
+func (sink *TarSink) Close() error {
+	return sink.writer.Close()
+}
+
+// ZipSink приемник скриптов, записывающий скрипты в zip архив. Структура каталогов архива соответствует описанию
+// структуры каталога скриптов
+type ZipSink struct {
+	writer *zip.Writer
+	rules  IScriptsFolderOutput
+	mutex  sync.Mutex
+}
+
+// NewZipSink конструктор ZipSink
+func NewZipSink(writer io.Writer, rules IScriptsFolderOutput) *ZipSink {
+	return &ZipSink{
+		writer: zip.NewWriter(writer),
+		rules:  rules,
+	}
+}
+
+// Write записывает скрипт объекта БД в архив
+func (sink *ZipSink) Write(object ObjectDefinition) error {
+	name, err := archiveName(sink.rules, object)
+
+	if err != nil {
+		return err
+	}
+
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	writer, err := sink.writer.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: archiveModTime,
+	})
+
+	if err != nil {
+		return err
+	}
+
+	_, err = writer.Write(sink.rules.Format(object.Type).Encode(object.Definition))
+
+	return err
+}
+
+// Close завершает запись архива
+func (sink *ZipSink) Close() error {
+	return sink.writer.Close()
+}
+
+// archiveName возвращает путь к скрипту объекта БД внутри архива
+func archiveName(rules IScriptsFolderOutput, object ObjectDefinition) (string, error) {
+	subdirectory, filename, err := ScriptFilename(rules, object)
+
+	if err != nil {
+		return "", err
+	}
+
+	return path.Join(strings.ReplaceAll(subdirectory, "\\", "/"), filename), nil
+}
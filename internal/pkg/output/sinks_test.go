@@ -0,0 +1,392 @@
+package output
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+)
+
+var sinkObjects = []ObjectDefinition{
+	{Catalog: "Sales", Schema: "dbo", Name: "GetOrders", Type: Procedure,
+		Definition: []byte("CREATE PROCEDURE [dbo].[GetOrders] AS RETURN\nGO")},
+	{Catalog: "Sales", Schema: "rpt", Type: Schema, Definition: []byte("CREATE SCHEMA [rpt]\nGO")},
+	{Catalog: "Sales", Schema: "dbo", Name: "Orders", Type: View,
+		Definition: []byte("CREATE VIEW [dbo].[Orders] AS SELECT 1 AS [one]\nGO")},
+	{Catalog: "Sales", Schema: "dbo", Type: Schema, Definition: []byte("CREATE SCHEMA [dbo]\nGO")},
+}
+
+func TestScriptSink(t *testing.T) {
+	var buffer bytes.Buffer
+
+	sink := NewScriptSink(&buffer, FileFormat{})
+
+	for _, object := range sinkObjects {
+		if err := sink.Write(object); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "CREATE SCHEMA [dbo]\nGO\n\nCREATE SCHEMA [rpt]\nGO\n\n" +
+		"CREATE VIEW [dbo].[Orders] AS SELECT 1 AS [one]\nGO\n\n" +
+		"CREATE PROCEDURE [dbo].[GetOrders] AS RETURN\nGO\n\n"
+
+	if buffer.String() != want {
+		t.Errorf("ScriptSink failed: have %q, want %q", buffer.String(), want)
+	}
+}
+
+func TestDeploymentOrder(t *testing.T) {
+	reference := func(objectType DatabaseObjectType, name string) ObjectReference {
+		return ObjectReference{Type: objectType, Schema: "dbo", Name: name}
+	}
+
+	objects := []ObjectDefinition{
+		{Schema: "dbo", Name: "Report", Type: View,
+			Dependencies: []ObjectReference{reference(View, "Orders"), reference(Function, "Total")}},
+		{Schema: "dbo", Name: "Orders", Type: View,
+			Dependencies: []ObjectReference{reference(Table, "Orders"), reference(Table, "Missing")}},
+		{Schema: "dbo", Name: "Total", Type: Function},
+		{Schema: "dbo", Name: "Lines", Type: Table,
+			Dependencies: []ObjectReference{reference(Table, "Orders"), reference(Function, "Amount")}},
+		{Schema: "dbo", Name: "Orders", Type: Table},
+		{Schema: "dbo", Name: "Amount", Type: Function},
+		{Schema: "dbo", Type: Schema},
+		{Schema: "dbo", Name: "A", Type: Procedure, Dependencies: []ObjectReference{reference(Procedure, "B")}},
+		{Schema: "dbo", Name: "B", Type: Procedure, Dependencies: []ObjectReference{reference(Procedure, "A")}},
+	}
+
+	want := []string{
+		"schema [dbo]",
+		"table [dbo].[Orders]",
+		"function [dbo].[Amount]",
+		"table [dbo].[Lines]",
+		"function [dbo].[Total]",
+		"view [dbo].[Orders]",
+		"view [dbo].[Report]",
+		"procedure [dbo].[A]",
+		"procedure [dbo].[B]",
+	}
+
+	have := make([]string, 0, len(objects))
+
+	for _, object := range deploymentOrder(objects) {
+		have = append(have, fmt.Sprintf("%s %s", object.Type, object.QualifiedName()))
+	}
+
+	if !reflect.DeepEqual(have, want) {
+		t.Errorf("deploymentOrder failed: have %v, want %v", have, want)
+	}
+}
+
+func TestStreamSink(t *testing.T) {
+	var buffer bytes.Buffer
+
+	sink := NewStreamSink(&buffer, FileFormat{})
+
+	for _, object := range sinkObjects[:2] {
+		if err := sink.Write(object); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := "CREATE PROCEDURE [dbo].[GetOrders] AS RETURN\nGO\n\nCREATE SCHEMA [rpt]\nGO\n\n"
+
+	if buffer.String() != want {
+		t.Errorf("StreamSink failed: have %q, want %q", buffer.String(), want)
+	}
+}
+
+var archiveNames = []string{
+	"Programmability/Procedures/dbo.GetOrders.sql",
+	"Security/Schemas/rpt.sql",
+	"Views/dbo.Orders.sql",
+	"Security/Schemas/dbo.sql",
+}
+
+func TestTarSink(t *testing.T) {
+	var buffer bytes.Buffer
+
+	sink := NewTarSink(&buffer, DefaultScriptsFolderOutput)
+
+	for _, object := range sinkObjects {
+		if err := sink.Write(object); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader := tar.NewReader(&buffer)
+	names := make([]string, 0)
+
+	for {
+		header, err := reader.Next()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := ioutil.ReadAll(reader)
+
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(data, sinkObjects[len(names)].Definition) {
+			t.Errorf("TarSink failed: unexpected content of %s", header.Name)
+		}
+
+		names = append(names, header.Name)
+	}
+
+	if !reflect.DeepEqual(names, archiveNames) {
+		t.Errorf("TarSink failed: have %v, want %v", names, archiveNames)
+	}
+}
+
+func TestZipSink(t *testing.T) {
+	var buffer bytes.Buffer
+
+	sink := NewZipSink(&buffer, DefaultScriptsFolderOutput)
+
+	for _, object := range sinkObjects {
+		if err := sink.Write(object); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, len(reader.File))
+
+	for index, file := range reader.File {
+		names[index] = file.Name
+	}
+
+	if !reflect.DeepEqual(names, archiveNames) {
+		t.Errorf("ZipSink failed: have %v, want %v", names, archiveNames)
+	}
+}
+
+func TestArchiveSinks_reproducible(t *testing.T) {
+	sinks := map[string]func(writer io.Writer) ISink{
+		"tar": func(writer io.Writer) ISink { return NewTarSink(writer, DefaultScriptsFolderOutput) },
+		"zip": func(writer io.Writer) ISink { return NewZipSink(writer, DefaultScriptsFolderOutput) },
+	}
+
+	for name, newSink := range sinks {
+		archives := make([][]byte, 2)
+
+		for index := range archives {
+			var buffer bytes.Buffer
+
+			sink := newSink(&buffer)
+
+			for _, object := range sinkObjects {
+				if err := sink.Write(object); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := sink.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			archives[index] = buffer.Bytes()
+		}
+
+		if !bytes.Equal(archives[0], archives[1]) {
+			t.Errorf("%s archives of the same scripts differ", name)
+		}
+	}
+
+	var buffer bytes.Buffer
+
+	sink := NewTarSink(&buffer, DefaultScriptsFolderOutput)
+
+	if err := sink.Write(sinkObjects[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := tar.NewReader(&buffer).Next()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !header.ModTime.Equal(archiveModTime) {
+		t.Errorf("have modification time %v, want %v", header.ModTime, archiveModTime)
+	}
+}
+
+func TestObjectDefinition_QualifiedName(t *testing.T) {
+	var cases = []struct {
+		object ObjectDefinition
+		want   string
+	}{
+		{object: ObjectDefinition{Catalog: "Sales", Type: Database}, want: "[Sales]"},
+		{object: ObjectDefinition{Schema: "rpt", Type: Schema}, want: "[rpt]"},
+		{object: ObjectDefinition{Schema: "dbo", Name: "Orders", Type: Table}, want: "[dbo].[Orders]"},
+		{object: ObjectDefinition{Schema: "dbo", Name: "a]b", Type: Table}, want: "[dbo].[a]]b]"},
+		{object: ObjectDefinition{Catalog: "Sales]Archive", Type: Database}, want: "[Sales]]Archive]"},
+	}
+
+	for _, test := range cases {
+		if have := test.object.QualifiedName(); have != test.want {
+			t.Errorf("QualifiedName failed: have %s, want %s", have, test.want)
+		}
+	}
+}
+
+type memorySink struct {
+	mutex  sync.Mutex
+	names  []string
+	failOn string
+	closed bool
+}
+
+func (sink *memorySink) Write(object ObjectDefinition) error {
+	if sink.failOn != "" && object.Name == sink.failOn {
+		return errors.New("write failed")
+	}
+
+	sink.mutex.Lock()
+	defer sink.mutex.Unlock()
+
+	sink.names = append(sink.names, object.Name)
+
+	return nil
+}
+
+func (sink *memorySink) Close() error {
+	sink.closed = true
+	return nil
+}
+
+func TestAsyncSink(t *testing.T) {
+	var cases = []struct {
+		failOn  string
+		wantErr bool
+	}{
+		{failOn: "", wantErr: false},
+		{failOn: "GetOrders", wantErr: true},
+	}
+
+	for _, test := range cases {
+		memory := &memorySink{failOn: test.failOn}
+		sink := NewAsyncSink(memory, 4)
+
+		for _, object := range sinkObjects {
+			_ = sink.Write(object)
+		}
+
+		err := sink.Close()
+
+		if (err != nil) != test.wantErr {
+			t.Fatalf("AsyncSink failed: have error %v, want error %v", err, test.wantErr)
+		}
+
+		if test.wantErr {
+			var writeErr *WriteError
+
+			if !errors.As(err, &writeErr) || writeErr.Object.QualifiedName() != "[dbo].[GetOrders]" {
+				t.Errorf("AsyncSink failed: have error %v, want write error of [dbo].[GetOrders]", err)
+			}
+		}
+
+		if !memory.closed {
+			t.Error("AsyncSink failed: sink is not closed")
+		}
+
+		if test.wantErr {
+			continue
+		}
+
+		want := make([]string, len(sinkObjects))
+
+		for index, object := range sinkObjects {
+			want[index] = object.Name
+		}
+
+		sort.Strings(want)
+		sort.Strings(memory.names)
+
+		if !reflect.DeepEqual(memory.names, want) {
+			t.Errorf("AsyncSink failed: have %v, want %v", memory.names, want)
+		}
+	}
+}
+
+func BenchmarkAsyncSink(b *testing.B) {
+	objects := make([]ObjectDefinition, 1000)
+
+	for index := range objects {
+		name := fmt.Sprintf("GetOrders%05d", index)
+
+		objects[index] = ObjectDefinition{Schema: "dbo", Name: name, Type: Procedure,
+			Definition: []byte(fmt.Sprintf("CREATE PROCEDURE [dbo].[%s] AS RETURN\nGO", name))}
+	}
+
+	for _, workers := range []int{1, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+
+				dir, err := ioutil.TempDir("", "dbmill-async")
+
+				if err != nil {
+					b.Fatal(err)
+				}
+
+				b.StartTimer()
+
+				sink := NewAsyncSink(NewFolderSink(NewFolder(dir), DefaultScriptsFolderOutput), workers)
+
+				for _, object := range objects {
+					if err = sink.Write(object); err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				if err = sink.Close(); err != nil {
+					b.Fatal(err)
+				}
+
+				b.StopTimer()
+				_ = os.RemoveAll(dir)
+				b.StartTimer()
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// TemplateFuncs функции, доступные в шаблонах скриптов
+var TemplateFuncs = template.FuncMap{
+	// upper переводит строку в верхний регистр
+	"upper": strings.ToUpper,
+	// lower переводит строку в нижний регистр
+	"lower": strings.ToLower,
+	// trim удаляет пробельные символы в начале и в конце строки
+	"trim": strings.TrimSpace,
+	// join объединяет элементы среза строк через разделитель
+	"join": func(sep string, values []string) string {
+		return strings.Join(values, sep)
+	},
+	// brackets заключает идентификатор в квадратные скобки
+	"brackets": Bracketed,
+	// quote экранирует одинарные кавычки для использования значения в строковом литерале
+	"quote": func(value string) string {
+		return strings.ReplaceAll(value, "'", "''")
+	},
+	// indent добавляет в начало каждой непустой строки текста spaces пробелов
+	"indent": func(spaces int, value string) string {
+		prefix := strings.Repeat(" ", spaces)
+		lines := strings.Split(value, "\n")
+
+		for index, line := range lines {
+			if line != "" {
+				lines[index] = prefix + line
+			}
+		}
+
+		return strings.Join(lines, "\n")
+	},
+}
+
+// Templates шаблоны скриптов объектов БД
+type Templates map[DatabaseObjectType]*template.Template
+
+// NewTemplate разбирает текст шаблона скрипта text
+func NewTemplate(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(TemplateFuncs).Option("missingkey=error").Parse(text)
+}
+
+// LoadTemplates загружает шаблоны скриптов, указанные в описании структуры каталога скриптов rules. Относительные
+// пути к файлам шаблонов отсчитываются от каталога baseDir
+func LoadTemplates(rules IScriptsFolderOutput, baseDir string) (Templates, error) {
+	templates := make(Templates)
+
+	for _, objectType := range rules.DatabaseObjects() {
+		path, ok := rules.Template(objectType)
+
+		if !ok {
+			continue
+		}
+
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(baseDir, path)
+		}
+
+		data, err := ioutil.ReadFile(path)
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the %s template: %v", objectType, err)
+		}
+
+		tmpl, err := NewTemplate(objectType.String(), string(data))
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse the %s template: %v", objectType, err)
+		}
+
+		templates[objectType] = tmpl
+	}
+
+	return templates, nil
+}
+
+// Execute создает скрипт объекта БД типа objectType по шаблону. Если шаблон для указанного типа не задан, то в
+// параметре ok возвращается false
+func (templates Templates) Execute(objectType DatabaseObjectType, data interface{}) (script []byte, ok bool,
+	err error) {
+	tmpl, ok := templates[objectType]
+
+	if !ok || tmpl == nil {
+		return nil, false, nil
+	}
+
+	var buffer bytes.Buffer
+
+	if err = tmpl.Execute(&buffer, data); err != nil {
+		return nil, true, err
+	}
+
+	return buffer.Bytes(), true, nil
+}
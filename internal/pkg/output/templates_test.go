@@ -0,0 +1,115 @@
+package output
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadTemplates(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbmill-templates")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	err = ioutil.WriteFile(filepath.Join(dir, "procedure.tmpl"),
+		[]byte("-- {{ upper .Name }}\n{{ .Definition }}"), 0664)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := NewScriptsFolderOutput(strings.NewReader(`
+procedure:
+  subdirectory: Procedures
+  mask: $schema$.$object$.sql
+  template: procedure.tmpl
+view:
+  subdirectory: Views
+  mask: $schema$.$object$.sql
+`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	templates, err := LoadTemplates(rules, dir)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := struct {
+		Name       string
+		Definition string
+	}{
+		Name:       "Test",
+		Definition: "CREATE PROCEDURE [dbo].[Test] AS RETURN\nGO",
+	}
+
+	script, ok, err := templates.Execute(Procedure, data)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "-- TEST\nCREATE PROCEDURE [dbo].[Test] AS RETURN\nGO"
+
+	if !ok || string(script) != want {
+		t.Errorf("Execute(procedure) failed: have %q, want %q", script, want)
+	}
+
+	if _, ok, _ = templates.Execute(View, data); ok {
+		t.Error("Execute(view) failed: template must not exist")
+	}
+}
+
+func TestLoadTemplatesWithMissingFile(t *testing.T) {
+	rules, err := NewScriptsFolderOutput(strings.NewReader(`
+procedure:
+  subdirectory: Procedures
+  mask: $schema$.$object$.sql
+  template: missing.tmpl
+`))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = LoadTemplates(rules, os.TempDir()); err == nil {
+		t.Error("LoadTemplates must fail on a missing template file")
+	}
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	tmpl, err := NewTemplate("test",
+		`{{ brackets .Name }} {{ quote .Description }} {{ join ", " .Items }}|{{ indent 2 .Text }}`)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var builder strings.Builder
+
+	err = tmpl.Execute(&builder, map[string]interface{}{
+		"Name":        "a]b",
+		"Description": "it's",
+		"Items":       []string{"x", "y"},
+		"Text":        "line1\n\nline2",
+	})
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "[a]]b] it''s x, y|  line1\n\n  line2"
+
+	if builder.String() != want {
+		t.Errorf("TemplateFuncs failed: have %q, want %q", builder.String(), want)
+	}
+}
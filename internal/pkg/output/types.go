@@ -1,5 +1,10 @@
 package output
 
+import (
+	"fmt"
+	"strings"
+)
+
 // DatabaseObjectType тип элемента структуры каталога
 type DatabaseObjectType uint16
 
@@ -33,6 +38,18 @@ func (objType DatabaseObjectType) String() string {
 	return databaseObjectTypeMapping[objType]
 }
 
+// ParseDatabaseObjectType возвращает тип объекта БД по его наименованию (table, procedure, dataType и т.п.) без учета
+// регистра
+func ParseDatabaseObjectType(name string) (DatabaseObjectType, error) {
+	for key, objType := range databaseObjectTypeMappingReverse {
+		if objType != UnknownObject && strings.EqualFold(key, name) {
+			return objType, nil
+		}
+	}
+
+	return UnknownObject, fmt.Errorf("unknown object type %s", name)
+}
+
 var databaseObjectTypeMapping = map[DatabaseObjectType]string{
 	UnknownObject:        "unknown",
 	Database:             "database",
@@ -0,0 +1,195 @@
+package progress
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reporter отображение хода выполнения длительной операции. На терминале строка хода выполнения обновляется на месте,
+// иначе ход выполнения выводится отдельными строками не чаще, чем раз в интервал, и при смене этапа
+type Reporter struct {
+	writer   io.Writer
+	tty      bool
+	interval time.Duration
+	now      func() time.Time
+
+	mutex        sync.Mutex
+	phase        string
+	phaseStarted time.Time
+	printed      time.Time
+	width        int
+	done         int
+	total        int
+	details      string
+}
+
+// Option тип параметра Reporter
+type Option func(reporter *Reporter)
+
+// WithTTY указывает, что ход выполнения выводится на терминал
+func WithTTY(tty bool) Option {
+	return func(reporter *Reporter) {
+		reporter.tty = tty
+	}
+}
+
+// WithInterval устанавливает минимальный интервал между выводом хода выполнения
+func WithInterval(interval time.Duration) Option {
+	return func(reporter *Reporter) {
+		reporter.interval = interval
+	}
+}
+
+// WithClock устанавливает функцию получения текущего времени
+func WithClock(now func() time.Time) Option {
+	return func(reporter *Reporter) {
+		reporter.now = now
+	}
+}
+
+// TTYInterval интервал обновления строки хода выполнения на терминале
+const TTYInterval = 100 * time.Millisecond
+
+// PlainInterval интервал вывода строк хода выполнения, если вывод не является терминалом
+const PlainInterval = 5 * time.Second
+
+// New конструктор Reporter
+func New(writer io.Writer, options ...Option) *Reporter {
+	reporter := &Reporter{
+		writer: writer,
+		now:    time.Now,
+	}
+
+	for _, option := range options {
+		option(reporter)
+	}
+
+	if reporter.interval == 0 {
+		if reporter.tty {
+			reporter.interval = TTYInterval
+		} else {
+			reporter.interval = PlainInterval
+		}
+	}
+
+	return reporter
+}
+
+// Update обновляет ход выполнения этапа phase: обработано done элементов из total (0, если количество неизвестно)
+func (reporter *Reporter) Update(phase string, done, total int) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	now := reporter.now()
+	changed := phase != reporter.phase
+
+	if changed {
+		if reporter.phase != "" {
+			reporter.print(now, true)
+		}
+
+		reporter.phase = phase
+		reporter.phaseStarted = now
+		reporter.details = ""
+	}
+
+	reporter.done = done
+	reporter.total = total
+
+	if changed || now.Sub(reporter.printed) >= reporter.interval || (total > 0 && done == total) {
+		reporter.print(now, false)
+	}
+}
+
+// SetDetails устанавливает дополнительные сведения о ходе выполнения текущего этапа. Сведения сбрасываются при смене
+// этапа
+func (reporter *Reporter) SetDetails(details string) {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	reporter.details = details
+}
+
+// Finish выводит итоговое состояние текущего этапа и завершает строку хода выполнения
+func (reporter *Reporter) Finish() {
+	reporter.mutex.Lock()
+	defer reporter.mutex.Unlock()
+
+	if reporter.phase == "" {
+		return
+	}
+
+	reporter.print(reporter.now(), true)
+	reporter.phase = ""
+}
+
+func (reporter *Reporter) print(now time.Time, final bool) {
+	reporter.printed = now
+
+	line := Line(reporter.phase, reporter.done, reporter.total, now.Sub(reporter.phaseStarted), reporter.details)
+
+	if !reporter.tty {
+		if final {
+			return
+		}
+
+		fmt.Fprintln(reporter.writer, line)
+
+		return
+	}
+
+	padding := ""
+
+	if len(line) < reporter.width {
+		padding = strings.Repeat(" ", reporter.width-len(line))
+	}
+
+	reporter.width = len(line)
+
+	if final {
+		fmt.Fprintf(reporter.writer, "\r%s%s\n", line, padding)
+		reporter.width = 0
+
+		return
+	}
+
+	fmt.Fprintf(reporter.writer, "\r%s%s", line, padding)
+}
+
+// Line возвращает строку хода выполнения этапа phase с дополнительными сведениями details
+func Line(phase string, done, total int, elapsed time.Duration, details string) string {
+	if total <= 0 {
+		if done == 0 {
+			return phase + "..."
+		}
+
+		return fmt.Sprintf("%s: %d", phase, done)
+	}
+
+	line := fmt.Sprintf("%s: %d/%d (%d%%)", phase, done, total, done*100/total)
+
+	if details != "" {
+		line = fmt.Sprintf("%s, %s", line, details)
+	}
+
+	if done > 0 && done < total {
+		line = fmt.Sprintf("%s, ETA %s", line, ETA(elapsed, done, total))
+	}
+
+	return line
+}
+
+// ETA возвращает оценку оставшегося времени выполнения этапа, если за время elapsed обработано done элементов из
+// total
+func ETA(elapsed time.Duration, done, total int) time.Duration {
+	if done <= 0 || done >= total {
+		return 0
+	}
+
+	remaining := time.Duration(float64(elapsed) / float64(done) * float64(total-done))
+
+	return remaining.Round(time.Second)
+}
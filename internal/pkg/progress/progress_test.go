@@ -0,0 +1,98 @@
+package progress
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLine(t *testing.T) {
+	var cases = []struct {
+		phase   string
+		done    int
+		total   int
+		elapsed time.Duration
+		details string
+		want    string
+	}{
+		{phase: "metadata reading", done: 0, total: 0, want: "metadata reading..."},
+		{phase: "object enumeration", done: 120, total: 0, want: "object enumeration: 120"},
+		{phase: "rendering", done: 0, total: 200, want: "rendering: 0/200 (0%)"},
+		{phase: "rendering", done: 50, total: 200, elapsed: 10 * time.Second, want: "rendering: 50/200 (25%), ETA 30s"},
+		{phase: "writing", done: 200, total: 200, elapsed: time.Minute, want: "writing: 200/200 (100%)"},
+		{
+			phase:   "writing",
+			done:    20,
+			total:   200,
+			elapsed: 2 * time.Second,
+			details: "rendered 25",
+			want:    "writing: 20/200 (10%), rendered 25, ETA 18s",
+		},
+	}
+
+	for _, test := range cases {
+		if have := Line(test.phase, test.done, test.total, test.elapsed, test.details); have != test.want {
+			t.Errorf("Line(%s, %d, %d): have %q, want %q", test.phase, test.done, test.total, have, test.want)
+		}
+	}
+}
+
+func TestReporter(t *testing.T) {
+	var cases = []struct {
+		tty  bool
+		want string
+	}{
+		{
+			tty: false,
+			want: "rendering: 0/4 (0%)\n" +
+				"rendering: 2/4 (50%), ETA 10s\n" +
+				"rendering: 4/4 (100%)\n" +
+				"writing: 0/4 (0%)\n" +
+				"writing: 1/4 (25%), ETA 33s\n",
+		},
+		{
+			tty: true,
+			want: "\rrendering: 0/4 (0%)" +
+				"\rrendering: 2/4 (50%), ETA 10s" +
+				"\rrendering: 4/4 (100%)        " +
+				"\rrendering: 4/4 (100%)\n" +
+				"\rwriting: 0/4 (0%)" +
+				"\rwriting: 1/4 (25%), ETA 33s" +
+				"\rwriting: 1/4 (25%), ETA 33s\n",
+		},
+	}
+
+	for _, test := range cases {
+		var (
+			buffer bytes.Buffer
+			now    time.Time
+		)
+
+		reporter := New(&buffer, WithTTY(test.tty), WithInterval(10*time.Second), WithClock(func() time.Time {
+			return now
+		}))
+
+		reporter.Update("rendering", 0, 4)
+
+		now = now.Add(5 * time.Second)
+		reporter.Update("rendering", 1, 4)
+
+		now = now.Add(5 * time.Second)
+		reporter.Update("rendering", 2, 4)
+		reporter.Update("rendering", 3, 4)
+		reporter.Update("rendering", 4, 4)
+
+		reporter.Update("writing", 0, 4)
+
+		now = now.Add(time.Second)
+		reporter.Update("writing", 1, 4)
+		now = now.Add(10 * time.Second)
+		reporter.Update("writing", 1, 4)
+
+		reporter.Finish()
+
+		if buffer.String() != test.want {
+			t.Errorf("tty %v: have %q, want %q", test.tty, buffer.String(), test.want)
+		}
+	}
+}
@@ -0,0 +1,300 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"sort"
+	"time"
+)
+
+// Report отчет о выполнении команды создания скриптов
+type Report struct {
+	// Command наименование команды
+	Command string `json:"command"`
+	// Server сведения о сервере БД
+	Server Server `json:"server"`
+	// Database наименование базы данных
+	Database string `json:"database"`
+	// Output вид вывода скриптов: folder, script, tar, zip, stdout
+	Output string `json:"output"`
+	// Path путь к каталогу скриптов или к файлу вывода
+	Path string `json:"path,omitempty"`
+	// StartedAt время начала выполнения команды
+	StartedAt time.Time `json:"startedAt"`
+	// FinishedAt время завершения выполнения команды
+	FinishedAt time.Time `json:"finishedAt"`
+	// Duration продолжительность выполнения команды в секундах
+	Duration float64 `json:"duration"`
+	// Success признак успешного выполнения команды
+	Success bool `json:"success"`
+	// ExitCode код завершения приложения
+	ExitCode int `json:"exitCode"`
+	// Error ошибка, не относящаяся к конкретному объекту БД
+	Error string `json:"error,omitempty"`
+	// Objects количество созданных скриптов по типам объектов БД
+	Objects map[string]int `json:"objects"`
+	// Scripts объекты БД, скрипты которых созданы
+	Scripts []Object `json:"scripts"`
+	// Files файлы каталога скриптов или файл вывода
+	Files []File `json:"files"`
+	// Skipped объекты БД, скрипты которых не создавались
+	Skipped []Skipped `json:"skipped"`
+	// Failures объекты БД, скрипты которых не созданы или не сохранены из-за ошибки
+	Failures []Failure `json:"failures"`
+	// Warnings предупреждения
+	Warnings []string `json:"warnings"`
+	// Timings продолжительность этапов выполнения команды
+	Timings []Timing `json:"timings"`
+
+	phaseStarted []time.Time
+}
+
+// Server сведения о сервере БД
+type Server struct {
+	// Version версия сервера
+	Version string `json:"version"`
+	// Edition редакция сервера
+	Edition string `json:"edition"`
+}
+
+// Object объект БД
+type Object struct {
+	// Name наименование объекта БД
+	Name string `json:"name"`
+	// Type тип объекта БД
+	Type string `json:"type"`
+}
+
+// File файл со скриптами
+type File struct {
+	// Path путь к файлу
+	Path string `json:"path"`
+	// Status результат записи файла: created, updated, unchanged, deleted
+	Status string `json:"status"`
+	// SHA256 хэш содержимого файла
+	SHA256 string `json:"sha256,omitempty"`
+}
+
+// Skipped объект БД, скрипт которого не создавался
+type Skipped struct {
+	Object
+	// Reason причина: filtered, encrypted, unsupported
+	Reason string `json:"reason"`
+}
+
+// Failure объект БД, скрипт которого не создан или не сохранен из-за ошибки
+type Failure struct {
+	// Object наименование объекта БД
+	Object string `json:"object"`
+	// Error текст ошибки
+	Error string `json:"error"`
+}
+
+// Timing продолжительность этапа выполнения команды
+type Timing struct {
+	// Phase этап выполнения команды
+	Phase string `json:"phase"`
+	// Duration продолжительность этапа в секундах
+	Duration float64 `json:"duration"`
+}
+
+// New конструктор Report. startedAt - время начала выполнения команды command
+func New(command string, startedAt time.Time) *Report {
+	return &Report{
+		Command:   command,
+		StartedAt: startedAt,
+		Objects:   make(map[string]int),
+		Scripts:   make([]Object, 0),
+		Files:     make([]File, 0),
+		Skipped:   make([]Skipped, 0),
+		Failures:  make([]Failure, 0),
+		Warnings:  make([]string, 0),
+		Timings:   make([]Timing, 0),
+	}
+}
+
+// AddScript добавляет в отчет объект БД, скрипт которого создан
+func (report *Report) AddScript(name, objectType string) {
+	report.Objects[objectType]++
+	report.Scripts = append(report.Scripts, Object{Name: name, Type: objectType})
+}
+
+// AddSkipped добавляет в отчет объект БД, скрипт которого не создавался по причине reason
+func (report *Report) AddSkipped(name, objectType, reason string) {
+	report.Skipped = append(report.Skipped, Skipped{Object: Object{Name: name, Type: objectType}, Reason: reason})
+}
+
+// AddFailure добавляет в отчет объект БД, скрипт которого не создан или не сохранен из-за ошибки err
+func (report *Report) AddFailure(name string, err error) {
+	report.Failures = append(report.Failures, Failure{Object: name, Error: err.Error()})
+}
+
+// AddFile добавляет в отчет файл со скриптами
+func (report *Report) AddFile(path, status, sha256 string) {
+	report.Files = append(report.Files, File{Path: path, Status: status, SHA256: sha256})
+}
+
+// Mark отмечает событие этапа phase выполнения команды в момент at. Продолжительность этапа - время от первого до
+// последнего его события. Этапы могут выполняться одновременно (например, создание и сохранение скриптов), поэтому
+// сумма продолжительностей этапов может превышать продолжительность выполнения команды
+func (report *Report) Mark(phase string, at time.Time) {
+	for i, timing := range report.Timings {
+		if timing.Phase == phase {
+			report.Timings[i].Duration = seconds(at.Sub(report.phaseStarted[i]))
+			return
+		}
+	}
+
+	report.Timings = append(report.Timings, Timing{Phase: phase})
+	report.phaseStarted = append(report.phaseStarted, at)
+}
+
+// Finish завершает отчет: фиксирует время завершения команды и код завершения exitCode. err - ошибка, не
+// относящаяся к конкретному объекту БД
+func (report *Report) Finish(finishedAt time.Time, exitCode int, err error) {
+	report.FinishedAt = finishedAt
+	report.Duration = seconds(finishedAt.Sub(report.StartedAt))
+	report.ExitCode = exitCode
+	report.Success = exitCode == 0
+
+	if err != nil {
+		report.Error = err.Error()
+	}
+
+	// при параллельной записи скрипты добавляются в отчет в произвольном порядке
+	sort.SliceStable(report.Scripts, func(i, j int) bool {
+		return report.Scripts[i].Name < report.Scripts[j].Name
+	})
+
+	sort.SliceStable(report.Skipped, func(i, j int) bool {
+		return report.Skipped[i].Name < report.Skipped[j].Name
+	})
+
+	sort.SliceStable(report.Files, func(i, j int) bool {
+		return report.Files[i].Path < report.Files[j].Path
+	})
+}
+
+// WriteJSON записывает отчет в формате JSON
+func (report *Report) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(report)
+}
+
+func seconds(duration time.Duration) float64 {
+	return float64(duration.Milliseconds()) / 1000
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     float64          `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      float64         `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit записывает отчет в формате JUnit XML: каждый объект БД - отдельный тест, объекты с ошибками - упавшие
+// тесты, объекты, скрипты которых не создавались, - пропущенные тесты. Ошибка, не относящаяся к конкретному объекту
+// БД, записывается упавшим тестом с наименованием команды
+func (report *Report) WriteJUnit(writer io.Writer) error {
+	suite := junitTestSuite{
+		Name:      report.Database,
+		Time:      report.Duration,
+		Timestamp: report.StartedAt.UTC().Format("2006-01-02T15:04:05"),
+		Cases:     make([]junitTestCase, 0, len(report.Scripts)+len(report.Failures)+len(report.Skipped)+1),
+	}
+
+	for _, script := range report.Scripts {
+		suite.Cases = append(suite.Cases, junitTestCase{ClassName: script.Type, Name: script.Name})
+	}
+
+	for _, failure := range report.Failures {
+		suite.Cases = append(suite.Cases, junitTestCase{
+			ClassName: "failed",
+			Name:      failure.Object,
+			Failure:   &junitFailure{Message: failure.Error, Text: failure.Error},
+		})
+	}
+
+	if report.Error != "" {
+		suite.Cases = append(suite.Cases, junitTestCase{
+			ClassName: report.Command,
+			Name:      report.Command,
+			Failure:   &junitFailure{Message: report.Error, Text: report.Error},
+		})
+	}
+
+	for _, skipped := range report.Skipped {
+		suite.Cases = append(suite.Cases, junitTestCase{
+			ClassName: skipped.Type,
+			Name:      skipped.Name,
+			Skipped:   &junitSkipped{Message: skipped.Reason},
+		})
+	}
+
+	for _, testCase := range suite.Cases {
+		suite.Tests++
+
+		if testCase.Failure != nil {
+			suite.Failures++
+		}
+
+		if testCase.Skipped != nil {
+			suite.Skipped++
+		}
+	}
+
+	suites := junitTestSuites{
+		Name:     "dbmill " + report.Command,
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Skipped:  suite.Skipped,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
@@ -0,0 +1,151 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var started = time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)
+
+func testReport() *Report {
+	report := New("scriptsfolder", started)
+
+	report.Server = Server{Version: "Microsoft SQL Server 2019", Edition: "Enterprise"}
+	report.Database = "Orders"
+	report.Output = "folder"
+	report.Path = "scripts"
+
+	report.Mark("metadata reading", started)
+	report.Mark("metadata reading", started.Add(2*time.Second))
+	report.Mark("rendering", started.Add(2*time.Second))
+	report.Mark("writing", started.Add(2500*time.Millisecond))
+	report.Mark("rendering", started.Add(3*time.Second))
+	report.Mark("writing", started.Add(4*time.Second))
+
+	report.AddScript("[dbo].[PutOrder]", "procedure")
+	report.AddScript("[dbo].[GetOrders]", "procedure")
+	report.AddScript("[dbo].[Orders]", "table")
+
+	report.AddSkipped("[dbo].[Secret]", "procedure", "encrypted")
+	report.AddSkipped("[dbo].[Audit]", "table", "filtered")
+
+	report.AddFailure("[dbo].[Broken]", errors.New("invalid definition"))
+
+	report.AddFile("Procedures/dbo.PutOrder.sql", "created", "b2")
+	report.AddFile("Procedures/dbo.GetOrders.sql", "unchanged", "a1")
+
+	report.Warnings = append(report.Warnings, "[dbo].[Orders] cannot be scripted for 2016")
+
+	report.Finish(started.Add(5*time.Second), 3, nil)
+
+	return report
+}
+
+func TestReport_Finish(t *testing.T) {
+	report := testReport()
+
+	if report.Duration != 5 {
+		t.Errorf("duration: have %v, want 5", report.Duration)
+	}
+
+	if report.Success || report.ExitCode != 3 {
+		t.Errorf("have success %v and exit code %d, want false and 3", report.Success, report.ExitCode)
+	}
+
+	wantObjects := map[string]int{"procedure": 2, "table": 1}
+
+	if !reflect.DeepEqual(report.Objects, wantObjects) {
+		t.Errorf("objects: have %v, want %v", report.Objects, wantObjects)
+	}
+
+	wantTimings := []Timing{
+		{Phase: "metadata reading", Duration: 2},
+		{Phase: "rendering", Duration: 1},
+		{Phase: "writing", Duration: 1.5},
+	}
+
+	if !reflect.DeepEqual(report.Timings, wantTimings) {
+		t.Errorf("timings: have %v, want %v", report.Timings, wantTimings)
+	}
+
+	if report.Skipped[0].Name != "[dbo].[Audit]" || report.Files[0].Path != "Procedures/dbo.GetOrders.sql" {
+		t.Errorf("skipped objects and files are not sorted: %v, %v", report.Skipped, report.Files)
+	}
+
+	if report.Scripts[0].Name != "[dbo].[GetOrders]" || report.Scripts[2].Name != "[dbo].[PutOrder]" {
+		t.Errorf("scripts are not sorted: %v", report.Scripts)
+	}
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := testReport().WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	var have map[string]interface{}
+
+	if err := json.Unmarshal(buf.Bytes(), &have); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"server", "database", "objects", "files", "skipped", "failures", "warnings",
+		"timings", "startedAt", "finishedAt", "duration", "exitCode"} {
+		if _, ok := have[key]; !ok {
+			t.Errorf("key %s is missing in the JSON report", key)
+		}
+	}
+
+	if _, ok := have["error"]; ok {
+		t.Error("the JSON report contains an error, although there is no error")
+	}
+
+	skipped := have["skipped"].([]interface{})[0].(map[string]interface{})
+
+	if skipped["name"] != "[dbo].[Audit]" || skipped["type"] != "table" || skipped["reason"] != "filtered" {
+		t.Errorf("unexpected skipped object %v", skipped)
+	}
+}
+
+func TestReport_WriteJUnit(t *testing.T) {
+	report := New("scriptsfolder", started)
+	report.Database = "Orders"
+
+	report.AddScript("[dbo].[GetOrders]", "procedure")
+	report.AddSkipped("[dbo].[Secret]", "procedure", "encrypted")
+	report.AddFailure("[dbo].[Broken]", errors.New("invalid definition"))
+
+	report.Finish(started.Add(1500*time.Millisecond), 1, errors.New("connection lost"))
+
+	var buf bytes.Buffer
+
+	if err := report.WriteJUnit(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="dbmill scriptsfolder" tests="4" failures="2" skipped="1" time="1.5">
+  <testsuite name="Orders" tests="4" failures="2" skipped="1" time="1.5" timestamp="2026-10-19T10:00:00">
+    <testcase classname="procedure" name="[dbo].[GetOrders]"></testcase>
+    <testcase classname="failed" name="[dbo].[Broken]">
+      <failure message="invalid definition">invalid definition</failure>
+    </testcase>
+    <testcase classname="scriptsfolder" name="scriptsfolder">
+      <failure message="connection lost">connection lost</failure>
+    </testcase>
+    <testcase classname="procedure" name="[dbo].[Secret]">
+      <skipped message="encrypted"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+
+	if have := buf.String(); have != want {
+		t.Errorf("have:\n%s\nwant:\n%s", have, want)
+	}
+}
@@ -0,0 +1,92 @@
+package state
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/vitpelekhaty/dbmill-cli/internal/pkg/output"
+)
+
+// Filename наименование файла состояния, который по умолчанию создается в рабочем каталоге
+const Filename = "dbmill.state.json"
+
+// TimeLayout формат времени сервера БД в файле состояния. Время сервера хранится без часового пояса, как и время
+// изменения объектов БД
+const TimeLayout = "2006-01-02T15:04:05.999"
+
+// State состояние выгрузок: сведения о последних успешных запусках команды для каждой БД и каталога скриптов
+type State struct {
+	// Runs последние успешные запуски. Ключ - БД и путь к скриптам (см. Key)
+	Runs map[string]Run `json:"runs"`
+}
+
+// Run сведения об успешном запуске команды
+type Run struct {
+	// ServerTime время сервера БД на момент начала выполнения команды в формате TimeLayout
+	ServerTime string `json:"serverTime"`
+	// FinishedAt время завершения выполнения команды
+	FinishedAt time.Time `json:"finishedAt"`
+}
+
+// Since возвращает время сервера БД на момент начала выполнения команды
+func (run Run) Since() (time.Time, error) {
+	return time.Parse(TimeLayout, run.ServerTime)
+}
+
+// Key возвращает ключ запуска команды для БД database сервера server (host:port) и приемника скриптов sink (вид
+// вывода и путь)
+func Key(server, database, sink string) string {
+	return server + "/" + database + " " + sink
+}
+
+// Load читает состояние из файла path. Если файла нет, то возвращается пустое состояние
+func Load(path string) (*State, error) {
+	data, err := ioutil.ReadFile(path)
+
+	if os.IsNotExist(err) {
+		return &State{Runs: make(map[string]Run)}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var state State
+
+	if err = json.Unmarshal(data, &state); err != nil {
+		return nil, err
+	}
+
+	if state.Runs == nil {
+		state.Runs = make(map[string]Run)
+	}
+
+	return &state, nil
+}
+
+// Last возвращает последний успешный запуск команды с ключом key
+func (state *State) Last(key string) (Run, bool) {
+	run, ok := state.Runs[key]
+	return run, ok
+}
+
+// Set запоминает успешный запуск команды с ключом key, начатый при времени сервера БД serverTime
+func (state *State) Set(key string, serverTime, finishedAt time.Time) {
+	state.Runs[key] = Run{
+		ServerTime: serverTime.Format(TimeLayout),
+		FinishedAt: finishedAt,
+	}
+}
+
+// Save записывает состояние в файл path. Файл заменяется только после успешной записи
+func (state *State) Save(path string) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return output.WriteFileAtomic(path, append(data, '\n'))
+}
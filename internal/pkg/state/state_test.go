@@ -0,0 +1,63 @@
+package state
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbmill-state")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, Filename)
+
+	state, err := Load(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := Key("localhost:1433", "Sales", "folder:/var/scripts")
+
+	if _, ok := state.Last(key); ok {
+		t.Fatalf("the run %s is found in the empty state", key)
+	}
+
+	serverTime := time.Date(2026, 10, 1, 22, 30, 15, 123000000, time.UTC)
+
+	state.Set(key, serverTime, time.Now())
+
+	if err = state.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	state, err = Load(path)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run, ok := state.Last(key)
+
+	if !ok {
+		t.Fatalf("the run %s is not found", key)
+	}
+
+	since, err := run.Since()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !since.Equal(serverTime) {
+		t.Errorf("have since %v, want %v", since, serverTime)
+	}
+}
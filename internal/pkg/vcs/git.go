@@ -0,0 +1,255 @@
+package vcs
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ErrorNothingToCommit ошибка "Нет изменений для фиксации"
+var ErrorNothingToCommit = errors.New("nothing to commit")
+
+// ErrorStagedOutside ошибка "В индексе есть изменения за пределами каталога скриптов"
+var ErrorStagedOutside = errors.New("there are staged changes outside the scripts folder")
+
+// Changes изменения в каталоге скриптов. Пути к файлам указываются относительно каталога скриптов
+type Changes struct {
+	// Added добавленные файлы
+	Added []string
+	// Modified измененные файлы
+	Modified []string
+	// Removed удаленные файлы
+	Removed []string
+}
+
+// Empty проверяет отсутствие изменений
+func (changes Changes) Empty() bool {
+	return len(changes.Added) == 0 && len(changes.Modified) == 0 && len(changes.Removed) == 0
+}
+
+// Signature автор коммита
+type Signature struct {
+	// Name имя автора
+	Name string
+	// Email адрес электронной почты автора
+	Email string
+}
+
+// CommitOption опция фиксации изменений
+type CommitOption func(committer *committer)
+
+// WithAuthor указывает автора коммита. По умолчанию используется автор из конфигурации git (user.name, user.email)
+func WithAuthor(author Signature) CommitOption {
+	return func(committer *committer) {
+		committer.author = author
+	}
+}
+
+// WithSubject указывает заголовок сообщения коммита
+func WithSubject(subject string) CommitOption {
+	return func(committer *committer) {
+		committer.subject = subject
+	}
+}
+
+type committer struct {
+	author  Signature
+	subject string
+}
+
+// defaultSubject заголовок сообщения коммита по умолчанию
+const defaultSubject = "Update database scripts"
+
+// Commit добавляет в индекс все изменения в каталоге скриптов path, находящемся в рабочем каталоге git репозитория,
+// и фиксирует их. Сообщение коммита содержит списки добавленных, измененных и удаленных скриптов. Если изменений нет,
+// то коммит не создается и возвращается ошибка ErrorNothingToCommit
+func Commit(path string, options ...CommitOption) (hash string, changes Changes, err error) {
+	c := &committer{subject: defaultSubject}
+
+	for _, option := range options {
+		option(c)
+	}
+
+	absPath, err := filepath.Abs(path)
+
+	if err != nil {
+		return "", changes, err
+	}
+
+	repository, err := git.PlainOpenWithOptions(absPath, &git.PlainOpenOptions{DetectDotGit: true})
+
+	if err != nil {
+		return "", changes, fmt.Errorf("failed to open git repository %s: %v", path, err)
+	}
+
+	worktree, err := repository.Worktree()
+
+	if err != nil {
+		return "", changes, err
+	}
+
+	prefix, err := filepath.Rel(worktree.Filesystem.Root(), absPath)
+
+	if err != nil {
+		return "", changes, err
+	}
+
+	prefix = filepath.ToSlash(prefix)
+
+	status, err := worktree.Status()
+
+	if err != nil {
+		return "", changes, err
+	}
+
+	for _, file := range sortedFiles(status) {
+		fileStatus := status[file]
+
+		if !inFolder(prefix, file) {
+			if fileStatus.Staging != git.Unmodified && fileStatus.Staging != git.Untracked {
+				return "", changes, ErrorStagedOutside
+			}
+
+			continue
+		}
+
+		switch {
+		case fileStatus.Worktree == git.Deleted:
+			_, err = worktree.Remove(file)
+		case fileStatus.Worktree != git.Unmodified:
+			_, err = worktree.Add(file)
+		}
+
+		if err != nil {
+			return "", changes, err
+		}
+	}
+
+	if status, err = worktree.Status(); err != nil {
+		return "", changes, err
+	}
+
+	for _, file := range sortedFiles(status) {
+		if !inFolder(prefix, file) {
+			continue
+		}
+
+		name := relative(prefix, file)
+
+		switch status[file].Staging {
+		case git.Added, git.Copied:
+			changes.Added = append(changes.Added, name)
+		case git.Modified, git.Renamed:
+			changes.Modified = append(changes.Modified, name)
+		case git.Deleted:
+			changes.Removed = append(changes.Removed, name)
+		}
+	}
+
+	if changes.Empty() {
+		return "", changes, ErrorNothingToCommit
+	}
+
+	author, err := c.signature(repository)
+
+	if err != nil {
+		return "", changes, err
+	}
+
+	commit, err := worktree.Commit(Message(c.subject, changes), &git.CommitOptions{Author: author})
+
+	if err != nil {
+		return "", changes, err
+	}
+
+	return commit.String(), changes, nil
+}
+
+// Message возвращает сообщение коммита с заголовком subject и списками изменений changes
+func Message(subject string, changes Changes) string {
+	var builder strings.Builder
+
+	builder.WriteString(subject)
+
+	sections := []struct {
+		title string
+		files []string
+	}{
+		{title: "Added", files: changes.Added},
+		{title: "Modified", files: changes.Modified},
+		{title: "Removed", files: changes.Removed},
+	}
+
+	for _, section := range sections {
+		if len(section.files) == 0 {
+			continue
+		}
+
+		builder.WriteString(fmt.Sprintf("\n\n%s (%d):", section.title, len(section.files)))
+
+		for _, file := range section.files {
+			builder.WriteString("\n  " + file)
+		}
+	}
+
+	builder.WriteString("\n")
+
+	return builder.String()
+}
+
+func (c *committer) signature(repository *git.Repository) (*object.Signature, error) {
+	author := c.author
+
+	if strings.Trim(author.Name, " ") == "" || strings.Trim(author.Email, " ") == "" {
+		cfg, err := repository.ConfigScoped(config.SystemScope)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if strings.Trim(author.Name, " ") == "" {
+			author.Name = cfg.User.Name
+		}
+
+		if strings.Trim(author.Email, " ") == "" {
+			author.Email = cfg.User.Email
+		}
+	}
+
+	if strings.Trim(author.Name, " ") == "" {
+		return nil, errors.New("commit author is not specified")
+	}
+
+	return &object.Signature{Name: author.Name, Email: author.Email, When: time.Now()}, nil
+}
+
+func sortedFiles(status git.Status) []string {
+	files := make([]string, 0, len(status))
+
+	for file := range status {
+		files = append(files, file)
+	}
+
+	sort.Strings(files)
+
+	return files
+}
+
+func inFolder(prefix, file string) bool {
+	return prefix == "." || file == prefix || strings.HasPrefix(file, prefix+"/")
+}
+
+func relative(prefix, file string) string {
+	if prefix == "." {
+		return file
+	}
+
+	return strings.TrimPrefix(file, prefix+"/")
+}
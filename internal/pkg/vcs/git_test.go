@@ -0,0 +1,124 @@
+package vcs
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+)
+
+var testAuthor = Signature{Name: "dbmill", Email: "dbmill@localhost"}
+
+func writeFile(t *testing.T, path, content string) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte(content), 0664); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCommit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dbmill-vcs")
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+
+	repository, err := git.PlainInit(dir, false)
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scripts := filepath.Join(dir, "scripts")
+
+	writeFile(t, filepath.Join(scripts, "Views", "dbo.Orders.sql"), "CREATE VIEW [dbo].[Orders] AS SELECT 1 AS [one]")
+	writeFile(t, filepath.Join(scripts, "Views", "dbo.Customers.sql"),
+		"CREATE VIEW [dbo].[Customers] AS SELECT 1 AS [one]")
+	writeFile(t, filepath.Join(dir, "notes.txt"), "not a script")
+
+	_, changes, err := Commit(scripts, WithAuthor(testAuthor))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Changes{Added: []string{"Views/dbo.Customers.sql", "Views/dbo.Orders.sql"}}
+
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Commit failed: have %+v, want %+v", changes, want)
+	}
+
+	writeFile(t, filepath.Join(scripts, "Views", "dbo.Orders.sql"), "CREATE VIEW [dbo].[Orders] AS SELECT 2 AS [two]")
+	writeFile(t, filepath.Join(scripts, "Procedures", "dbo.GetOrders.sql"),
+		"CREATE PROCEDURE [dbo].[GetOrders] AS RETURN")
+
+	if err = os.Remove(filepath.Join(scripts, "Views", "dbo.Customers.sql")); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, changes, err := Commit(scripts, WithAuthor(testAuthor), WithSubject("Nightly export"))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want = Changes{
+		Added:    []string{"Procedures/dbo.GetOrders.sql"},
+		Modified: []string{"Views/dbo.Orders.sql"},
+		Removed:  []string{"Views/dbo.Customers.sql"},
+	}
+
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Commit failed: have %+v, want %+v", changes, want)
+	}
+
+	head, err := repository.Head()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if head.Hash().String() != hash {
+		t.Errorf("Commit failed: HEAD %s, want %s", head.Hash(), hash)
+	}
+
+	if _, _, err = Commit(scripts, WithAuthor(testAuthor)); err != ErrorNothingToCommit {
+		t.Errorf("Commit without changes failed: %v", err)
+	}
+
+	worktree, err := repository.Worktree()
+
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = worktree.Add("notes.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = Commit(scripts, WithAuthor(testAuthor)); err != ErrorStagedOutside {
+		t.Errorf("Commit with staged changes outside the scripts folder failed: %v", err)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	have := Message("Update database scripts", Changes{
+		Added:   []string{"Views/dbo.Orders.sql"},
+		Removed: []string{"Views/dbo.Customers.sql", "Views/dbo.Products.sql"},
+	})
+
+	want := "Update database scripts\n\nAdded (1):\n  Views/dbo.Orders.sql\n\n" +
+		"Removed (2):\n  Views/dbo.Customers.sql\n  Views/dbo.Products.sql\n"
+
+	if have != want {
+		t.Errorf("Message failed: have %q, want %q", have, want)
+	}
+}
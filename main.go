@@ -2,12 +2,14 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/vitpelekhaty/dbmill-cli/cmd/commands"
 )
 
 func main() {
 	if err := commands.Execute(); err != nil {
-		log.Fatal(err)
+		log.Println(err)
+		os.Exit(commands.ExitCode(err))
 	}
 }